@@ -1,578 +1,3647 @@
-// Package docs GENERATED BY SWAG; EDIT AT YOUR OWN RISK
-// This file was generated by swaggo/swag at
-// 2023-12-15 14:30:00.123456789 +0000 UTC
+// Package docs Code generated by swaggo/swag. DO NOT EDIT
 package docs
 
 import "github.com/swaggo/swag"
 
 const docTemplate = `{
-    "openapi": "3.0.1",
+    "schemes": {{ marshal .Schemes }},
+    "swagger": "2.0",
     "info": {
-        "title": "API Catalog Service",
-        "description": "API for managing events, properties, and tracking plans",
-        "version": "1.0.0"
+        "description": "{{escape .Description}}",
+        "title": "{{.Title}}",
+        "contact": {},
+        "version": "{{.Version}}"
     },
-    "servers": [
-        {
-            "url": "http://localhost:8080",
-            "description": "Development server"
-        }
-    ],
-    "tags": [
-        {
-            "name": "events",
-            "description": "Operations related to events"
-        },
-        {
-            "name": "properties",
-            "description": "Operations related to properties"
-        },
-        {
-            "name": "tracking-plans",
-            "description": "Operations related to tracking plans"
-        },
-        {
-            "name": "health",
-            "description": "Health check endpoints"
-        }
-    ],
+    "host": "{{.Host}}",
+    "basePath": "{{.BasePath}}",
     "paths": {
-        "/health": {
+        "/api/v1/audit": {
             "get": {
-                "tags": ["health"],
-                "summary": "Health check",
-                "description": "Returns the health status of the service",
+                "description": "Retrieve a page of the audit log of catalog changes, newest first. Each entry names the actor, the client, the action and the resource before and after the change.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "audit"
+                ],
+                "summary": "List audit log entries",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Page size (default 50, max 200)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor returned as next_cursor by the previous page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only return changes to this kind of resource: event, property or tracking_plan",
+                        "name": "resource",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Only return changes to the resource with this ID",
+                        "name": "resource_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only return changes made by this user ID or email",
+                        "name": "actor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only return this action: create, update, delete or restore",
+                        "name": "action",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Only return changes made at or after this unix time",
+                        "name": "created_after",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Only return changes made before this unix time",
+                        "name": "created_before",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Sort order: desc (default) or asc",
+                        "name": "order",
+                        "in": "query"
+                    }
+                ],
                 "responses": {
                     "200": {
-                        "description": "Service is healthy",
-                        "content": {
-                            "application/json": {
-                                "schema": {
-                                    "type": "object",
-                                    "properties": {
-                                        "status": {"type": "string", "example": "healthy"},
-                                        "service": {"type": "string", "example": "datacatalog"},
-                                        "version": {"type": "string", "example": "1.0.0"}
-                                    }
-                                }
-                            }
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.PageResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/fiber.Map"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/fiber.Map"
                         }
                     }
                 }
             }
         },
-        "/api/v1/events": {
+        "/api/v1/clients": {
+            "get": {
+                "description": "Retrieve every registered API client, including revoked and expired ones. Secrets are never returned.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "clients"
+                ],
+                "summary": "List API clients",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.APIClient"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/fiber.Map"
+                        }
+                    }
+                }
+            },
             "post": {
-                "tags": ["events"],
-                "summary": "Create a new event",
-                "description": "Create a new event with name, type, and description",
-                "requestBody": {
-                    "required": true,
-                    "content": {
-                        "application/json": {
-                            "schema": {"$ref": "#/components/schemas/CreateEventRequest"}
+                "description": "Create an API client with a generated secret. Scopes list the roles the client's users may act with (viewer, editor, admin), plus clients to manage API clients. The secret is only returned in this response.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "clients"
+                ],
+                "summary": "Register an API client",
+                "parameters": [
+                    {
+                        "description": "Client to create",
+                        "name": "client",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dtos.CreateAPIClientRequest"
                         }
                     }
-                },
+                ],
                 "responses": {
                     "201": {
-                        "description": "Event created successfully",
-                        "content": {
-                            "application/json": {
-                                "schema": {"$ref": "#/components/schemas/Event"}
-                            }
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.APIClientCredentials"
                         }
                     },
-                    "400": {"description": "Invalid input"},
-                    "500": {"description": "Internal server error"}
-                }
-            },
-            "get": {
-                "tags": ["events"],
-                "summary": "Get all events",
-                "description": "Retrieve a list of all events",
-                "responses": {
-                    "200": {
-                        "description": "List of events",
-                        "content": {
-                            "application/json": {
-                                "schema": {
-                                    "type": "array",
-                                    "items": {"$ref": "#/components/schemas/Event"}
-                                }
-                            }
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/fiber.Map"
                         }
                     },
-                    "500": {"description": "Internal server error"}
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/fiber.Map"
+                        }
+                    }
                 }
             }
         },
-        "/api/v1/events/{id}": {
-            "get": {
-                "tags": ["events"],
-                "summary": "Get event by ID",
-                "description": "Retrieve a single event by its ID",
-                "parameters": [{
-                    "name": "id",
-                    "in": "path",
-                    "required": true,
-                    "schema": {"type": "integer"}
-                }],
-                "responses": {
-                    "200": {
-                        "description": "Event details",
-                        "content": {
-                            "application/json": {
-                                "schema": {"$ref": "#/components/schemas/Event"}
-                            }
+        "/api/v1/clients/{id}/revoke": {
+            "post": {
+                "description": "Permanently stop an API client from authenticating. Revoking a revoked client is a no-op.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "clients"
+                ],
+                "summary": "Revoke an API client",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Client ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIClient"
                         }
                     },
-                    "400": {"description": "Invalid ID"},
-                    "404": {"description": "Event not found"},
-                    "500": {"description": "Internal server error"}
-                }
-            },
-            "put": {
-                "tags": ["events"],
-                "summary": "Update an event",
-                "description": "Update an event by its ID",
-                "parameters": [{
-                    "name": "id",
-                    "in": "path",
-                    "required": true,
-                    "schema": {"type": "integer"}
-                }],
-                "requestBody": {
-                    "required": true,
-                    "content": {
-                        "application/json": {
-                            "schema": {"$ref": "#/components/schemas/UpdateEventRequest"}
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/fiber.Map"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/fiber.Map"
                         }
                     }
-                },
+                }
+            }
+        },
+        "/api/v1/clients/{id}/rotate": {
+            "post": {
+                "description": "Replace the secret of an API client. The old secret stops working immediately and the new one is only returned in this response.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "clients"
+                ],
+                "summary": "Rotate an API client secret",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Client ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
                 "responses": {
                     "200": {
-                        "description": "Updated event",
-                        "content": {
-                            "application/json": {
-                                "schema": {"$ref": "#/components/schemas/Event"}
-                            }
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.APIClientCredentials"
                         }
                     },
-                    "400": {"description": "Invalid input"},
-                    "404": {"description": "Event not found"},
-                    "500": {"description": "Internal server error"}
-                }
-            },
-            "delete": {
-                "tags": ["events"],
-                "summary": "Delete an event",
-                "description": "Delete an event by its ID",
-                "parameters": [{
-                    "name": "id",
-                    "in": "path",
-                    "required": true,
-                    "schema": {"type": "integer"}
-                }],
-                "responses": {
-                    "204": {"description": "Event deleted successfully"},
-                    "400": {"description": "Invalid ID"},
-                    "404": {"description": "Event not found"},
-                    "500": {"description": "Internal server error"}
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/fiber.Map"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/fiber.Map"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/fiber.Map"
+                        }
+                    }
                 }
             }
         },
-        "/api/v1/properties": {
+        "/api/v1/events": {
+            "get": {
+                "description": "Retrieve a page of events with optional filters and sorting",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "events"
+                ],
+                "summary": "List events",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Page size (default 50, max 200)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor returned as next_cursor by the previous page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only return items of this type",
+                        "name": "type",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only return items whose name starts with this prefix",
+                        "name": "name_prefix",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Only return items created at or after this unix time",
+                        "name": "created_after",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Only return items created before this unix time",
+                        "name": "created_before",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Only return items updated at or after this unix time",
+                        "name": "updated_after",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Only return items updated before this unix time",
+                        "name": "updated_before",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Sort field: id, name, create_time or update_time",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Sort order: asc or desc",
+                        "name": "order",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Also return soft-deleted items",
+                        "name": "include_deleted",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.PageResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/fiber.Map"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/fiber.Map"
+                        }
+                    }
+                }
+            },
             "post": {
-                "tags": ["properties"],
-                "summary": "Create a new property",
-                "description": "Create a new property with name, type, and description",
-                "requestBody": {
-                    "required": true,
-                    "content": {
-                        "application/json": {
-                            "schema": {"$ref": "#/components/schemas/CreatePropertyRequest"}
+                "description": "Create a new event with name, type, and description",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "events"
+                ],
+                "summary": "Create a new event",
+                "parameters": [
+                    {
+                        "description": "Event to create",
+                        "name": "event",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dtos.CreateEventRequest"
                         }
                     }
-                },
+                ],
                 "responses": {
                     "201": {
-                        "description": "Property created successfully",
-                        "content": {
-                            "application/json": {
-                                "schema": {"$ref": "#/components/schemas/Property"}
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.Event"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Current version of the resource"
                             }
                         }
                     },
-                    "400": {"description": "Invalid input"},
-                    "500": {"description": "Internal server error"}
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/fiber.Map"
+                        }
+                    }
                 }
-            },
-            "get": {
-                "tags": ["properties"],
-                "summary": "Get all properties",
-                "description": "Retrieve a list of all properties",
-                "responses": {
-                    "200": {
-                        "description": "List of properties",
-                        "content": {
-                            "application/json": {
-                                "schema": {
-                                    "type": "array",
-                                    "items": {"$ref": "#/components/schemas/Property"}
-                                }
-                            }
+            }
+        },
+        "/api/v1/events/bulk": {
+            "post": {
+                "description": "Create many events from an NDJSON or CSV upload, sent as the request body or as the \"file\" field of a multipart form. CSV uploads start with a header row naming the columns (name, type, description). Every row is validated like a single create, and the response reports per row whether it was created, already existed or failed. In atomic mode (the default) nothing is written unless every row succeeds; in best_effort mode each valid row is saved on its own.",
+                "consumes": [
+                    "application/x-ndjson",
+                    "text/csv",
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "events"
+                ],
+                "summary": "Bulk import events",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "atomic or best_effort",
+                        "name": "mode",
+                        "in": "query"
+                    },
+                    {
+                        "type": "file",
+                        "description": "NDJSON or CSV file",
+                        "name": "file",
+                        "in": "formData"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.BulkImportResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/fiber.Map"
+                        }
+                    },
+                    "415": {
+                        "description": "Unsupported Media Type",
+                        "schema": {
+                            "$ref": "#/definitions/fiber.Map"
                         }
                     },
-                    "500": {"description": "Internal server error"}
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.BulkImportResponse"
+                        }
+                    }
                 }
             }
         },
-        "/api/v1/properties/{id}": {
+        "/api/v1/events/{id}": {
             "get": {
-                "tags": ["properties"],
-                "summary": "Get property by ID",
-                "description": "Retrieve a single property by its ID",
-                "parameters": [{
-                    "name": "id",
-                    "in": "path",
-                    "required": true,
-                    "schema": {"type": "integer"}
-                }],
-                "responses": {
-                    "200": {
-                        "description": "Property details",
-                        "content": {
-                            "application/json": {
-                                "schema": {"$ref": "#/components/schemas/Property"}
+                "description": "Retrieve a single event by its ID",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "events"
+                ],
+                "summary": "Get event by ID",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Event ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Event"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Current version of the resource"
                             }
                         }
                     },
-                    "400": {"description": "Invalid ID"},
-                    "404": {"description": "Property not found"},
-                    "500": {"description": "Internal server error"}
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/fiber.Map"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/fiber.Map"
+                        }
+                    }
                 }
             },
             "put": {
-                "tags": ["properties"],
-                "summary": "Update a property",
-                "description": "Update a property by its ID",
-                "parameters": [{
-                    "name": "id",
-                    "in": "path",
-                    "required": true,
-                    "schema": {"type": "integer"}
-                }],
-                "requestBody": {
-                    "required": true,
-                    "content": {
-                        "application/json": {
-                            "schema": {"$ref": "#/components/schemas/UpdatePropertyRequest"}
+                "description": "Update an event by its ID",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "events"
+                ],
+                "summary": "Update an event",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Event ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Event update payload",
+                        "name": "event",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dtos.UpdateEventRequest"
                         }
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag the update is based on",
+                        "name": "If-Match",
+                        "in": "header"
                     }
-                },
+                ],
                 "responses": {
                     "200": {
-                        "description": "Updated property",
-                        "content": {
-                            "application/json": {
-                                "schema": {"$ref": "#/components/schemas/Property"}
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Event"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Current version of the resource"
                             }
                         }
                     },
-                    "400": {"description": "Invalid input"},
-                    "404": {"description": "Property not found"},
-                    "500": {"description": "Internal server error"}
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/fiber.Map"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/fiber.Map"
+                        }
+                    },
+                    "412": {
+                        "description": "Precondition Failed",
+                        "schema": {
+                            "$ref": "#/definitions/fiber.Map"
+                        }
+                    }
                 }
             },
             "delete": {
-                "tags": ["properties"],
-                "summary": "Delete a property",
-                "description": "Delete a property by its ID",
-                "parameters": [{
-                    "name": "id",
-                    "in": "path",
-                    "required": true,
-                    "schema": {"type": "integer"}
-                }],
+                "description": "Soft delete an event by its ID. Returns 409 listing the referencing tracking plans while tracking plans still include it, unless cascade=true detaches it from them.",
+                "tags": [
+                    "events"
+                ],
+                "summary": "Delete an event",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Event ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Detach the event from referencing tracking plans",
+                        "name": "cascade",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag the delete is based on",
+                        "name": "If-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/fiber.Map"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/fiber.Map"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/fiber.Map"
+                        }
+                    },
+                    "412": {
+                        "description": "Precondition Failed",
+                        "schema": {
+                            "$ref": "#/definitions/fiber.Map"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "description": "Partially update an event with an RFC 7396 merge patch or an RFC 6902 JSON Patch. The patch applies to the update request form of the event, and the result is validated like a PUT.",
+                "consumes": [
+                    "application/merge-patch+json",
+                    "application/json-patch+json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "events"
+                ],
+                "summary": "Patch an event",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Event ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Merge patch or JSON Patch document",
+                        "name": "patch",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "object"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag the patch is based on",
+                        "name": "If-Match",
+                        "in": "header"
+                    }
+                ],
                 "responses": {
-                    "204": {"description": "Property deleted successfully"},
-                    "400": {"description": "Invalid ID"},
-                    "404": {"description": "Property not found"},
-                    "500": {"description": "Internal server error"}
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Event"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Current version of the resource"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/fiber.Map"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/fiber.Map"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/fiber.Map"
+                        }
+                    },
+                    "412": {
+                        "description": "Precondition Failed",
+                        "schema": {
+                            "$ref": "#/definitions/fiber.Map"
+                        }
+                    },
+                    "415": {
+                        "description": "Unsupported Media Type",
+                        "schema": {
+                            "$ref": "#/definitions/fiber.Map"
+                        }
+                    }
                 }
             }
         },
-        "/api/v1/tracking-plans": {
+        "/api/v1/events/{id}/restore": {
             "post": {
-                "tags": ["tracking-plans"],
-                "summary": "Create a new tracking plan",
-                "description": "Create a new tracking plan with events and properties",
-                "requestBody": {
-                    "required": true,
-                    "content": {
-                        "application/json": {
-                            "schema": {"$ref": "#/components/schemas/CreateTrackingPlanRequest"}
-                        }
+                "description": "Bring back a soft-deleted event",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "events"
+                ],
+                "summary": "Restore an event",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Event ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     }
-                },
+                ],
                 "responses": {
-                    "201": {
-                        "description": "Tracking plan created successfully",
-                        "content": {
-                            "application/json": {
-                                "schema": {"$ref": "#/components/schemas/TrackingPlan"}
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Event"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Current version of the resource"
                             }
                         }
                     },
-                    "400": {"description": "Invalid input"},
-                    "500": {"description": "Internal server error"}
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/fiber.Map"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/fiber.Map"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/fiber.Map"
+                        }
+                    }
                 }
-            },
+            }
+        },
+        "/api/v1/events/{id}/usages": {
             "get": {
-                "tags": ["tracking-plans"],
-                "summary": "Get all tracking plans",
-                "description": "Retrieve a list of all tracking plans",
-                "responses": {
-                    "200": {
-                        "description": "List of tracking plans",
-                        "content": {
-                            "application/json": {
-                                "schema": {
-                                    "type": "array",
-                                    "items": {"$ref": "#/components/schemas/TrackingPlan"}
-                                }
+                "description": "List every tracking plan that uses the event, with the per-plan event and additionalProperties setting",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "events"
+                ],
+                "summary": "List where an event is used",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Event ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Usage"
                             }
                         }
                     },
-                    "500": {"description": "Internal server error"}
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/fiber.Map"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/fiber.Map"
+                        }
+                    }
                 }
             }
         },
-        "/api/v1/tracking-plans/{id}": {
+        "/api/v1/properties": {
             "get": {
-                "tags": ["tracking-plans"],
-                "summary": "Get tracking plan by ID",
-                "description": "Retrieve a single tracking plan by its ID",
-                "parameters": [{
-                    "name": "id",
-                    "in": "path",
-                    "required": true,
-                    "schema": {"type": "integer"}
-                }],
-                "responses": {
-                    "200": {
-                        "description": "Tracking plan details",
-                        "content": {
-                            "application/json": {
-                                "schema": {"$ref": "#/components/schemas/TrackingPlan"}
-                            }
+                "description": "Retrieve a page of properties with optional filters and sorting",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "properties"
+                ],
+                "summary": "List properties",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Page size (default 50, max 200)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor returned as next_cursor by the previous page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only return items of this type",
+                        "name": "type",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only return items whose name starts with this prefix",
+                        "name": "name_prefix",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Only return items created at or after this unix time",
+                        "name": "created_after",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Only return items created before this unix time",
+                        "name": "created_before",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Only return items updated at or after this unix time",
+                        "name": "updated_after",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Only return items updated before this unix time",
+                        "name": "updated_before",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Sort field: id, name, create_time or update_time",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Sort order: asc or desc",
+                        "name": "order",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Also return soft-deleted items",
+                        "name": "include_deleted",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.PageResponse"
                         }
                     },
-                    "400": {"description": "Invalid ID"},
-                    "404": {"description": "Tracking plan not found"},
-                    "500": {"description": "Internal server error"}
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/fiber.Map"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/fiber.Map"
+                        }
+                    }
                 }
             },
-            "put": {
-                "tags": ["tracking-plans"],
-                "summary": "Update a tracking plan",
-                "description": "Update a tracking plan by its ID",
-                "parameters": [{
-                    "name": "id",
-                    "in": "path",
-                    "required": true,
-                    "schema": {"type": "integer"}
-                }],
-                "requestBody": {
-                    "required": true,
-                    "content": {
-                        "application/json": {
-                            "schema": {"$ref": "#/components/schemas/UpdateTrackingPlanRequest"}
+            "post": {
+                "description": "Create a new property with name, type, and description",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "properties"
+                ],
+                "summary": "Create a new property",
+                "parameters": [
+                    {
+                        "description": "Property to create",
+                        "name": "property",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dtos.CreatePropertyRequest"
                         }
                     }
-                },
+                ],
                 "responses": {
-                    "200": {
-                        "description": "Updated tracking plan",
-                        "content": {
-                            "application/json": {
-                                "schema": {"$ref": "#/components/schemas/TrackingPlan"}
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.Property"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Current version of the resource"
                             }
                         }
                     },
-                    "400": {"description": "Invalid input"},
-                    "404": {"description": "Tracking plan not found"},
-                    "500": {"description": "Internal server error"}
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/fiber.Map"
+                        }
+                    }
                 }
-            },
-            "delete": {
-                "tags": ["tracking-plans"],
-                "summary": "Delete a tracking plan",
-                "description": "Delete a tracking plan by its ID",
-                "parameters": [{
-                    "name": "id",
-                    "in": "path",
-                    "required": true,
-                    "schema": {"type": "integer"}
-                }],
+            }
+        },
+        "/api/v1/properties/bulk": {
+            "post": {
+                "description": "Create many properties from an NDJSON or CSV upload, sent as the request body or as the \"file\" field of a multipart form. CSV uploads start with a header row naming the columns (name, type, items, description, enum, pattern, minimum, maximum, minLength, maxLength, format, properties). Every row is validated like a single create, and the response reports per row whether it was created, already existed or failed. In atomic mode (the default) nothing is written unless every row succeeds; in best_effort mode each valid row is saved on its own.",
+                "consumes": [
+                    "application/x-ndjson",
+                    "text/csv",
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "properties"
+                ],
+                "summary": "Bulk import properties",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "atomic or best_effort",
+                        "name": "mode",
+                        "in": "query"
+                    },
+                    {
+                        "type": "file",
+                        "description": "NDJSON or CSV file",
+                        "name": "file",
+                        "in": "formData"
+                    }
+                ],
                 "responses": {
-                    "204": {"description": "Tracking plan deleted successfully"},
-                    "400": {"description": "Invalid ID"},
-                    "404": {"description": "Tracking plan not found"},
-                    "500": {"description": "Internal server error"}
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.BulkImportResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/fiber.Map"
+                        }
+                    },
+                    "415": {
+                        "description": "Unsupported Media Type",
+                        "schema": {
+                            "$ref": "#/definitions/fiber.Map"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.BulkImportResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/properties/{id}": {
+            "get": {
+                "description": "Retrieve a single property by its ID",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "properties"
+                ],
+                "summary": "Get property by ID",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Property ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Property"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Current version of the resource"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/fiber.Map"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/fiber.Map"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Update a property by its ID",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "properties"
+                ],
+                "summary": "Update a property",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Property ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Property update payload",
+                        "name": "property",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dtos.UpdatePropertyRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag the update is based on",
+                        "name": "If-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Property"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Current version of the resource"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/fiber.Map"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/fiber.Map"
+                        }
+                    },
+                    "412": {
+                        "description": "Precondition Failed",
+                        "schema": {
+                            "$ref": "#/definitions/fiber.Map"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Soft delete a property by its ID. Returns 409 listing the referencing tracking plans while tracking plan events still use it, unless cascade=true detaches it from them.",
+                "tags": [
+                    "properties"
+                ],
+                "summary": "Delete a property",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Property ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Detach the property from referencing tracking plans",
+                        "name": "cascade",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag the delete is based on",
+                        "name": "If-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/fiber.Map"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/fiber.Map"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/fiber.Map"
+                        }
+                    },
+                    "412": {
+                        "description": "Precondition Failed",
+                        "schema": {
+                            "$ref": "#/definitions/fiber.Map"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "description": "Partially update a property with an RFC 7396 merge patch or an RFC 6902 JSON Patch. The patch applies to the update request form of the property, and the result is validated like a PUT.",
+                "consumes": [
+                    "application/merge-patch+json",
+                    "application/json-patch+json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "properties"
+                ],
+                "summary": "Patch a property",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Property ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Merge patch or JSON Patch document",
+                        "name": "patch",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "object"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag the patch is based on",
+                        "name": "If-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Property"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Current version of the resource"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/fiber.Map"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/fiber.Map"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/fiber.Map"
+                        }
+                    },
+                    "412": {
+                        "description": "Precondition Failed",
+                        "schema": {
+                            "$ref": "#/definitions/fiber.Map"
+                        }
+                    },
+                    "415": {
+                        "description": "Unsupported Media Type",
+                        "schema": {
+                            "$ref": "#/definitions/fiber.Map"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/properties/{id}/restore": {
+            "post": {
+                "description": "Bring back a soft-deleted property",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "properties"
+                ],
+                "summary": "Restore a property",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Property ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Property"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Current version of the resource"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/fiber.Map"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/fiber.Map"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/fiber.Map"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/properties/{id}/usages": {
+            "get": {
+                "description": "List every tracking plan that uses the property, with the per-plan event, required flag and additionalProperties setting",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "properties"
+                ],
+                "summary": "List where a property is used",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Property ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Usage"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/fiber.Map"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/fiber.Map"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/search": {
+            "get": {
+                "description": "Full-text search over the names and descriptions of events, properties and tracking plans, ranked by relevance",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "search"
+                ],
+                "summary": "Search the catalog",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Search text; every word is matched as a prefix",
+                        "name": "q",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma-separated kinds to search: event, property, tracking_plan",
+                        "name": "types",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Maximum number of results (default 20, max 100)",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.SearchResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/fiber.Map"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/fiber.Map"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/tracking-plans": {
+            "get": {
+                "description": "Retrieve a page of tracking plans with optional filters and sorting. Events are only included with expand=events",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tracking-plans"
+                ],
+                "summary": "List tracking plans",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Page size (default 50, max 200)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor returned as next_cursor by the previous page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Set to events to include nested events and properties",
+                        "name": "expand",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only return items whose name starts with this prefix",
+                        "name": "name_prefix",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Only return items created at or after this unix time",
+                        "name": "created_after",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Only return items created before this unix time",
+                        "name": "created_before",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Only return items updated at or after this unix time",
+                        "name": "updated_after",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Only return items updated before this unix time",
+                        "name": "updated_before",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Sort field: id, name, create_time or update_time",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Sort order: asc or desc",
+                        "name": "order",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Also return soft-deleted items",
+                        "name": "include_deleted",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.PageResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/fiber.Map"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/fiber.Map"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Create a new tracking plan with events and properties",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tracking-plans"
+                ],
+                "summary": "Create a new tracking plan",
+                "parameters": [
+                    {
+                        "description": "Tracking plan to create",
+                        "name": "trackingPlan",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dtos.CreateTrackingPlanRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.TrackingPlan"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Current version of the resource"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/fiber.Map"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/tracking-plans/diff": {
+            "get": {
+                "description": "Compare two tracking plans, or two versions of one plan, and list added and removed events and properties, type changes, required flips and additionalProperties changes",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tracking-plans"
+                ],
+                "summary": "Diff two tracking plans or plan versions",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Base tracking plan ID",
+                        "name": "base",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Head tracking plan ID",
+                        "name": "head",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Base version (defaults to the current plan)",
+                        "name": "base_version",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Head version (defaults to the current plan)",
+                        "name": "head_version",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.TrackingPlanDiffResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/fiber.Map"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/fiber.Map"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/tracking-plans/import": {
+            "post": {
+                "description": "Create a tracking plan, or replace the events of an existing plan with the same name, from one JSON Schema document per event",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tracking-plans"
+                ],
+                "summary": "Import a tracking plan from JSON Schema",
+                "parameters": [
+                    {
+                        "description": "JSON Schema bundle",
+                        "name": "bundle",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ImportTrackingPlanSchemaRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.TrackingPlan"
+                        }
+                    },
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.TrackingPlan"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/fiber.Map"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/fiber.Map"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/tracking-plans/import/spreadsheet": {
+            "post": {
+                "description": "Create a tracking plan, or replace the events of an existing plan with the same name, from a CSV or XLSX spreadsheet in the export layout. The spreadsheet is sent as the request body or as the \"file\" field of a multipart form. A spreadsheet without the Constraints column keeps the plan-level constraints the plan already has.",
+                "consumes": [
+                    "text/csv",
+                    "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet",
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tracking-plans"
+                ],
+                "summary": "Import a tracking plan from a spreadsheet",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Tracking plan name",
+                        "name": "name",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Tracking plan description",
+                        "name": "description",
+                        "in": "query"
+                    },
+                    {
+                        "type": "file",
+                        "description": "CSV or XLSX file",
+                        "name": "file",
+                        "in": "formData"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.TrackingPlan"
+                        }
+                    },
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.TrackingPlan"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/fiber.Map"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/fiber.Map"
+                        }
+                    },
+                    "415": {
+                        "description": "Unsupported Media Type",
+                        "schema": {
+                            "$ref": "#/definitions/fiber.Map"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/tracking-plans/{id}": {
+            "get": {
+                "description": "Retrieve a single tracking plan by its ID",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tracking-plans"
+                ],
+                "summary": "Get tracking plan by ID",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Tracking Plan ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.TrackingPlan"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Current version of the resource"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/fiber.Map"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/fiber.Map"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Update a tracking plan by its ID",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tracking-plans"
+                ],
+                "summary": "Update a tracking plan",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Tracking Plan ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Tracking plan update payload",
+                        "name": "trackingPlan",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dtos.UpdateTrackingPlanRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag the update is based on",
+                        "name": "If-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.TrackingPlan"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Current version of the resource"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/fiber.Map"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/fiber.Map"
+                        }
+                    },
+                    "412": {
+                        "description": "Precondition Failed",
+                        "schema": {
+                            "$ref": "#/definitions/fiber.Map"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Soft delete a tracking plan by its ID. It can be brought back with the restore endpoint.",
+                "tags": [
+                    "tracking-plans"
+                ],
+                "summary": "Delete a tracking plan",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Tracking Plan ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag the delete is based on",
+                        "name": "If-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/fiber.Map"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/fiber.Map"
+                        }
+                    },
+                    "412": {
+                        "description": "Precondition Failed",
+                        "schema": {
+                            "$ref": "#/definitions/fiber.Map"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "description": "Partially update a tracking plan with an RFC 7396 merge patch or an RFC 6902 JSON Patch. The patch applies to the update request form of the tracking plan, and the result is validated like a PUT.",
+                "consumes": [
+                    "application/merge-patch+json",
+                    "application/json-patch+json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tracking-plans"
+                ],
+                "summary": "Patch a tracking plan",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Tracking Plan ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Merge patch or JSON Patch document",
+                        "name": "patch",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "object"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag the patch is based on",
+                        "name": "If-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.TrackingPlan"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Current version of the resource"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/fiber.Map"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/fiber.Map"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/fiber.Map"
+                        }
+                    },
+                    "412": {
+                        "description": "Precondition Failed",
+                        "schema": {
+                            "$ref": "#/definitions/fiber.Map"
+                        }
+                    },
+                    "415": {
+                        "description": "Unsupported Media Type",
+                        "schema": {
+                            "$ref": "#/definitions/fiber.Map"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/tracking-plans/{id}/events": {
+            "post": {
+                "description": "Add one event, with its properties, to a tracking plan. Events and properties missing from the catalog are created.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tracking-plans"
+                ],
+                "summary": "Add an event to a tracking plan",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Tracking Plan ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Event to add",
+                        "name": "event",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dtos.TrackingPlanEventRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag the change is based on",
+                        "name": "If-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.TrackingPlan"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Current version of the resource"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/fiber.Map"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/fiber.Map"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/fiber.Map"
+                        }
+                    },
+                    "412": {
+                        "description": "Precondition Failed",
+                        "schema": {
+                            "$ref": "#/definitions/fiber.Map"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/tracking-plans/{id}/events/{eventId}": {
+            "delete": {
+                "description": "Take an event out of a tracking plan. The event stays in the catalog.",
+                "tags": [
+                    "tracking-plans"
+                ],
+                "summary": "Remove an event from a tracking plan",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Tracking Plan ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Event ID",
+                        "name": "eventId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag the change is based on",
+                        "name": "If-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content",
+                        "schema": {
+                            "type": "string"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Current version of the resource"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/fiber.Map"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/fiber.Map"
+                        }
+                    },
+                    "412": {
+                        "description": "Precondition Failed",
+                        "schema": {
+                            "$ref": "#/definitions/fiber.Map"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/tracking-plans/{id}/events/{eventId}/properties/{propertyId}": {
+            "put": {
+                "description": "Add a catalog property to an event of a tracking plan, or update whether it is required and its plan-level constraints",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tracking-plans"
+                ],
+                "summary": "Set a property on a tracking plan event",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Tracking Plan ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Event ID",
+                        "name": "eventId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Property ID",
+                        "name": "propertyId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "How the event uses the property",
+                        "name": "property",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dtos.TrackingPlanEventPropertyRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag the change is based on",
+                        "name": "If-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.TrackingPlan"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Current version of the resource"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/fiber.Map"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/fiber.Map"
+                        }
+                    },
+                    "412": {
+                        "description": "Precondition Failed",
+                        "schema": {
+                            "$ref": "#/definitions/fiber.Map"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Take a property off an event of a tracking plan. The property stays in the catalog.",
+                "tags": [
+                    "tracking-plans"
+                ],
+                "summary": "Remove a property from a tracking plan event",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Tracking Plan ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Event ID",
+                        "name": "eventId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Property ID",
+                        "name": "propertyId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag the change is based on",
+                        "name": "If-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content",
+                        "schema": {
+                            "type": "string"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Current version of the resource"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/fiber.Map"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/fiber.Map"
+                        }
+                    },
+                    "412": {
+                        "description": "Precondition Failed",
+                        "schema": {
+                            "$ref": "#/definitions/fiber.Map"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/tracking-plans/{id}/restore": {
+            "post": {
+                "description": "Bring back a soft-deleted tracking plan",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tracking-plans"
+                ],
+                "summary": "Restore a tracking plan",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Tracking Plan ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.TrackingPlan"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Current version of the resource"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/fiber.Map"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/fiber.Map"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/fiber.Map"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/tracking-plans/{id}/schema": {
+            "get": {
+                "description": "Render every event of a tracking plan as a JSON Schema document",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tracking-plans"
+                ],
+                "summary": "Export a tracking plan as JSON Schema",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Tracking Plan ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "JSON Schema draft (draft-07 or 2020-12)",
+                        "name": "draft",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.TrackingPlanSchemaResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/fiber.Map"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/fiber.Map"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/tracking-plans/{id}/spreadsheet": {
+            "get": {
+                "description": "Download a tracking plan as CSV or XLSX with one row per event/property pair and the columns Event Name, Event Type, Event Description, Property Name, Property Type, Required, Property Description, Additional Properties and Constraints (the plan-level constraints as JSON)",
+                "produces": [
+                    "text/csv",
+                    "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+                ],
+                "tags": [
+                    "tracking-plans"
+                ],
+                "summary": "Export a tracking plan as a spreadsheet",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Tracking Plan ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "csv or xlsx (default xlsx)",
+                        "name": "format",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/fiber.Map"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/fiber.Map"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/tracking-plans/{id}/validate": {
+            "post": {
+                "description": "Check a Segment-spec event payload against the events and properties of a tracking plan",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tracking-plans"
+                ],
+                "summary": "Validate a payload against a tracking plan",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Tracking Plan ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Event payload to validate",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ValidatePayloadRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ValidatePayloadResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/fiber.Map"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/fiber.Map"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/tracking-plans/{id}/versions": {
+            "get": {
+                "description": "Retrieve the version history of a tracking plan, newest first",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tracking-plans"
+                ],
+                "summary": "List tracking plan versions",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Tracking Plan ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/dtos.TrackingPlanVersionSummary"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/fiber.Map"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/fiber.Map"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/tracking-plans/{id}/versions/{n}": {
+            "get": {
+                "description": "Retrieve the snapshot of a tracking plan at a given version",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tracking-plans"
+                ],
+                "summary": "Get a tracking plan version",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Tracking Plan ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Version number",
+                        "name": "n",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.TrackingPlanVersion"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/fiber.Map"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/fiber.Map"
+                        }
+                    }
+                }
+            }
+        },
+        "/health": {
+            "get": {
+                "description": "Returns the health status of the service",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "health"
+                ],
+                "summary": "Health check",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/fiber.Map"
+                        }
+                    }
                 }
             }
         }
     },
-    "components": {
-        "schemas": {
-            "Event": {
-                "type": "object",
+    "definitions": {
+        "dtos.APIClientCredentials": {
+            "type": "object",
+            "properties": {
+                "client_id": {
+                    "type": "string"
+                },
+                "create_time": {
+                    "type": "integer"
+                },
+                "expires_at": {
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "last_used_at": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "revoked_at": {
+                    "type": "integer"
+                },
+                "scopes": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "secret": {
+                    "type": "string"
+                },
+                "update_time": {
+                    "type": "integer"
+                }
+            }
+        },
+        "dtos.BulkImportResponse": {
+            "type": "object",
+            "properties": {
+                "committed": {
+                    "type": "boolean"
+                },
+                "created": {
+                    "type": "integer"
+                },
+                "existing": {
+                    "type": "integer"
+                },
+                "failed": {
+                    "type": "integer"
+                },
+                "mode": {
+                    "type": "string"
+                },
+                "results": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dtos.BulkImportResult"
+                    }
+                },
+                "skipped": {
+                    "type": "integer"
+                }
+            }
+        },
+        "dtos.BulkImportResult": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "line": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                }
+            }
+        },
+        "dtos.CreateAPIClientRequest": {
+            "type": "object",
+            "required": [
+                "client_id",
+                "scopes"
+            ],
+            "properties": {
+                "client_id": {
+                    "type": "string"
+                },
+                "expires_at": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "scopes": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "dtos.CreateEventRequest": {
+            "type": "object",
+            "required": [
+                "name",
+                "type"
+            ],
+            "properties": {
+                "description": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "type": {
+                    "type": "string"
+                }
+            }
+        },
+        "dtos.CreatePropertyRequest": {
+            "type": "object",
+            "required": [
+                "name",
+                "type"
+            ],
+            "properties": {
+                "description": {
+                    "type": "string"
+                },
+                "enum": {
+                    "type": "array",
+                    "items": {}
+                },
+                "format": {
+                    "type": "string"
+                },
+                "items": {
+                    "type": "string"
+                },
+                "maxLength": {
+                    "type": "integer"
+                },
+                "maximum": {
+                    "type": "number"
+                },
+                "minLength": {
+                    "type": "integer"
+                },
+                "minimum": {
+                    "type": "number"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "pattern": {
+                    "type": "string"
+                },
                 "properties": {
-                    "id": {"type": "integer"},
-                    "name": {"type": "string"},
-                    "type": {"type": "string"},
-                    "description": {"type": "string"},
-                    "createdAt": {"type": "string", "format": "date-time"},
-                    "updatedAt": {"type": "string", "format": "date-time"}
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.PropertyField"
+                    }
+                },
+                "type": {
+                    "type": "string"
+                }
+            }
+        },
+        "dtos.CreateTrackingPlanRequest": {
+            "type": "object",
+            "required": [
+                "events",
+                "name"
+            ],
+            "properties": {
+                "description": {
+                    "type": "string"
+                },
+                "events": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dtos.TrackingPlanEventRequest"
+                    }
+                },
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "dtos.EventSchema": {
+            "type": "object",
+            "properties": {
+                "name": {
+                    "type": "string"
+                },
+                "schema": {
+                    "$ref": "#/definitions/jsonschema.Schema"
+                },
+                "type": {
+                    "type": "string"
+                }
+            }
+        },
+        "dtos.ImportTrackingPlanSchemaRequest": {
+            "type": "object",
+            "required": [
+                "name",
+                "schemas"
+            ],
+            "properties": {
+                "description": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "schemas": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/jsonschema.Schema"
+                    }
+                }
+            }
+        },
+        "dtos.PageResponse": {
+            "type": "object",
+            "properties": {
+                "data": {},
+                "limit": {
+                    "type": "integer"
+                },
+                "next": {
+                    "type": "string"
+                },
+                "next_cursor": {
+                    "type": "string"
+                },
+                "total": {
+                    "type": "integer"
+                }
+            }
+        },
+        "dtos.PayloadViolation": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "string"
+                },
+                "message": {
+                    "type": "string"
+                },
+                "path": {
+                    "type": "string"
                 }
-            },
-            "Property": {
-                "type": "object",
-                "properties": {
-                    "id": {"type": "integer"},
-                    "name": {"type": "string"},
-                    "type": {"type": "string", "enum": ["string", "number", "boolean"]},
-                    "description": {"type": "string"},
-                    "createdAt": {"type": "string", "format": "date-time"},
-                    "updatedAt": {"type": "string", "format": "date-time"}
+            }
+        },
+        "dtos.SearchResponse": {
+            "type": "object",
+            "properties": {
+                "query": {
+                    "type": "string"
+                },
+                "results": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.SearchResult"
+                    }
                 }
-            },
-            "TrackingPlan": {
-                "type": "object",
-                "properties": {
-                    "id": {"type": "integer"},
-                    "name": {"type": "string"},
-                    "description": {"type": "string"},
-                    "events": {
-                        "type": "array",
-                        "items": {"$ref": "#/components/schemas/TrackingPlanEvent"}
-                    },
-                    "createdAt": {"type": "string", "format": "date-time"},
-                    "updatedAt": {"type": "string", "format": "date-time"}
+            }
+        },
+        "dtos.TrackingPlanChange": {
+            "type": "object",
+            "properties": {
+                "event": {
+                    "type": "string"
+                },
+                "event_type": {
+                    "type": "string"
+                },
+                "from": {},
+                "kind": {
+                    "type": "string"
+                },
+                "property": {
+                    "type": "string"
+                },
+                "to": {}
+            }
+        },
+        "dtos.TrackingPlanDiffResponse": {
+            "type": "object",
+            "properties": {
+                "base": {
+                    "$ref": "#/definitions/dtos.TrackingPlanRef"
+                },
+                "changes": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dtos.TrackingPlanChange"
+                    }
+                },
+                "head": {
+                    "$ref": "#/definitions/dtos.TrackingPlanRef"
                 }
-            },
-            "TrackingPlanEvent": {
-                "type": "object",
-                "properties": {
-                    "id": {"type": "integer"},
-                    "trackingPlanID": {"type": "integer"},
-                    "eventID": {"type": "integer"},
-                    "event": {"$ref": "#/components/schemas/Event"},
-                    "additionalProperties": {"type": "object"},
-                    "properties": {
-                        "type": "array",
-                        "items": {"$ref": "#/components/schemas/TrackingPlanEventProperty"}
-                    },
-                    "createdAt": {"type": "string", "format": "date-time"},
-                    "updatedAt": {"type": "string", "format": "date-time"}
+            }
+        },
+        "dtos.TrackingPlanEventPropertyRequest": {
+            "type": "object",
+            "properties": {
+                "enum": {
+                    "type": "array",
+                    "items": {}
+                },
+                "format": {
+                    "type": "string"
+                },
+                "maxLength": {
+                    "type": "integer"
+                },
+                "maximum": {
+                    "type": "number"
+                },
+                "minLength": {
+                    "type": "integer"
+                },
+                "minimum": {
+                    "type": "number"
+                },
+                "pattern": {
+                    "type": "string"
+                },
+                "required": {
+                    "type": "boolean"
                 }
-            },
-            "TrackingPlanEventProperty": {
-                "type": "object",
+            }
+        },
+        "dtos.TrackingPlanEventRequest": {
+            "type": "object",
+            "required": [
+                "name",
+                "type"
+            ],
+            "properties": {
+                "additionalProperties": {
+                    "type": "boolean"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
                 "properties": {
-                    "id": {"type": "integer"},
-                    "trackingPlanEventID": {"type": "integer"},
-                    "propertyID": {"type": "integer"},
-                    "property": {"$ref": "#/components/schemas/Property"},
-                    "required": {"type": "boolean"},
-                    "createdAt": {"type": "string", "format": "date-time"},
-                    "updatedAt": {"type": "string", "format": "date-time"}
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dtos.TrackingPlanPropertyRequest"
+                    }
+                },
+                "type": {
+                    "type": "string"
                 }
-            },
-            "CreateEventRequest": {
-                "type": "object",
-                "required": ["name", "type"],
+            }
+        },
+        "dtos.TrackingPlanPropertyRequest": {
+            "type": "object",
+            "required": [
+                "name",
+                "type"
+            ],
+            "properties": {
+                "description": {
+                    "type": "string"
+                },
+                "enum": {
+                    "type": "array",
+                    "items": {}
+                },
+                "format": {
+                    "type": "string"
+                },
+                "items": {
+                    "type": "string"
+                },
+                "maxLength": {
+                    "type": "integer"
+                },
+                "maximum": {
+                    "type": "number"
+                },
+                "minLength": {
+                    "type": "integer"
+                },
+                "minimum": {
+                    "type": "number"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "pattern": {
+                    "type": "string"
+                },
                 "properties": {
-                    "name": {"type": "string"},
-                    "type": {"type": "string"},
-                    "description": {"type": "string"}
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.PropertyField"
+                    }
+                },
+                "required": {
+                    "type": "boolean"
+                },
+                "type": {
+                    "type": "string"
                 }
-            },
-            "UpdateEventRequest": {
-                "type": "object",
-                "required": ["name", "type"],
-                "properties": {
-                    "name": {"type": "string"},
-                    "type": {"type": "string"},
-                    "description": {"type": "string"}
+            }
+        },
+        "dtos.TrackingPlanRef": {
+            "type": "object",
+            "properties": {
+                "name": {
+                    "type": "string"
+                },
+                "tracking_plan_id": {
+                    "type": "integer"
+                },
+                "version": {
+                    "type": "integer"
                 }
-            },
-            "CreatePropertyRequest": {
-                "type": "object",
-                "required": ["name", "type"],
+            }
+        },
+        "dtos.TrackingPlanSchemaResponse": {
+            "type": "object",
+            "properties": {
+                "draft": {
+                    "type": "string"
+                },
+                "events": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dtos.EventSchema"
+                    }
+                },
+                "name": {
+                    "type": "string"
+                },
+                "tracking_plan_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "dtos.TrackingPlanVersionSummary": {
+            "type": "object",
+            "properties": {
+                "create_time": {
+                    "type": "integer"
+                },
+                "tracking_plan_id": {
+                    "type": "integer"
+                },
+                "version": {
+                    "type": "integer"
+                }
+            }
+        },
+        "dtos.UpdateEventRequest": {
+            "type": "object",
+            "required": [
+                "name",
+                "type"
+            ],
+            "properties": {
+                "description": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "type": {
+                    "type": "string"
+                }
+            }
+        },
+        "dtos.UpdatePropertyRequest": {
+            "type": "object",
+            "required": [
+                "name",
+                "type"
+            ],
+            "properties": {
+                "description": {
+                    "type": "string"
+                },
+                "enum": {
+                    "type": "array",
+                    "items": {}
+                },
+                "format": {
+                    "type": "string"
+                },
+                "items": {
+                    "type": "string"
+                },
+                "maxLength": {
+                    "type": "integer"
+                },
+                "maximum": {
+                    "type": "number"
+                },
+                "minLength": {
+                    "type": "integer"
+                },
+                "minimum": {
+                    "type": "number"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "pattern": {
+                    "type": "string"
+                },
                 "properties": {
-                    "name": {"type": "string"},
-                    "type": {"type": "string", "enum": ["string", "number", "boolean"]},
-                    "description": {"type": "string"}
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.PropertyField"
+                    }
+                },
+                "type": {
+                    "type": "string"
                 }
-            },
-            "UpdatePropertyRequest": {
-                "type": "object",
-                "required": ["name", "type"],
+            }
+        },
+        "dtos.UpdateTrackingPlanRequest": {
+            "type": "object",
+            "required": [
+                "events",
+                "name"
+            ],
+            "properties": {
+                "description": {
+                    "type": "string"
+                },
+                "events": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dtos.TrackingPlanEventRequest"
+                    }
+                },
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "dtos.ValidatePayloadRequest": {
+            "type": "object",
+            "required": [
+                "type"
+            ],
+            "properties": {
+                "event": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
                 "properties": {
-                    "name": {"type": "string"},
-                    "type": {"type": "string", "enum": ["string", "number", "boolean"]},
-                    "description": {"type": "string"}
+                    "type": "object",
+                    "additionalProperties": true
+                },
+                "traits": {
+                    "type": "object",
+                    "additionalProperties": true
+                },
+                "type": {
+                    "type": "string"
                 }
-            },
-            "CreateTrackingPlanRequest": {
-                "type": "object",
-                "required": ["name", "events"],
+            }
+        },
+        "dtos.ValidatePayloadResponse": {
+            "type": "object",
+            "properties": {
+                "event": {
+                    "type": "string"
+                },
+                "valid": {
+                    "type": "boolean"
+                },
+                "violations": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dtos.PayloadViolation"
+                    }
+                }
+            }
+        },
+        "fiber.Map": {
+            "type": "object",
+            "additionalProperties": true
+        },
+        "gorm.DeletedAt": {
+            "type": "object",
+            "properties": {
+                "time": {
+                    "type": "string"
+                },
+                "valid": {
+                    "description": "Valid is true if Time is not NULL",
+                    "type": "boolean"
+                }
+            }
+        },
+        "jsonschema.Schema": {
+            "type": "object",
+            "properties": {
+                "$id": {
+                    "type": "string"
+                },
+                "$schema": {
+                    "type": "string"
+                },
+                "additionalProperties": {
+                    "type": "boolean"
+                },
+                "const": {},
+                "description": {
+                    "type": "string"
+                },
+                "enum": {
+                    "type": "array",
+                    "items": {}
+                },
+                "format": {
+                    "type": "string"
+                },
+                "items": {
+                    "$ref": "#/definitions/jsonschema.Schema"
+                },
+                "maxLength": {
+                    "type": "integer"
+                },
+                "maximum": {
+                    "type": "number"
+                },
+                "minLength": {
+                    "type": "integer"
+                },
+                "minimum": {
+                    "type": "number"
+                },
+                "pattern": {
+                    "type": "string"
+                },
                 "properties": {
-                    "name": {"type": "string"},
-                    "description": {"type": "string"},
-                    "events": {
-                        "type": "array",
-                        "items": {"$ref": "#/components/schemas/TrackingPlanEventRequest"}
+                    "type": "object",
+                    "additionalProperties": {
+                        "$ref": "#/definitions/jsonschema.Schema"
+                    }
+                },
+                "required": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "title": {
+                    "type": "string"
+                },
+                "type": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
                     }
                 }
-            },
-            "UpdateTrackingPlanRequest": {
-                "type": "object",
-                "required": ["name", "events"],
+            }
+        },
+        "models.APIClient": {
+            "type": "object",
+            "properties": {
+                "client_id": {
+                    "type": "string"
+                },
+                "create_time": {
+                    "type": "integer"
+                },
+                "expires_at": {
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "last_used_at": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "revoked_at": {
+                    "type": "integer"
+                },
+                "scopes": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "update_time": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.Event": {
+            "type": "object",
+            "properties": {
+                "create_time": {
+                    "type": "integer"
+                },
+                "deleted_at": {
+                    "$ref": "#/definitions/gorm.DeletedAt"
+                },
+                "deleted_by": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "type": {
+                    "type": "string"
+                },
+                "update_time": {
+                    "type": "integer"
+                },
+                "version": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.Property": {
+            "type": "object",
+            "properties": {
+                "create_time": {
+                    "type": "integer"
+                },
+                "deleted_at": {
+                    "$ref": "#/definitions/gorm.DeletedAt"
+                },
+                "deleted_by": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "enum": {
+                    "type": "array",
+                    "items": {}
+                },
+                "format": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "items": {
+                    "type": "string"
+                },
+                "maxLength": {
+                    "type": "integer"
+                },
+                "maximum": {
+                    "type": "number"
+                },
+                "minLength": {
+                    "type": "integer"
+                },
+                "minimum": {
+                    "type": "number"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "pattern": {
+                    "type": "string"
+                },
                 "properties": {
-                    "name": {"type": "string"},
-                    "description": {"type": "string"},
-                    "events": {
-                        "type": "array",
-                        "items": {"$ref": "#/components/schemas/TrackingPlanEventRequest"}
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.PropertyField"
                     }
+                },
+                "type": {
+                    "type": "string"
+                },
+                "update_time": {
+                    "type": "integer"
+                },
+                "version": {
+                    "type": "integer"
                 }
-            },
-            "TrackingPlanEventRequest": {
-                "type": "object",
-                "required": ["name", "properties"],
+            }
+        },
+        "models.PropertyField": {
+            "type": "object",
+            "properties": {
+                "description": {
+                    "type": "string"
+                },
+                "enum": {
+                    "type": "array",
+                    "items": {}
+                },
+                "format": {
+                    "type": "string"
+                },
+                "items": {
+                    "type": "string"
+                },
+                "maxLength": {
+                    "type": "integer"
+                },
+                "maximum": {
+                    "type": "number"
+                },
+                "minLength": {
+                    "type": "integer"
+                },
+                "minimum": {
+                    "type": "number"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "pattern": {
+                    "type": "string"
+                },
                 "properties": {
-                    "name": {"type": "string"},
-                    "type": {"type": "string"},
-                    "description": {"type": "string"},
-                    "additionalProperties": {"type": "object"},
-                    "properties": {
-                        "type": "array",
-                        "items": {"$ref": "#/components/schemas/TrackingPlanPropertyRequest"}
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.PropertyField"
                     }
+                },
+                "required": {
+                    "type": "boolean"
+                },
+                "type": {
+                    "type": "string"
                 }
-            },
-            "TrackingPlanPropertyRequest": {
-                "type": "object",
-                "required": ["name", "type", "required"],
+            }
+        },
+        "models.SearchResult": {
+            "type": "object",
+            "properties": {
+                "description": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "kind": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "rank": {
+                    "type": "number"
+                },
+                "type": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.TrackingPlan": {
+            "type": "object",
+            "properties": {
+                "create_time": {
+                    "type": "integer"
+                },
+                "deleted_at": {
+                    "$ref": "#/definitions/gorm.DeletedAt"
+                },
+                "deleted_by": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "events": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.TrackingPlanEvent"
+                    }
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "update_time": {
+                    "type": "integer"
+                },
+                "version": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.TrackingPlanEvent": {
+            "type": "object",
+            "properties": {
+                "additionalProperties": {
+                    "type": "boolean"
+                },
+                "event": {
+                    "$ref": "#/definitions/models.Event"
+                },
+                "event_id": {
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "integer"
+                },
                 "properties": {
-                    "name": {"type": "string"},
-                    "type": {"type": "string", "enum": ["string", "number", "boolean"]},
-                    "description": {"type": "string"},
-                    "required": {"type": "boolean"}
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.TrackingPlanEventProperty"
+                    }
+                },
+                "tracking_plan_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.TrackingPlanEventProperty": {
+            "type": "object",
+            "properties": {
+                "enum": {
+                    "type": "array",
+                    "items": {}
+                },
+                "format": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "maxLength": {
+                    "type": "integer"
+                },
+                "maximum": {
+                    "type": "number"
+                },
+                "minLength": {
+                    "type": "integer"
+                },
+                "minimum": {
+                    "type": "number"
+                },
+                "pattern": {
+                    "type": "string"
+                },
+                "property": {
+                    "$ref": "#/definitions/models.Property"
+                },
+                "property_id": {
+                    "type": "integer"
+                },
+                "required": {
+                    "type": "boolean"
+                },
+                "tracking_plan_event_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.TrackingPlanVersion": {
+            "type": "object",
+            "properties": {
+                "create_time": {
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "snapshot": {
+                    "$ref": "#/definitions/models.TrackingPlan"
+                },
+                "tracking_plan_id": {
+                    "type": "integer"
+                },
+                "version": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.Usage": {
+            "type": "object",
+            "properties": {
+                "additionalProperties": {
+                    "type": "boolean"
+                },
+                "event_id": {
+                    "type": "integer"
+                },
+                "event_name": {
+                    "type": "string"
+                },
+                "event_type": {
+                    "type": "string"
+                },
+                "required": {
+                    "type": "boolean"
+                },
+                "tracking_plan_event_id": {
+                    "type": "integer"
+                },
+                "tracking_plan_id": {
+                    "type": "integer"
+                },
+                "tracking_plan_name": {
+                    "type": "string"
                 }
             }
         }
@@ -581,14 +3650,16 @@ const docTemplate = `{
 
 // SwaggerInfo holds exported Swagger Info so clients can modify it
 var SwaggerInfo = &swag.Spec{
-	Version:          "1.0.0",
+	Version:          "1.0",
 	Host:             "localhost:8080",
 	BasePath:         "/",
-	Schemes:          []string{"http"},
-	Title:            "API Catalog Service",
-	Description:      "API for managing events, properties, and tracking plans",
+	Schemes:          []string{},
+	Title:            "API Catalog",
+	Description:      "This is the API Catalog service.",
 	InfoInstanceName: "swagger",
 	SwaggerTemplate:  docTemplate,
+	LeftDelim:        "{{",
+	RightDelim:       "}}",
 }
 
 func init() {
@@ -14,6 +14,7 @@ import (
 	"github.com/shivamrajput1826/api-catalog/config"
 	"github.com/shivamrajput1826/api-catalog/internal/db"
 	"github.com/shivamrajput1826/api-catalog/internal/handlers"
+	"github.com/shivamrajput1826/api-catalog/internal/metrics"
 	"github.com/shivamrajput1826/api-catalog/internal/routes"
 	"github.com/shivamrajput1826/api-catalog/logger"
 	"github.com/shivamrajput1826/api-catalog/middleware"
@@ -28,6 +29,10 @@ func SetupRoutes(prefix string, app *fiber.App) {
 func main() {
 	config.LoadConfig()
 
+	if err := middleware.LoadJWTVerifier(); err != nil {
+		log.Fatal("Failed to configure JWT verification:", err)
+	}
+
 	app := fiber.New(fiber.Config{
 		BodyLimit:      1024 * 1024 * 10,
 		Immutable:      true,
@@ -43,9 +48,11 @@ func main() {
 		log.Fatal("Failed to migrate database:", err)
 	}
 	defer db.Close(database)
+	app.Use(middleware.RequestContextMiddleware)
+	app.Use(metrics.Middleware)
+	app.Use(middleware.RecoveryMiddleware)
 	app.Get("/swagger/*", swagger.HandlerDefault)
 
-	app.Use(middleware.RecoveryMiddleware)
 	h := handlers.New(database)
 
 	routes.Setup(app, h)
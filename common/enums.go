@@ -1,6 +1,15 @@
 package common
 
-var ClientIdsConfig = []string{
-	"client_id",
-	"client_id2",
+const (
+	RoleViewer = "viewer"
+	RoleEditor = "editor"
+	RoleAdmin  = "admin"
+)
+
+// RoleRanks orders the roles a token can carry. Each role includes the
+// permissions of every role ranked below it.
+var RoleRanks = map[string]int{
+	RoleViewer: 1,
+	RoleEditor: 2,
+	RoleAdmin:  3,
 }
@@ -32,3 +32,7 @@ func LoadConfig() error {
 func GetConfigValue(key string) string {
 	return viper.GetString(key)
 }
+
+func GetConfigBool(key string) bool {
+	return viper.GetBool(key)
+}
@@ -1,11 +1,17 @@
 package db
 
 import (
+	"errors"
 	"fmt"
+	"strings"
 	"time"
 
+	"github.com/shivamrajput1826/api-catalog/common"
 	"github.com/shivamrajput1826/api-catalog/config"
+	"github.com/shivamrajput1826/api-catalog/internal/metrics"
 	"github.com/shivamrajput1826/api-catalog/internal/models"
+	"github.com/shivamrajput1826/api-catalog/internal/repositories"
+	"github.com/shivamrajput1826/api-catalog/internal/workspace"
 	"github.com/shivamrajput1826/api-catalog/logger"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
@@ -28,13 +34,19 @@ func ConnectDB() (*gorm.DB, error) {
 		host, user, password, dbname, port)
 
 	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{
-		Logger: gormLogger.Default.LogMode(gormLogger.Info),
+		Logger:         gormLogger.Default.LogMode(gormLogger.Info),
+		TranslateError: true,
 	})
 	if err != nil {
 		customLogger.Error("Failed to connect to database", err)
 		return nil, err
 	}
 
+	if err := db.Use(workspace.Plugin{}); err != nil {
+		customLogger.Error("Failed to register workspace plugin", err)
+		return nil, err
+	}
+
 	sqlDB, err := db.DB()
 	if err != nil {
 		customLogger.Error("Failed to get underlying sql.DB", err)
@@ -50,6 +62,11 @@ func ConnectDB() (*gorm.DB, error) {
 	sqlDB.SetMaxOpenConns(100)
 	sqlDB.SetConnMaxLifetime(time.Hour)
 
+	if err := metrics.InstrumentDB(db, config.GetConfigBool("METRICS_WORKSPACE_LABELS")); err != nil {
+		customLogger.Error("Failed to register database metrics", err)
+		return nil, err
+	}
+
 	customLogger.Info("Successfully connected to database")
 	return db, nil
 }
@@ -66,10 +83,141 @@ func Migrate(db *gorm.DB) error {
 		return err
 	}
 
+	if err := dropLegacyIndexes(db); err != nil {
+		customLogger.Error("Failed to drop legacy indexes", err)
+		return err
+	}
+
+	if err := bootstrapClient(db); err != nil {
+		customLogger.Error("Failed to create the bootstrap API client", err)
+		return err
+	}
+
+	if err := bootstrapLegacyClients(db); err != nil {
+		customLogger.Error("Failed to create the legacy API clients", err)
+		return err
+	}
+
+	if err := assignDefaultWorkspace(db); err != nil {
+		customLogger.Error("Failed to assign rows to the default workspace", err)
+		return err
+	}
+
+	if err := repositories.CreateSearchIndexes(db); err != nil {
+		customLogger.Error("Failed to create search indexes", err)
+		return err
+	}
+
 	customLogger.Info("Database migrations completed successfully")
 	return nil
 }
 
+// dropLegacyIndexes removes unique indexes that also covered soft-deleted
+// rows, and the global name indexes that were replaced by per-workspace ones.
+func dropLegacyIndexes(db *gorm.DB) error {
+	legacy := []struct {
+		model interface{}
+		name  string
+	}{
+		{&models.Event{}, "idx_event_name_type"},
+		{&models.Property{}, "idx_property_name_type"},
+		{&models.Event{}, "idx_event_name_type_active"},
+		{&models.Property{}, "idx_property_name_type_active"},
+		{&models.TrackingPlan{}, "idx_tracking_plan_name_active"},
+	}
+	for _, index := range legacy {
+		if !db.Migrator().HasIndex(index.model, index.name) {
+			continue
+		}
+		if err := db.Migrator().DropIndex(index.model, index.name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// bootstrapClient creates the API client named by BOOTSTRAP_CLIENT_ID with
+// BOOTSTRAP_CLIENT_SECRET and every scope, so a fresh deployment has a client
+// to create the others with. An existing client is left alone, so rotating
+// its secret sticks across restarts.
+func bootstrapClient(db *gorm.DB) error {
+	clientID := config.GetConfigValue("BOOTSTRAP_CLIENT_ID")
+	secret := config.GetConfigValue("BOOTSTRAP_CLIENT_SECRET")
+	if clientID == "" || secret == "" {
+		return nil
+	}
+
+	return seedClient(db, clientID, secret,
+		[]string{common.RoleViewer, common.RoleEditor, common.RoleAdmin, models.ScopeClients})
+}
+
+// bootstrapLegacyClients registers the client ids that were accepted before
+// clients were stored in the database, given in BOOTSTRAP_LEGACY_CLIENTS as
+// comma-separated client_id=secret pairs. They get every role, as before,
+// but not the clients scope.
+func bootstrapLegacyClients(db *gorm.DB) error {
+	pairs := config.GetConfigValue("BOOTSTRAP_LEGACY_CLIENTS")
+	if pairs == "" {
+		return nil
+	}
+	for _, pair := range strings.Split(pairs, ",") {
+		clientID, secret, ok := strings.Cut(strings.TrimSpace(pair), "=")
+		if !ok || clientID == "" || secret == "" {
+			return fmt.Errorf("BOOTSTRAP_LEGACY_CLIENTS must be comma-separated client_id=secret pairs, got %q", pair)
+		}
+		err := seedClient(db, clientID, secret, []string{common.RoleViewer, common.RoleEditor, common.RoleAdmin})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// seedClient creates an API client unless one with its client id exists
+// already, so rotating its secret sticks across restarts.
+func seedClient(db *gorm.DB, clientID, secret string, scopes []string) error {
+	clients := repositories.NewAPIClientRepository(db)
+	if _, err := clients.GetByClientID(clientID); err != gorm.ErrRecordNotFound {
+		return err
+	}
+	customLogger.Info(fmt.Sprintf("Creating API client %s", clientID))
+	err := clients.Create(&models.APIClient{
+		ClientID:   clientID,
+		Name:       clientID,
+		SecretHash: models.HashClientSecret(secret),
+		Scopes:     scopes,
+	})
+	// Another instance starting at the same time may have created it.
+	if errors.Is(err, gorm.ErrDuplicatedKey) {
+		return nil
+	}
+	return err
+}
+
+// assignDefaultWorkspace moves rows created before workspaces existed into the
+// workspace of the bootstrap client, so existing catalogs stay visible to it.
+func assignDefaultWorkspace(db *gorm.DB) error {
+	clientID := config.GetConfigValue("BOOTSTRAP_CLIENT_ID")
+	if clientID == "" {
+		return nil
+	}
+	defaultWorkspace, err := repositories.NewWorkspaceRepository(db).FirstOrCreate(clientID)
+	if err != nil {
+		return err
+	}
+	owned := []interface{}{&models.Event{}, &models.Property{}, &models.TrackingPlan{}, &models.AuditEntry{}}
+	return db.Transaction(func(tx *gorm.DB) error {
+		for _, model := range owned {
+			err := tx.Model(model).Unscoped().Where(workspace.Column+" = 0").
+				UpdateColumn(workspace.Column, defaultWorkspace.ID).Error
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
 func Close(db *gorm.DB) error {
 	customLogger.Info("Closing database connection...")
 	sqlDB, err := db.DB()
@@ -0,0 +1,54 @@
+package db
+
+import (
+	"testing"
+
+	"github.com/glebarez/sqlite"
+	"github.com/shivamrajput1826/api-catalog/internal/models"
+	"github.com/spf13/viper"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+func TestBootstrapLegacyClients(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open("file:legacy?mode=memory&cache=shared"), &gorm.Config{
+		Logger:         logger.Default.LogMode(logger.Silent),
+		TranslateError: true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := db.AutoMigrate(&models.APIClient{}); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { viper.Set("BOOTSTRAP_LEGACY_CLIENTS", "") })
+
+	viper.Set("BOOTSTRAP_LEGACY_CLIENTS", "client_id=first, client_id2=second")
+	if err := bootstrapLegacyClients(db); err != nil {
+		t.Fatal(err)
+	}
+	// Secrets rotated since are kept on the next start.
+	viper.Set("BOOTSTRAP_LEGACY_CLIENTS", "client_id=changed,client_id2=second")
+	if err := bootstrapLegacyClients(db); err != nil {
+		t.Fatal(err)
+	}
+
+	var clients []models.APIClient
+	if err := db.Order("client_id").Find(&clients).Error; err != nil {
+		t.Fatal(err)
+	}
+	if len(clients) != 2 || clients[0].ClientID != "client_id" || clients[1].ClientID != "client_id2" {
+		t.Fatalf("unexpected clients %+v", clients)
+	}
+	if !clients[0].CheckSecret("first") || !clients[1].CheckSecret("second") {
+		t.Error("unexpected secrets")
+	}
+	if clients[0].HasScope(models.ScopeClients) || clients[0].EffectiveRole("admin") != "admin" {
+		t.Errorf("unexpected scopes %v", clients[0].Scopes)
+	}
+
+	viper.Set("BOOTSTRAP_LEGACY_CLIENTS", "client_id")
+	if err := bootstrapLegacyClients(db); err == nil {
+		t.Error("expected a pair without a secret to be rejected")
+	}
+}
@@ -1,5 +1,10 @@
 package dtos
 
+import (
+	"github.com/shivamrajput1826/api-catalog/internal/jsonschema"
+	"github.com/shivamrajput1826/api-catalog/internal/models"
+)
+
 type CreateEventRequest struct {
 	Name        string `json:"name" validate:"required"`
 	Type        string `json:"type" validate:"required"`
@@ -13,22 +18,35 @@ type UpdateEventRequest struct {
 }
 
 type CreatePropertyRequest struct {
-	Name        string `json:"name" validate:"required"`
-	Type        string `json:"type" validate:"required"`
-	Description string `json:"description"`
+	Name        string              `json:"name" validate:"required"`
+	Type        models.PropertyType `json:"type" validate:"required"`
+	Items       models.PropertyType `json:"items,omitempty"`
+	Description string              `json:"description"`
+	models.PropertyConstraints
+	Properties []models.PropertyField `json:"properties,omitempty"`
 }
 
 type UpdatePropertyRequest struct {
-	Name        string `json:"name" validate:"required"`
-	Type        string `json:"type" validate:"required"`
-	Description string `json:"description"`
+	Name        string              `json:"name" validate:"required"`
+	Type        models.PropertyType `json:"type" validate:"required"`
+	Items       models.PropertyType `json:"items,omitempty"`
+	Description string              `json:"description"`
+	models.PropertyConstraints
+	Properties []models.PropertyField `json:"properties,omitempty"`
 }
 
 type TrackingPlanPropertyRequest struct {
-	Name        string `json:"name" validate:"required"`
-	Type        string `json:"type" validate:"required"`
-	Required    bool   `json:"required"`
-	Description string `json:"description"`
+	Name        string              `json:"name" validate:"required"`
+	Type        models.PropertyType `json:"type" validate:"required"`
+	Items       models.PropertyType `json:"items,omitempty"`
+	Required    bool                `json:"required"`
+	Description string              `json:"description"`
+	models.PropertyConstraints
+	Properties []models.PropertyField `json:"properties,omitempty"`
+	// KeepConstraints keeps the plan-level constraints the property already
+	// has on the event instead of replacing them, for imports that cannot
+	// express constraints.
+	KeepConstraints bool `json:"-"`
 }
 
 type TrackingPlanEventRequest struct {
@@ -39,6 +57,13 @@ type TrackingPlanEventRequest struct {
 	Type                 string                        `json:"type" validate:"required"`
 }
 
+// TrackingPlanEventPropertyRequest sets how an event in a plan uses a
+// catalog property.
+type TrackingPlanEventPropertyRequest struct {
+	Required bool `json:"required"`
+	models.PropertyConstraints
+}
+
 type CreateTrackingPlanRequest struct {
 	Name        string                     `json:"name" validate:"required"`
 	Description string                     `json:"description"`
@@ -60,3 +85,147 @@ type SuccessResponse struct {
 	Message string      `json:"message"`
 	Data    interface{} `json:"data,omitempty"`
 }
+
+type ValidatePayloadRequest struct {
+	Type       string                 `json:"type" validate:"required"`
+	Event      string                 `json:"event"`
+	Name       string                 `json:"name"`
+	Properties map[string]interface{} `json:"properties"`
+	Traits     map[string]interface{} `json:"traits"`
+}
+
+type PayloadViolation struct {
+	Code    string `json:"code"`
+	Path    string `json:"path"`
+	Message string `json:"message"`
+}
+
+type ValidatePayloadResponse struct {
+	Valid      bool               `json:"valid"`
+	Event      string             `json:"event,omitempty"`
+	Violations []PayloadViolation `json:"violations"`
+}
+
+type EventSchema struct {
+	Name   string             `json:"name"`
+	Type   string             `json:"type"`
+	Schema *jsonschema.Schema `json:"schema"`
+}
+
+type TrackingPlanSchemaResponse struct {
+	TrackingPlanID uint          `json:"tracking_plan_id"`
+	Name           string        `json:"name"`
+	Draft          string        `json:"draft"`
+	Events         []EventSchema `json:"events"`
+}
+
+type ImportTrackingPlanSchemaRequest struct {
+	Name        string               `json:"name" validate:"required"`
+	Description string               `json:"description"`
+	Schemas     []*jsonschema.Schema `json:"schemas" validate:"required"`
+}
+
+type TrackingPlanVersionSummary struct {
+	TrackingPlanID uint  `json:"tracking_plan_id"`
+	Version        int   `json:"version"`
+	CreateTime     int64 `json:"create_time"`
+}
+
+type TrackingPlanRef struct {
+	TrackingPlanID uint   `json:"tracking_plan_id"`
+	Name           string `json:"name"`
+	Version        int    `json:"version"`
+}
+
+type TrackingPlanChange struct {
+	Kind      string      `json:"kind"`
+	Event     string      `json:"event"`
+	EventType string      `json:"event_type"`
+	Property  string      `json:"property,omitempty"`
+	From      interface{} `json:"from,omitempty"`
+	To        interface{} `json:"to,omitempty"`
+}
+
+type TrackingPlanDiffResponse struct {
+	Base    TrackingPlanRef      `json:"base"`
+	Head    TrackingPlanRef      `json:"head"`
+	Changes []TrackingPlanChange `json:"changes"`
+}
+
+type ListQuery struct {
+	Limit          int    `query:"limit"`
+	Cursor         string `query:"cursor"`
+	Type           string `query:"type"`
+	NamePrefix     string `query:"name_prefix"`
+	CreatedAfter   int64  `query:"created_after"`
+	CreatedBefore  int64  `query:"created_before"`
+	UpdatedAfter   int64  `query:"updated_after"`
+	UpdatedBefore  int64  `query:"updated_before"`
+	Sort           string `query:"sort"`
+	Order          string `query:"order"`
+	Expand         string `query:"expand"`
+	IncludeDeleted bool   `query:"include_deleted"`
+}
+
+type PageResponse struct {
+	Data       interface{} `json:"data"`
+	Total      int64       `json:"total"`
+	Limit      int         `json:"limit"`
+	NextCursor string      `json:"next_cursor,omitempty"`
+	Next       string      `json:"next,omitempty"`
+}
+
+type SearchQuery struct {
+	Q     string `query:"q"`
+	Types string `query:"types"`
+	Limit int    `query:"limit"`
+}
+
+type SearchResponse struct {
+	Query   string                `json:"query"`
+	Results []models.SearchResult `json:"results"`
+}
+
+type BulkImportResult struct {
+	Line   int    `json:"line"`
+	Status string `json:"status"`
+	ID     uint   `json:"id,omitempty"`
+	Name   string `json:"name,omitempty"`
+	Error  string `json:"error,omitempty"`
+}
+
+type BulkImportResponse struct {
+	Mode      string             `json:"mode"`
+	Committed bool               `json:"committed"`
+	Created   int                `json:"created"`
+	Existing  int                `json:"existing"`
+	Failed    int                `json:"failed"`
+	Skipped   int                `json:"skipped"`
+	Results   []BulkImportResult `json:"results"`
+}
+
+type AuditQuery struct {
+	Limit         int    `query:"limit"`
+	Cursor        string `query:"cursor"`
+	Resource      string `query:"resource"`
+	ResourceID    uint   `query:"resource_id"`
+	Actor         string `query:"actor"`
+	Action        string `query:"action"`
+	CreatedAfter  int64  `query:"created_after"`
+	CreatedBefore int64  `query:"created_before"`
+	Order         string `query:"order"`
+}
+
+type CreateAPIClientRequest struct {
+	ClientID  string   `json:"client_id" validate:"required"`
+	Name      string   `json:"name"`
+	Scopes    []string `json:"scopes" validate:"required"`
+	ExpiresAt int64    `json:"expires_at,omitempty"`
+}
+
+// APIClientCredentials returns a client together with its plain secret. The
+// secret is only ever shown in the response that created or rotated it.
+type APIClientCredentials struct {
+	models.APIClient
+	Secret string `json:"secret"`
+}
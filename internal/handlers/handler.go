@@ -1,39 +1,116 @@
 package handlers
 
 import (
+	"errors"
+
 	"github.com/shivamrajput1826/api-catalog/internal/dtos"
 	"github.com/shivamrajput1826/api-catalog/internal/repositories"
 	"github.com/shivamrajput1826/api-catalog/internal/services"
 	"github.com/shivamrajput1826/api-catalog/internal/utils"
 	"github.com/shivamrajput1826/api-catalog/internal/validation"
+	"github.com/shivamrajput1826/api-catalog/internal/workspace"
+	"github.com/shivamrajput1826/api-catalog/logger"
 
 	"github.com/gofiber/fiber/v2"
 	"gorm.io/gorm"
 )
 
+var authLogger = logger.CreateLogger("ClientAuth")
+
 type Handlers struct {
+	db               *gorm.DB
+	validator        *validation.Validator
+	workspaceService *services.WorkspaceService
+	apiClientService *services.APIClientService
+}
+
+// catalogServices are the services of one request. Their repositories share
+// a session bound to the caller's workspace, so every query they run is
+// confined to it, and the changes they make are audited as the caller's.
+type catalogServices struct {
 	eventService        *services.EventService
 	propertyService     *services.PropertyService
 	trackingPlanService *services.TrackingPlanService
+	searchService       *services.SearchService
+	auditService        *services.AuditService
 }
 
 func New(db *gorm.DB) *Handlers {
+	validator := validation.New()
+
+	return &Handlers{
+		db:               db,
+		validator:        validator,
+		workspaceService: services.NewWorkspaceService(repositories.NewWorkspaceRepository(db)),
+		apiClientService: services.NewAPIClientService(repositories.NewAPIClientRepository(db), validator),
+	}
+}
+
+func newCatalogServices(db *gorm.DB, validator *validation.Validator, actor services.Actor) *catalogServices {
 	eventRepo := repositories.NewEventRepository(db)
 	propertyRepo := repositories.NewPropertyRepository(db)
 	trackingPlanRepo := repositories.NewTrackingPlanRepository(db)
+	versionRepo := repositories.NewTrackingPlanVersionRepository(db)
+	searchRepo := repositories.NewSearchRepository(db)
+	auditRepo := repositories.NewAuditRepository(db)
 	txManager := repositories.NewTransactionManager(db)
+	auditor := services.NewAuditor(actor)
 
-	validator := validation.New()
+	return &catalogServices{
+		eventService:        services.NewEventService(eventRepo, trackingPlanRepo, txManager, validator, auditor),
+		propertyService:     services.NewPropertyService(propertyRepo, trackingPlanRepo, txManager, validator, auditor),
+		trackingPlanService: services.NewTrackingPlanService(trackingPlanRepo, versionRepo, eventRepo, propertyRepo, txManager, validator, auditor),
+		searchService:       services.NewSearchService(searchRepo, validator),
+		auditService:        services.NewAuditService(auditRepo, validator),
+	}
+}
 
-	eventService := services.NewEventService(eventRepo, validator)
-	propertyService := services.NewPropertyService(propertyRepo, validator)
-	trackingPlanService := services.NewTrackingPlanService(trackingPlanRepo, eventRepo, propertyRepo, txManager, validator)
+// AuthenticateClient checks the client-id and client-secret headers against
+// the registered API clients. It caps the token's role at the client's scopes
+// and stores the scopes for RequireScope. It must run after AuthMiddleware.
+func (h *Handlers) AuthenticateClient(c *fiber.Ctx) error {
+	client, err := h.apiClientService.AuthenticateClient(utils.ClientID(c), c.Get("client-secret"))
+	if err != nil {
+		if !errors.Is(err, services.ErrInvalidClient) {
+			authLogger.Error("Failed to authenticate client", "client_id", utils.ClientID(c), "error", err)
+		}
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Unauthorized",
+		})
+	}
 
-	return &Handlers{
-		eventService:        eventService,
-		propertyService:     propertyService,
-		trackingPlanService: trackingPlanService,
+	role, _ := c.Locals("role").(string)
+	c.Locals("role", client.EffectiveRole(role))
+	c.Locals("scopes", client.Scopes)
+	return c.Next()
+}
+
+// ResolveWorkspace finds the workspace of the request's client id and binds
+// the request context to it. It must run after AuthMiddleware.
+func (h *Handlers) ResolveWorkspace(c *fiber.Ctx) error {
+	workspaceID, err := h.workspaceService.ResolveWorkspaceID(utils.ClientID(c))
+	if err != nil {
+		return err
+	}
+	c.Locals("workspace_id", workspaceID)
+	c.SetUserContext(workspace.WithID(c.UserContext(), workspaceID))
+	return c.Next()
+}
+
+// services returns the catalog services scoped to the request's workspace
+// and actor, building them once per request.
+func (h *Handlers) services(c *fiber.Ctx) *catalogServices {
+	if scoped, ok := c.Locals("services").(*catalogServices); ok {
+		return scoped
+	}
+	actor := services.Actor{
+		ID:       utils.ActorID(c),
+		Email:    utils.ActorEmail(c),
+		ClientID: utils.ClientID(c),
 	}
+	scoped := newCatalogServices(h.db.WithContext(c.UserContext()), h.validator, actor)
+	c.Locals("services", scoped)
+	return scoped
 }
 
 // Event Handlers
@@ -45,37 +122,91 @@ func New(db *gorm.DB) *Handlers {
 // @Produce      json
 // @Param        event  body  dtos.CreateEventRequest  true  "Event to create"
 // @Success      201  {object}  models.Event
+// @Header       201  {string}  ETag  "Current version of the resource"
 // @Failure      400  {object}  fiber.Map
-// @Router       /events [post]
+// @Router       /api/v1/events [post]
 func (h *Handlers) CreateEvent(c *fiber.Ctx) error {
 	var req dtos.CreateEventRequest
 	if err := c.BodyParser(&req); err != nil {
 		return fiber.NewError(fiber.StatusBadRequest, "Invalid JSON payload")
 	}
 
-	event, err := h.eventService.CreateEvent(&req)
+	event, err := h.services(c).eventService.CreateEvent(&req)
 	if err != nil {
 		return err
 	}
 
+	c.Set(fiber.HeaderETag, utils.ETag(event.Version))
 	return c.Status(fiber.StatusCreated).JSON(event)
 }
 
+// BulkImportEvents godoc
+// @Summary      Bulk import events
+// @Description  Create many events from an NDJSON or CSV upload, sent as the request body or as the "file" field of a multipart form. CSV uploads start with a header row naming the columns (name, type, description). Every row is validated like a single create, and the response reports per row whether it was created, already existed or failed. In atomic mode (the default) nothing is written unless every row succeeds; in best_effort mode each valid row is saved on its own.
+// @Tags         events
+// @Accept       application/x-ndjson
+// @Accept       text/csv
+// @Accept       multipart/form-data
+// @Produce      json
+// @Param        mode  query     string  false  "atomic or best_effort"
+// @Param        file  formData  file    false  "NDJSON or CSV file"
+// @Success      200   {object}  dtos.BulkImportResponse
+// @Failure      400   {object}  fiber.Map
+// @Failure      415   {object}  fiber.Map
+// @Failure      422   {object}  dtos.BulkImportResponse
+// @Router       /api/v1/events/bulk [post]
+func (h *Handlers) BulkImportEvents(c *fiber.Ctx) error {
+	body, contentType, err := utils.UploadBody(c)
+	if err != nil {
+		return err
+	}
+
+	result, err := h.services(c).eventService.BulkImportEvents(body, contentType, c.Query("mode"))
+	if err != nil {
+		return err
+	}
+
+	if !result.Committed {
+		return c.Status(fiber.StatusUnprocessableEntity).JSON(result)
+	}
+	return c.JSON(result)
+}
+
 // GetEvents godoc
-// @Summary      Get all events
-// @Description  Retrieve a list of all events
+// @Summary      List events
+// @Description  Retrieve a page of events with optional filters and sorting
 // @Tags         events
 // @Produce      json
-// @Success      200  {array}  models.Event
-// @Failure      500  {object}  fiber.Map
-// @Router       /events [get]
+// @Param        limit           query     int     false  "Page size (default 50, max 200)"
+// @Param        cursor          query     string  false  "Cursor returned as next_cursor by the previous page"
+// @Param        type            query     string  false  "Only return items of this type"
+// @Param        name_prefix     query     string  false  "Only return items whose name starts with this prefix"
+// @Param        created_after   query     int     false  "Only return items created at or after this unix time"
+// @Param        created_before  query     int     false  "Only return items created before this unix time"
+// @Param        updated_after   query     int     false  "Only return items updated at or after this unix time"
+// @Param        updated_before  query     int     false  "Only return items updated before this unix time"
+// @Param        sort            query     string  false  "Sort field: id, name, create_time or update_time"
+// @Param        order           query     string  false  "Sort order: asc or desc"
+// @Param        include_deleted query     bool    false  "Also return soft-deleted items"
+// @Success      200             {object}  dtos.PageResponse
+// @Failure      400             {object}  fiber.Map
+// @Failure      500             {object}  fiber.Map
+// @Router       /api/v1/events [get]
 func (h *Handlers) GetEvents(c *fiber.Ctx) error {
-	events, err := h.eventService.GetAllEvents()
+	var query dtos.ListQuery
+	if err := c.QueryParser(&query); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid query parameters")
+	}
+
+	page, err := h.services(c).eventService.ListEvents(&query)
 	if err != nil {
 		return err
 	}
+	if page.NextCursor != "" {
+		page.Next = utils.NextPageLink(c, page.NextCursor)
+	}
 
-	return c.JSON(events)
+	return c.JSON(page)
 }
 
 // GetEvent godoc
@@ -85,35 +216,64 @@ func (h *Handlers) GetEvents(c *fiber.Ctx) error {
 // @Produce      json
 // @Param        id   path      int  true  "Event ID"
 // @Success      200  {object}  models.Event
+// @Header       200  {string}  ETag  "Current version of the resource"
 // @Failure      400  {object}  fiber.Map
 // @Failure      404  {object}  fiber.Map
-// @Router       /events/{id} [get]
+// @Router       /api/v1/events/{id} [get]
 func (h *Handlers) GetEvent(c *fiber.Ctx) error {
 	id, err := utils.ParseUintID(c.Params("id"))
 	if err != nil {
 		return err
 	}
 
-	event, err := h.eventService.GetEventByID(id)
+	event, err := h.services(c).eventService.GetEventByID(id)
 	if err != nil {
 		return err
 	}
 
+	c.Set(fiber.HeaderETag, utils.ETag(event.Version))
 	return c.JSON(event)
 }
 
+// GetEventUsages godoc
+// @Summary      List where an event is used
+// @Description  List every tracking plan that uses the event, with the per-plan event and additionalProperties setting
+// @Tags         events
+// @Produce      json
+// @Param        id   path      int  true  "Event ID"
+// @Success      200  {array}   models.Usage
+// @Failure      400  {object}  fiber.Map
+// @Failure      404  {object}  fiber.Map
+// @Router       /api/v1/events/{id}/usages [get]
+func (h *Handlers) GetEventUsages(c *fiber.Ctx) error {
+	id, err := utils.ParseUintID(c.Params("id"))
+	if err != nil {
+		return err
+	}
+
+	usages, err := h.services(c).eventService.GetEventUsages(id)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(usages)
+}
+
 // UpdateEvent godoc
 // @Summary      Update an event
 // @Description  Update an event by its ID
 // @Tags         events
 // @Accept       json
 // @Produce      json
-// @Param        id     path      int                      true  "Event ID"
-// @Param        event  body      dtos.UpdateEventRequest  true  "Event update payload"
-// @Success      200    {object}  models.Event
-// @Failure      400    {object}  fiber.Map
-// @Failure      404    {object}  fiber.Map
-// @Router       /events/{id} [put]
+// @Param        id        path    int                      true   "Event ID"
+// @Param        event     body    dtos.UpdateEventRequest  true   "Event update payload"
+// @Param        If-Match  header  string                   false  "ETag the update is based on"
+// @Success      200  {object}  models.Event
+// @Header       200  {string}  ETag  "Current version of the resource"
+// @Failure      400  {object}  fiber.Map
+// @Failure      404  {object}  fiber.Map
+// @Failure      412  {object}  fiber.Map
+// @Router       /api/v1/events/{id} [put]
 func (h *Handlers) UpdateEvent(c *fiber.Ctx) error {
 	id, err := utils.ParseUintID(c.Params("id"))
 	if err != nil {
@@ -125,36 +285,101 @@ func (h *Handlers) UpdateEvent(c *fiber.Ctx) error {
 		return fiber.NewError(fiber.StatusBadRequest, "Invalid JSON payload")
 	}
 
-	event, err := h.eventService.UpdateEvent(id, &req)
+	event, err := h.services(c).eventService.UpdateEvent(id, &req, utils.IfMatchVersions(c))
+	if err != nil {
+		return err
+	}
+
+	c.Set(fiber.HeaderETag, utils.ETag(event.Version))
+	return c.JSON(event)
+}
+
+// PatchEvent godoc
+// @Summary      Patch an event
+// @Description  Partially update an event with an RFC 7396 merge patch or an RFC 6902 JSON Patch. The patch applies to the update request form of the event, and the result is validated like a PUT.
+// @Tags         events
+// @Accept       application/merge-patch+json
+// @Accept       application/json-patch+json
+// @Produce      json
+// @Param        id        path    int     true   "Event ID"
+// @Param        patch     body    object  true   "Merge patch or JSON Patch document"
+// @Param        If-Match  header  string  false  "ETag the patch is based on"
+// @Success      200  {object}  models.Event
+// @Header       200  {string}  ETag  "Current version of the resource"
+// @Failure      400  {object}  fiber.Map
+// @Failure      404  {object}  fiber.Map
+// @Failure      409  {object}  fiber.Map
+// @Failure      412  {object}  fiber.Map
+// @Failure      415  {object}  fiber.Map
+// @Router       /api/v1/events/{id} [patch]
+func (h *Handlers) PatchEvent(c *fiber.Ctx) error {
+	id, err := utils.ParseUintID(c.Params("id"))
+	if err != nil {
+		return err
+	}
+
+	event, err := h.services(c).eventService.PatchEvent(id, c.Body(), utils.MediaType(c), utils.IfMatchVersions(c))
 	if err != nil {
 		return err
 	}
 
+	c.Set(fiber.HeaderETag, utils.ETag(event.Version))
 	return c.JSON(event)
 }
 
 // DeleteEvent godoc
 // @Summary      Delete an event
-// @Description  Delete an event by its ID
+// @Description  Soft delete an event by its ID. Returns 409 listing the referencing tracking plans while tracking plans still include it, unless cascade=true detaches it from them.
 // @Tags         events
-// @Param        id   path      int  true  "Event ID"
+// @Param        id        path    int     true   "Event ID"
+// @Param        cascade   query   bool    false  "Detach the event from referencing tracking plans"
+// @Param        If-Match  header  string  false  "ETag the delete is based on"
 // @Success      204  {string}  string  "No Content"
 // @Failure      400  {object}  fiber.Map
 // @Failure      404  {object}  fiber.Map
-// @Router       /events/{id} [delete]
+// @Failure      409  {object}  fiber.Map
+// @Failure      412  {object}  fiber.Map
+// @Router       /api/v1/events/{id} [delete]
 func (h *Handlers) DeleteEvent(c *fiber.Ctx) error {
 	id, err := utils.ParseUintID(c.Params("id"))
 	if err != nil {
 		return err
 	}
 
-	if err := h.eventService.DeleteEvent(id); err != nil {
-		return err
+	if err := h.services(c).eventService.DeleteEvent(id, c.QueryBool("cascade"), utils.ActorID(c), utils.IfMatchVersions(c)); err != nil {
+		return referenceConflictResponse(c, err)
 	}
 
 	return c.SendStatus(fiber.StatusNoContent)
 }
 
+// RestoreEvent godoc
+// @Summary      Restore an event
+// @Description  Bring back a soft-deleted event
+// @Tags         events
+// @Produce      json
+// @Param        id   path      int  true  "Event ID"
+// @Success      200  {object}  models.Event
+// @Header       200  {string}  ETag  "Current version of the resource"
+// @Failure      400  {object}  fiber.Map
+// @Failure      404  {object}  fiber.Map
+// @Failure      409  {object}  fiber.Map
+// @Router       /api/v1/events/{id}/restore [post]
+func (h *Handlers) RestoreEvent(c *fiber.Ctx) error {
+	id, err := utils.ParseUintID(c.Params("id"))
+	if err != nil {
+		return err
+	}
+
+	event, err := h.services(c).eventService.RestoreEvent(id)
+	if err != nil {
+		return err
+	}
+
+	c.Set(fiber.HeaderETag, utils.ETag(event.Version))
+	return c.JSON(event)
+}
+
 // CreateProperty godoc
 // @Summary      Create a new property
 // @Description  Create a new property with name, type, and description
@@ -163,37 +388,91 @@ func (h *Handlers) DeleteEvent(c *fiber.Ctx) error {
 // @Produce      json
 // @Param        property  body  dtos.CreatePropertyRequest  true  "Property to create"
 // @Success      201  {object}  models.Property
+// @Header       201  {string}  ETag  "Current version of the resource"
 // @Failure      400  {object}  fiber.Map
-// @Router       /properties [post]
+// @Router       /api/v1/properties [post]
 func (h *Handlers) CreateProperty(c *fiber.Ctx) error {
 	var req dtos.CreatePropertyRequest
 	if err := c.BodyParser(&req); err != nil {
 		return fiber.NewError(fiber.StatusBadRequest, "Invalid JSON payload")
 	}
 
-	property, err := h.propertyService.CreateProperty(&req)
+	property, err := h.services(c).propertyService.CreateProperty(&req)
 	if err != nil {
 		return err
 	}
 
+	c.Set(fiber.HeaderETag, utils.ETag(property.Version))
 	return c.Status(fiber.StatusCreated).JSON(property)
 }
 
+// BulkImportProperties godoc
+// @Summary      Bulk import properties
+// @Description  Create many properties from an NDJSON or CSV upload, sent as the request body or as the "file" field of a multipart form. CSV uploads start with a header row naming the columns (name, type, items, description, enum, pattern, minimum, maximum, minLength, maxLength, format, properties). Every row is validated like a single create, and the response reports per row whether it was created, already existed or failed. In atomic mode (the default) nothing is written unless every row succeeds; in best_effort mode each valid row is saved on its own.
+// @Tags         properties
+// @Accept       application/x-ndjson
+// @Accept       text/csv
+// @Accept       multipart/form-data
+// @Produce      json
+// @Param        mode  query     string  false  "atomic or best_effort"
+// @Param        file  formData  file    false  "NDJSON or CSV file"
+// @Success      200   {object}  dtos.BulkImportResponse
+// @Failure      400   {object}  fiber.Map
+// @Failure      415   {object}  fiber.Map
+// @Failure      422   {object}  dtos.BulkImportResponse
+// @Router       /api/v1/properties/bulk [post]
+func (h *Handlers) BulkImportProperties(c *fiber.Ctx) error {
+	body, contentType, err := utils.UploadBody(c)
+	if err != nil {
+		return err
+	}
+
+	result, err := h.services(c).propertyService.BulkImportProperties(body, contentType, c.Query("mode"))
+	if err != nil {
+		return err
+	}
+
+	if !result.Committed {
+		return c.Status(fiber.StatusUnprocessableEntity).JSON(result)
+	}
+	return c.JSON(result)
+}
+
 // GetProperties godoc
-// @Summary      Get all properties
-// @Description  Retrieve a list of all properties
+// @Summary      List properties
+// @Description  Retrieve a page of properties with optional filters and sorting
 // @Tags         properties
 // @Produce      json
-// @Success      200  {array}  models.Property
-// @Failure      500  {object}  fiber.Map
-// @Router       /properties [get]
+// @Param        limit           query     int     false  "Page size (default 50, max 200)"
+// @Param        cursor          query     string  false  "Cursor returned as next_cursor by the previous page"
+// @Param        type            query     string  false  "Only return items of this type"
+// @Param        name_prefix     query     string  false  "Only return items whose name starts with this prefix"
+// @Param        created_after   query     int     false  "Only return items created at or after this unix time"
+// @Param        created_before  query     int     false  "Only return items created before this unix time"
+// @Param        updated_after   query     int     false  "Only return items updated at or after this unix time"
+// @Param        updated_before  query     int     false  "Only return items updated before this unix time"
+// @Param        sort            query     string  false  "Sort field: id, name, create_time or update_time"
+// @Param        order           query     string  false  "Sort order: asc or desc"
+// @Param        include_deleted query     bool    false  "Also return soft-deleted items"
+// @Success      200             {object}  dtos.PageResponse
+// @Failure      400             {object}  fiber.Map
+// @Failure      500             {object}  fiber.Map
+// @Router       /api/v1/properties [get]
 func (h *Handlers) GetProperties(c *fiber.Ctx) error {
-	properties, err := h.propertyService.GetAllProperties()
+	var query dtos.ListQuery
+	if err := c.QueryParser(&query); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid query parameters")
+	}
+
+	page, err := h.services(c).propertyService.ListProperties(&query)
 	if err != nil {
 		return err
 	}
+	if page.NextCursor != "" {
+		page.Next = utils.NextPageLink(c, page.NextCursor)
+	}
 
-	return c.JSON(properties)
+	return c.JSON(page)
 }
 
 // GetProperty godoc
@@ -203,35 +482,64 @@ func (h *Handlers) GetProperties(c *fiber.Ctx) error {
 // @Produce      json
 // @Param        id   path      int  true  "Property ID"
 // @Success      200  {object}  models.Property
+// @Header       200  {string}  ETag  "Current version of the resource"
 // @Failure      400  {object}  fiber.Map
 // @Failure      404  {object}  fiber.Map
-// @Router       /properties/{id} [get]
+// @Router       /api/v1/properties/{id} [get]
 func (h *Handlers) GetProperty(c *fiber.Ctx) error {
 	id, err := utils.ParseUintID(c.Params("id"))
 	if err != nil {
 		return err
 	}
 
-	property, err := h.propertyService.GetPropertyByID(id)
+	property, err := h.services(c).propertyService.GetPropertyByID(id)
 	if err != nil {
 		return err
 	}
 
+	c.Set(fiber.HeaderETag, utils.ETag(property.Version))
 	return c.JSON(property)
 }
 
+// GetPropertyUsages godoc
+// @Summary      List where a property is used
+// @Description  List every tracking plan that uses the property, with the per-plan event, required flag and additionalProperties setting
+// @Tags         properties
+// @Produce      json
+// @Param        id   path      int  true  "Property ID"
+// @Success      200  {array}   models.Usage
+// @Failure      400  {object}  fiber.Map
+// @Failure      404  {object}  fiber.Map
+// @Router       /api/v1/properties/{id}/usages [get]
+func (h *Handlers) GetPropertyUsages(c *fiber.Ctx) error {
+	id, err := utils.ParseUintID(c.Params("id"))
+	if err != nil {
+		return err
+	}
+
+	usages, err := h.services(c).propertyService.GetPropertyUsages(id)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(usages)
+}
+
 // UpdateProperty godoc
 // @Summary      Update a property
 // @Description  Update a property by its ID
 // @Tags         properties
 // @Accept       json
 // @Produce      json
-// @Param        id        path      int                          true  "Property ID"
-// @Param        property  body      dtos.UpdatePropertyRequest   true  "Property update payload"
-// @Success      200       {object}  models.Property
-// @Failure      400       {object}  fiber.Map
-// @Failure      404       {object}  fiber.Map
-// @Router       /properties/{id} [put]
+// @Param        id        path    int                         true   "Property ID"
+// @Param        property  body    dtos.UpdatePropertyRequest  true   "Property update payload"
+// @Param        If-Match  header  string                      false  "ETag the update is based on"
+// @Success      200  {object}  models.Property
+// @Header       200  {string}  ETag  "Current version of the resource"
+// @Failure      400  {object}  fiber.Map
+// @Failure      404  {object}  fiber.Map
+// @Failure      412  {object}  fiber.Map
+// @Router       /api/v1/properties/{id} [put]
 func (h *Handlers) UpdateProperty(c *fiber.Ctx) error {
 	id, err := utils.ParseUintID(c.Params("id"))
 	if err != nil {
@@ -243,36 +551,101 @@ func (h *Handlers) UpdateProperty(c *fiber.Ctx) error {
 		return fiber.NewError(fiber.StatusBadRequest, "Invalid JSON payload")
 	}
 
-	property, err := h.propertyService.UpdateProperty(id, &req)
+	property, err := h.services(c).propertyService.UpdateProperty(id, &req, utils.IfMatchVersions(c))
+	if err != nil {
+		return err
+	}
+
+	c.Set(fiber.HeaderETag, utils.ETag(property.Version))
+	return c.JSON(property)
+}
+
+// PatchProperty godoc
+// @Summary      Patch a property
+// @Description  Partially update a property with an RFC 7396 merge patch or an RFC 6902 JSON Patch. The patch applies to the update request form of the property, and the result is validated like a PUT.
+// @Tags         properties
+// @Accept       application/merge-patch+json
+// @Accept       application/json-patch+json
+// @Produce      json
+// @Param        id        path    int     true   "Property ID"
+// @Param        patch     body    object  true   "Merge patch or JSON Patch document"
+// @Param        If-Match  header  string  false  "ETag the patch is based on"
+// @Success      200  {object}  models.Property
+// @Header       200  {string}  ETag  "Current version of the resource"
+// @Failure      400  {object}  fiber.Map
+// @Failure      404  {object}  fiber.Map
+// @Failure      409  {object}  fiber.Map
+// @Failure      412  {object}  fiber.Map
+// @Failure      415  {object}  fiber.Map
+// @Router       /api/v1/properties/{id} [patch]
+func (h *Handlers) PatchProperty(c *fiber.Ctx) error {
+	id, err := utils.ParseUintID(c.Params("id"))
+	if err != nil {
+		return err
+	}
+
+	property, err := h.services(c).propertyService.PatchProperty(id, c.Body(), utils.MediaType(c), utils.IfMatchVersions(c))
 	if err != nil {
 		return err
 	}
 
+	c.Set(fiber.HeaderETag, utils.ETag(property.Version))
 	return c.JSON(property)
 }
 
 // DeleteProperty godoc
 // @Summary      Delete a property
-// @Description  Delete a property by its ID
+// @Description  Soft delete a property by its ID. Returns 409 listing the referencing tracking plans while tracking plan events still use it, unless cascade=true detaches it from them.
 // @Tags         properties
-// @Param        id   path      int  true  "Property ID"
+// @Param        id        path    int     true   "Property ID"
+// @Param        cascade   query   bool    false  "Detach the property from referencing tracking plans"
+// @Param        If-Match  header  string  false  "ETag the delete is based on"
 // @Success      204  {string}  string  "No Content"
 // @Failure      400  {object}  fiber.Map
 // @Failure      404  {object}  fiber.Map
-// @Router       /properties/{id} [delete]
+// @Failure      409  {object}  fiber.Map
+// @Failure      412  {object}  fiber.Map
+// @Router       /api/v1/properties/{id} [delete]
 func (h *Handlers) DeleteProperty(c *fiber.Ctx) error {
 	id, err := utils.ParseUintID(c.Params("id"))
 	if err != nil {
 		return err
 	}
 
-	if err := h.propertyService.DeleteProperty(id); err != nil {
-		return err
+	if err := h.services(c).propertyService.DeleteProperty(id, c.QueryBool("cascade"), utils.ActorID(c), utils.IfMatchVersions(c)); err != nil {
+		return referenceConflictResponse(c, err)
 	}
 
 	return c.SendStatus(fiber.StatusNoContent)
 }
 
+// RestoreProperty godoc
+// @Summary      Restore a property
+// @Description  Bring back a soft-deleted property
+// @Tags         properties
+// @Produce      json
+// @Param        id   path      int  true  "Property ID"
+// @Success      200  {object}  models.Property
+// @Header       200  {string}  ETag  "Current version of the resource"
+// @Failure      400  {object}  fiber.Map
+// @Failure      404  {object}  fiber.Map
+// @Failure      409  {object}  fiber.Map
+// @Router       /api/v1/properties/{id}/restore [post]
+func (h *Handlers) RestoreProperty(c *fiber.Ctx) error {
+	id, err := utils.ParseUintID(c.Params("id"))
+	if err != nil {
+		return err
+	}
+
+	property, err := h.services(c).propertyService.RestoreProperty(id)
+	if err != nil {
+		return err
+	}
+
+	c.Set(fiber.HeaderETag, utils.ETag(property.Version))
+	return c.JSON(property)
+}
+
 // CreateTrackingPlan godoc
 // @Summary      Create a new tracking plan
 // @Description  Create a new tracking plan with events and properties
@@ -281,37 +654,59 @@ func (h *Handlers) DeleteProperty(c *fiber.Ctx) error {
 // @Produce      json
 // @Param        trackingPlan  body  dtos.CreateTrackingPlanRequest  true  "Tracking plan to create"
 // @Success      201  {object}  models.TrackingPlan
+// @Header       201  {string}  ETag  "Current version of the resource"
 // @Failure      400  {object}  fiber.Map
-// @Router       /tracking-plans [post]
+// @Router       /api/v1/tracking-plans [post]
 func (h *Handlers) CreateTrackingPlan(c *fiber.Ctx) error {
 	var req dtos.CreateTrackingPlanRequest
 	if err := c.BodyParser(&req); err != nil {
 		return fiber.NewError(fiber.StatusBadRequest, "Invalid JSON payload")
 	}
 
-	plan, err := h.trackingPlanService.CreateTrackingPlan(&req)
+	plan, err := h.services(c).trackingPlanService.CreateTrackingPlan(&req)
 	if err != nil {
 		return err
 	}
 
+	c.Set(fiber.HeaderETag, utils.ETag(plan.Version))
 	return c.Status(fiber.StatusCreated).JSON(plan)
 }
 
 // GetTrackingPlans godoc
-// @Summary      Get all tracking plans
-// @Description  Retrieve a list of all tracking plans
+// @Summary      List tracking plans
+// @Description  Retrieve a page of tracking plans with optional filters and sorting. Events are only included with expand=events
 // @Tags         tracking-plans
 // @Produce      json
-// @Success      200  {array}  models.TrackingPlan
-// @Failure      500  {object}  fiber.Map
-// @Router       /tracking-plans [get]
+// @Param        limit           query     int     false  "Page size (default 50, max 200)"
+// @Param        cursor          query     string  false  "Cursor returned as next_cursor by the previous page"
+// @Param        expand          query     string  false  "Set to events to include nested events and properties"
+// @Param        name_prefix     query     string  false  "Only return items whose name starts with this prefix"
+// @Param        created_after   query     int     false  "Only return items created at or after this unix time"
+// @Param        created_before  query     int     false  "Only return items created before this unix time"
+// @Param        updated_after   query     int     false  "Only return items updated at or after this unix time"
+// @Param        updated_before  query     int     false  "Only return items updated before this unix time"
+// @Param        sort            query     string  false  "Sort field: id, name, create_time or update_time"
+// @Param        order           query     string  false  "Sort order: asc or desc"
+// @Param        include_deleted query     bool    false  "Also return soft-deleted items"
+// @Success      200             {object}  dtos.PageResponse
+// @Failure      400             {object}  fiber.Map
+// @Failure      500             {object}  fiber.Map
+// @Router       /api/v1/tracking-plans [get]
 func (h *Handlers) GetTrackingPlans(c *fiber.Ctx) error {
-	plans, err := h.trackingPlanService.GetAllTrackingPlans()
+	var query dtos.ListQuery
+	if err := c.QueryParser(&query); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid query parameters")
+	}
+
+	page, err := h.services(c).trackingPlanService.ListTrackingPlans(&query)
 	if err != nil {
 		return err
 	}
+	if page.NextCursor != "" {
+		page.Next = utils.NextPageLink(c, page.NextCursor)
+	}
 
-	return c.JSON(plans)
+	return c.JSON(page)
 }
 
 // GetTrackingPlan godoc
@@ -321,20 +716,22 @@ func (h *Handlers) GetTrackingPlans(c *fiber.Ctx) error {
 // @Produce      json
 // @Param        id   path      int  true  "Tracking Plan ID"
 // @Success      200  {object}  models.TrackingPlan
+// @Header       200  {string}  ETag  "Current version of the resource"
 // @Failure      400  {object}  fiber.Map
 // @Failure      404  {object}  fiber.Map
-// @Router       /tracking-plans/{id} [get]
+// @Router       /api/v1/tracking-plans/{id} [get]
 func (h *Handlers) GetTrackingPlan(c *fiber.Ctx) error {
 	id, err := utils.ParseUintID(c.Params("id"))
 	if err != nil {
 		return err
 	}
 
-	plan, err := h.trackingPlanService.GetTrackingPlanByID(id)
+	plan, err := h.services(c).trackingPlanService.GetTrackingPlanByID(id)
 	if err != nil {
 		return err
 	}
 
+	c.Set(fiber.HeaderETag, utils.ETag(plan.Version))
 	return c.JSON(plan)
 }
 
@@ -344,12 +741,15 @@ func (h *Handlers) GetTrackingPlan(c *fiber.Ctx) error {
 // @Tags         tracking-plans
 // @Accept       json
 // @Produce      json
-// @Param        id            path      int                             true  "Tracking Plan ID"
-// @Param        trackingPlan  body      dtos.UpdateTrackingPlanRequest  true  "Tracking plan update payload"
-// @Success      200           {object}  models.TrackingPlan
-// @Failure      400           {object}  fiber.Map
-// @Failure      404           {object}  fiber.Map
-// @Router       /tracking-plans/{id} [put]
+// @Param        id            path    int                             true   "Tracking Plan ID"
+// @Param        trackingPlan  body    dtos.UpdateTrackingPlanRequest  true   "Tracking plan update payload"
+// @Param        If-Match      header  string                          false  "ETag the update is based on"
+// @Success      200  {object}  models.TrackingPlan
+// @Header       200  {string}  ETag  "Current version of the resource"
+// @Failure      400  {object}  fiber.Map
+// @Failure      404  {object}  fiber.Map
+// @Failure      412  {object}  fiber.Map
+// @Router       /api/v1/tracking-plans/{id} [put]
 func (h *Handlers) UpdateTrackingPlan(c *fiber.Ctx) error {
 	id, err := utils.ParseUintID(c.Params("id"))
 	if err != nil {
@@ -361,47 +761,670 @@ func (h *Handlers) UpdateTrackingPlan(c *fiber.Ctx) error {
 		return fiber.NewError(fiber.StatusBadRequest, "Invalid JSON payload")
 	}
 
-	plan, err := h.trackingPlanService.UpdateTrackingPlan(id, &req)
+	plan, err := h.services(c).trackingPlanService.UpdateTrackingPlan(id, &req, utils.IfMatchVersions(c))
+	if err != nil {
+		return err
+	}
+
+	c.Set(fiber.HeaderETag, utils.ETag(plan.Version))
+	return c.JSON(plan)
+}
+
+// PatchTrackingPlan godoc
+// @Summary      Patch a tracking plan
+// @Description  Partially update a tracking plan with an RFC 7396 merge patch or an RFC 6902 JSON Patch. The patch applies to the update request form of the tracking plan, and the result is validated like a PUT.
+// @Tags         tracking-plans
+// @Accept       application/merge-patch+json
+// @Accept       application/json-patch+json
+// @Produce      json
+// @Param        id        path    int     true   "Tracking Plan ID"
+// @Param        patch     body    object  true   "Merge patch or JSON Patch document"
+// @Param        If-Match  header  string  false  "ETag the patch is based on"
+// @Success      200  {object}  models.TrackingPlan
+// @Header       200  {string}  ETag  "Current version of the resource"
+// @Failure      400  {object}  fiber.Map
+// @Failure      404  {object}  fiber.Map
+// @Failure      409  {object}  fiber.Map
+// @Failure      412  {object}  fiber.Map
+// @Failure      415  {object}  fiber.Map
+// @Router       /api/v1/tracking-plans/{id} [patch]
+func (h *Handlers) PatchTrackingPlan(c *fiber.Ctx) error {
+	id, err := utils.ParseUintID(c.Params("id"))
+	if err != nil {
+		return err
+	}
+
+	plan, err := h.services(c).trackingPlanService.PatchTrackingPlan(id, c.Body(), utils.MediaType(c), utils.IfMatchVersions(c))
 	if err != nil {
 		return err
 	}
 
+	c.Set(fiber.HeaderETag, utils.ETag(plan.Version))
 	return c.JSON(plan)
 }
 
 // DeleteTrackingPlan godoc
 // @Summary      Delete a tracking plan
-// @Description  Delete a tracking plan by its ID
+// @Description  Soft delete a tracking plan by its ID. It can be brought back with the restore endpoint.
 // @Tags         tracking-plans
-// @Param        id   path      int  true  "Tracking Plan ID"
+// @Param        id        path    int     true   "Tracking Plan ID"
+// @Param        If-Match  header  string  false  "ETag the delete is based on"
 // @Success      204  {string}  string  "No Content"
 // @Failure      400  {object}  fiber.Map
 // @Failure      404  {object}  fiber.Map
-// @Router       /tracking-plans/{id} [delete]
+// @Failure      412  {object}  fiber.Map
+// @Router       /api/v1/tracking-plans/{id} [delete]
 func (h *Handlers) DeleteTrackingPlan(c *fiber.Ctx) error {
 	id, err := utils.ParseUintID(c.Params("id"))
 	if err != nil {
 		return err
 	}
 
-	if err := h.trackingPlanService.DeleteTrackingPlan(id); err != nil {
+	if err := h.services(c).trackingPlanService.DeleteTrackingPlan(id, utils.ActorID(c), utils.IfMatchVersions(c)); err != nil {
 		return err
 	}
 
 	return c.SendStatus(fiber.StatusNoContent)
 }
 
-// HealthCheck godoc
-// @Summary      Health check
-// @Description  Returns the health status of the service
-// @Tags         health
+// RestoreTrackingPlan godoc
+// @Summary      Restore a tracking plan
+// @Description  Bring back a soft-deleted tracking plan
+// @Tags         tracking-plans
 // @Produce      json
-// @Success      200  {object}  fiber.Map
-// @Router       /health [get]
-func (h *Handlers) HealthCheck(c *fiber.Ctx) error {
-	return c.JSON(fiber.Map{
-		"status":  "healthy",
-		"service": "datacatalog",
-		"version": "1.0.0",
-	})
+// @Param        id   path      int  true  "Tracking Plan ID"
+// @Success      200  {object}  models.TrackingPlan
+// @Header       200  {string}  ETag  "Current version of the resource"
+// @Failure      400  {object}  fiber.Map
+// @Failure      404  {object}  fiber.Map
+// @Failure      409  {object}  fiber.Map
+// @Router       /api/v1/tracking-plans/{id}/restore [post]
+func (h *Handlers) RestoreTrackingPlan(c *fiber.Ctx) error {
+	id, err := utils.ParseUintID(c.Params("id"))
+	if err != nil {
+		return err
+	}
+
+	plan, err := h.services(c).trackingPlanService.RestoreTrackingPlan(id)
+	if err != nil {
+		return err
+	}
+
+	c.Set(fiber.HeaderETag, utils.ETag(plan.Version))
+	return c.JSON(plan)
+}
+
+// AddTrackingPlanEvent godoc
+// @Summary      Add an event to a tracking plan
+// @Description  Add one event, with its properties, to a tracking plan. Events and properties missing from the catalog are created.
+// @Tags         tracking-plans
+// @Accept       json
+// @Produce      json
+// @Param        id        path    int                            true   "Tracking Plan ID"
+// @Param        event     body    dtos.TrackingPlanEventRequest  true   "Event to add"
+// @Param        If-Match  header  string                         false  "ETag the change is based on"
+// @Success      201  {object}  models.TrackingPlan
+// @Header       201  {string}  ETag  "Current version of the resource"
+// @Failure      400  {object}  fiber.Map
+// @Failure      404  {object}  fiber.Map
+// @Failure      409  {object}  fiber.Map
+// @Failure      412  {object}  fiber.Map
+// @Router       /api/v1/tracking-plans/{id}/events [post]
+func (h *Handlers) AddTrackingPlanEvent(c *fiber.Ctx) error {
+	id, err := utils.ParseUintID(c.Params("id"))
+	if err != nil {
+		return err
+	}
+
+	var req dtos.TrackingPlanEventRequest
+	if err := c.BodyParser(&req); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid JSON payload")
+	}
+
+	plan, err := h.services(c).trackingPlanService.AddTrackingPlanEvent(id, &req, utils.IfMatchVersions(c))
+	if err != nil {
+		return err
+	}
+
+	c.Set(fiber.HeaderETag, utils.ETag(plan.Version))
+	return c.Status(fiber.StatusCreated).JSON(plan)
+}
+
+// RemoveTrackingPlanEvent godoc
+// @Summary      Remove an event from a tracking plan
+// @Description  Take an event out of a tracking plan. The event stays in the catalog.
+// @Tags         tracking-plans
+// @Param        id        path    int     true   "Tracking Plan ID"
+// @Param        eventId   path    int     true   "Event ID"
+// @Param        If-Match  header  string  false  "ETag the change is based on"
+// @Success      204  {string}  string  "No Content"
+// @Header       204  {string}  ETag    "Current version of the resource"
+// @Failure      400  {object}  fiber.Map
+// @Failure      404  {object}  fiber.Map
+// @Failure      412  {object}  fiber.Map
+// @Router       /api/v1/tracking-plans/{id}/events/{eventId} [delete]
+func (h *Handlers) RemoveTrackingPlanEvent(c *fiber.Ctx) error {
+	id, err := utils.ParseUintID(c.Params("id"))
+	if err != nil {
+		return err
+	}
+
+	eventID, err := utils.ParseUintID(c.Params("eventId"))
+	if err != nil {
+		return err
+	}
+
+	plan, err := h.services(c).trackingPlanService.RemoveTrackingPlanEvent(id, eventID, utils.IfMatchVersions(c))
+	if err != nil {
+		return err
+	}
+
+	c.Set(fiber.HeaderETag, utils.ETag(plan.Version))
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// SetTrackingPlanEventProperty godoc
+// @Summary      Set a property on a tracking plan event
+// @Description  Add a catalog property to an event of a tracking plan, or update whether it is required and its plan-level constraints
+// @Tags         tracking-plans
+// @Accept       json
+// @Produce      json
+// @Param        id          path    int                                    true   "Tracking Plan ID"
+// @Param        eventId     path    int                                    true   "Event ID"
+// @Param        propertyId  path    int                                    true   "Property ID"
+// @Param        property    body    dtos.TrackingPlanEventPropertyRequest  true   "How the event uses the property"
+// @Param        If-Match    header  string                                 false  "ETag the change is based on"
+// @Success      200  {object}  models.TrackingPlan
+// @Header       200  {string}  ETag  "Current version of the resource"
+// @Failure      400  {object}  fiber.Map
+// @Failure      404  {object}  fiber.Map
+// @Failure      412  {object}  fiber.Map
+// @Router       /api/v1/tracking-plans/{id}/events/{eventId}/properties/{propertyId} [put]
+func (h *Handlers) SetTrackingPlanEventProperty(c *fiber.Ctx) error {
+	id, err := utils.ParseUintID(c.Params("id"))
+	if err != nil {
+		return err
+	}
+
+	eventID, err := utils.ParseUintID(c.Params("eventId"))
+	if err != nil {
+		return err
+	}
+
+	propertyID, err := utils.ParseUintID(c.Params("propertyId"))
+	if err != nil {
+		return err
+	}
+
+	var req dtos.TrackingPlanEventPropertyRequest
+	if err := c.BodyParser(&req); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid JSON payload")
+	}
+
+	plan, err := h.services(c).trackingPlanService.SetTrackingPlanEventProperty(id, eventID, propertyID, &req, utils.IfMatchVersions(c))
+	if err != nil {
+		return err
+	}
+
+	c.Set(fiber.HeaderETag, utils.ETag(plan.Version))
+	return c.JSON(plan)
+}
+
+// RemoveTrackingPlanEventProperty godoc
+// @Summary      Remove a property from a tracking plan event
+// @Description  Take a property off an event of a tracking plan. The property stays in the catalog.
+// @Tags         tracking-plans
+// @Param        id          path    int     true   "Tracking Plan ID"
+// @Param        eventId     path    int     true   "Event ID"
+// @Param        propertyId  path    int     true   "Property ID"
+// @Param        If-Match    header  string  false  "ETag the change is based on"
+// @Success      204  {string}  string  "No Content"
+// @Header       204  {string}  ETag    "Current version of the resource"
+// @Failure      400  {object}  fiber.Map
+// @Failure      404  {object}  fiber.Map
+// @Failure      412  {object}  fiber.Map
+// @Router       /api/v1/tracking-plans/{id}/events/{eventId}/properties/{propertyId} [delete]
+func (h *Handlers) RemoveTrackingPlanEventProperty(c *fiber.Ctx) error {
+	id, err := utils.ParseUintID(c.Params("id"))
+	if err != nil {
+		return err
+	}
+
+	eventID, err := utils.ParseUintID(c.Params("eventId"))
+	if err != nil {
+		return err
+	}
+
+	propertyID, err := utils.ParseUintID(c.Params("propertyId"))
+	if err != nil {
+		return err
+	}
+
+	plan, err := h.services(c).trackingPlanService.RemoveTrackingPlanEventProperty(id, eventID, propertyID, utils.IfMatchVersions(c))
+	if err != nil {
+		return err
+	}
+
+	c.Set(fiber.HeaderETag, utils.ETag(plan.Version))
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// GetTrackingPlanVersions godoc
+// @Summary      List tracking plan versions
+// @Description  Retrieve the version history of a tracking plan, newest first
+// @Tags         tracking-plans
+// @Produce      json
+// @Param        id   path      int  true  "Tracking Plan ID"
+// @Success      200  {array}   dtos.TrackingPlanVersionSummary
+// @Failure      400  {object}  fiber.Map
+// @Failure      404  {object}  fiber.Map
+// @Router       /api/v1/tracking-plans/{id}/versions [get]
+func (h *Handlers) GetTrackingPlanVersions(c *fiber.Ctx) error {
+	id, err := utils.ParseUintID(c.Params("id"))
+	if err != nil {
+		return err
+	}
+
+	versions, err := h.services(c).trackingPlanService.GetTrackingPlanVersions(id)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(versions)
+}
+
+// GetTrackingPlanVersion godoc
+// @Summary      Get a tracking plan version
+// @Description  Retrieve the snapshot of a tracking plan at a given version
+// @Tags         tracking-plans
+// @Produce      json
+// @Param        id   path      int  true  "Tracking Plan ID"
+// @Param        n    path      int  true  "Version number"
+// @Success      200  {object}  models.TrackingPlanVersion
+// @Failure      400  {object}  fiber.Map
+// @Failure      404  {object}  fiber.Map
+// @Router       /api/v1/tracking-plans/{id}/versions/{n} [get]
+func (h *Handlers) GetTrackingPlanVersion(c *fiber.Ctx) error {
+	id, err := utils.ParseUintID(c.Params("id"))
+	if err != nil {
+		return err
+	}
+
+	version, err := utils.ParseVersion(c.Params("n"))
+	if err != nil {
+		return err
+	}
+
+	planVersion, err := h.services(c).trackingPlanService.GetTrackingPlanVersion(id, version)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(planVersion)
+}
+
+// CompareTrackingPlans godoc
+// @Summary      Diff two tracking plans or plan versions
+// @Description  Compare two tracking plans, or two versions of one plan, and list added and removed events and properties, type changes, required flips and additionalProperties changes
+// @Tags         tracking-plans
+// @Produce      json
+// @Param        base          query     int  true   "Base tracking plan ID"
+// @Param        head          query     int  true   "Head tracking plan ID"
+// @Param        base_version  query     int  false  "Base version (defaults to the current plan)"
+// @Param        head_version  query     int  false  "Head version (defaults to the current plan)"
+// @Success      200           {object}  dtos.TrackingPlanDiffResponse
+// @Failure      400           {object}  fiber.Map
+// @Failure      404           {object}  fiber.Map
+// @Router       /api/v1/tracking-plans/diff [get]
+func (h *Handlers) CompareTrackingPlans(c *fiber.Ctx) error {
+	baseID, err := utils.ParseUintID(c.Query("base"))
+	if err != nil {
+		return err
+	}
+
+	headID, err := utils.ParseUintID(c.Query("head"))
+	if err != nil {
+		return err
+	}
+
+	baseVersion, err := utils.ParseOptionalVersion(c.Query("base_version"))
+	if err != nil {
+		return err
+	}
+
+	headVersion, err := utils.ParseOptionalVersion(c.Query("head_version"))
+	if err != nil {
+		return err
+	}
+
+	diff, err := h.services(c).trackingPlanService.CompareTrackingPlans(baseID, baseVersion, headID, headVersion)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(diff)
+}
+
+// ValidateTrackingPlanPayload godoc
+// @Summary      Validate a payload against a tracking plan
+// @Description  Check a Segment-spec event payload against the events and properties of a tracking plan
+// @Tags         tracking-plans
+// @Accept       json
+// @Produce      json
+// @Param        id       path      int                          true  "Tracking Plan ID"
+// @Param        payload  body      dtos.ValidatePayloadRequest  true  "Event payload to validate"
+// @Success      200      {object}  dtos.ValidatePayloadResponse
+// @Failure      400      {object}  fiber.Map
+// @Failure      404      {object}  fiber.Map
+// @Router       /api/v1/tracking-plans/{id}/validate [post]
+func (h *Handlers) ValidateTrackingPlanPayload(c *fiber.Ctx) error {
+	id, err := utils.ParseUintID(c.Params("id"))
+	if err != nil {
+		return err
+	}
+
+	var req dtos.ValidatePayloadRequest
+	if err := c.BodyParser(&req); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid JSON payload")
+	}
+
+	result, err := h.services(c).trackingPlanService.ValidatePayload(id, &req)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(result)
+}
+
+// GetTrackingPlanSchema godoc
+// @Summary      Export a tracking plan as JSON Schema
+// @Description  Render every event of a tracking plan as a JSON Schema document
+// @Tags         tracking-plans
+// @Produce      json
+// @Param        id     path      int     true   "Tracking Plan ID"
+// @Param        draft  query     string  false  "JSON Schema draft (draft-07 or 2020-12)"
+// @Success      200    {object}  dtos.TrackingPlanSchemaResponse
+// @Failure      400    {object}  fiber.Map
+// @Failure      404    {object}  fiber.Map
+// @Router       /api/v1/tracking-plans/{id}/schema [get]
+func (h *Handlers) GetTrackingPlanSchema(c *fiber.Ctx) error {
+	id, err := utils.ParseUintID(c.Params("id"))
+	if err != nil {
+		return err
+	}
+
+	schema, err := h.services(c).trackingPlanService.ExportJSONSchema(id, c.Query("draft"))
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(schema)
+}
+
+// ImportTrackingPlanSchema godoc
+// @Summary      Import a tracking plan from JSON Schema
+// @Description  Create a tracking plan, or replace the events of an existing plan with the same name, from one JSON Schema document per event
+// @Tags         tracking-plans
+// @Accept       json
+// @Produce      json
+// @Param        bundle  body      dtos.ImportTrackingPlanSchemaRequest  true  "JSON Schema bundle"
+// @Success      200     {object}  models.TrackingPlan
+// @Success      201     {object}  models.TrackingPlan
+// @Failure      400     {object}  fiber.Map
+// @Failure      409     {object}  fiber.Map
+// @Router       /api/v1/tracking-plans/import [post]
+func (h *Handlers) ImportTrackingPlanSchema(c *fiber.Ctx) error {
+	var req dtos.ImportTrackingPlanSchemaRequest
+	if err := c.BodyParser(&req); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid JSON payload")
+	}
+
+	plan, created, err := h.services(c).trackingPlanService.ImportJSONSchema(&req)
+	if err != nil {
+		return err
+	}
+
+	if created {
+		return c.Status(fiber.StatusCreated).JSON(plan)
+	}
+	return c.JSON(plan)
+}
+
+// GetTrackingPlanSpreadsheet godoc
+// @Summary      Export a tracking plan as a spreadsheet
+// @Description  Download a tracking plan as CSV or XLSX with one row per event/property pair and the columns Event Name, Event Type, Event Description, Property Name, Property Type, Required, Property Description, Additional Properties and Constraints (the plan-level constraints as JSON)
+// @Tags         tracking-plans
+// @Produce      text/csv
+// @Produce      application/vnd.openxmlformats-officedocument.spreadsheetml.sheet
+// @Param        id      path      int     true   "Tracking Plan ID"
+// @Param        format  query     string  false  "csv or xlsx (default xlsx)"
+// @Success      200     {file}    file
+// @Failure      400     {object}  fiber.Map
+// @Failure      404     {object}  fiber.Map
+// @Router       /api/v1/tracking-plans/{id}/spreadsheet [get]
+func (h *Handlers) GetTrackingPlanSpreadsheet(c *fiber.Ctx) error {
+	id, err := utils.ParseUintID(c.Params("id"))
+	if err != nil {
+		return err
+	}
+
+	file, err := h.services(c).trackingPlanService.ExportSpreadsheet(id, c.Query("format"))
+	if err != nil {
+		return err
+	}
+
+	c.Attachment(file.Name)
+	c.Set(fiber.HeaderContentType, file.ContentType)
+	return c.Send(file.Body)
+}
+
+// ImportTrackingPlanSpreadsheet godoc
+// @Summary      Import a tracking plan from a spreadsheet
+// @Description  Create a tracking plan, or replace the events of an existing plan with the same name, from a CSV or XLSX spreadsheet in the export layout. The spreadsheet is sent as the request body or as the "file" field of a multipart form. A spreadsheet without the Constraints column keeps the plan-level constraints the plan already has.
+// @Tags         tracking-plans
+// @Accept       text/csv
+// @Accept       application/vnd.openxmlformats-officedocument.spreadsheetml.sheet
+// @Accept       multipart/form-data
+// @Produce      json
+// @Param        name         query     string  true   "Tracking plan name"
+// @Param        description  query     string  false  "Tracking plan description"
+// @Param        file         formData  file    false  "CSV or XLSX file"
+// @Success      200          {object}  models.TrackingPlan
+// @Success      201          {object}  models.TrackingPlan
+// @Failure      400          {object}  fiber.Map
+// @Failure      409          {object}  fiber.Map
+// @Failure      415          {object}  fiber.Map
+// @Router       /api/v1/tracking-plans/import/spreadsheet [post]
+func (h *Handlers) ImportTrackingPlanSpreadsheet(c *fiber.Ctx) error {
+	body, contentType, err := utils.UploadBody(c)
+	if err != nil {
+		return err
+	}
+
+	plan, created, err := h.services(c).trackingPlanService.ImportSpreadsheet(c.FormValue("name"), c.FormValue("description"), body, contentType)
+	if err != nil {
+		return err
+	}
+
+	if created {
+		return c.Status(fiber.StatusCreated).JSON(plan)
+	}
+	return c.JSON(plan)
+}
+
+// Search godoc
+// @Summary      Search the catalog
+// @Description  Full-text search over the names and descriptions of events, properties and tracking plans, ranked by relevance
+// @Tags         search
+// @Produce      json
+// @Param        q      query     string  true   "Search text; every word is matched as a prefix"
+// @Param        types  query     string  false  "Comma-separated kinds to search: event, property, tracking_plan"
+// @Param        limit  query     int     false  "Maximum number of results (default 20, max 100)"
+// @Success      200    {object}  dtos.SearchResponse
+// @Failure      400    {object}  fiber.Map
+// @Failure      500    {object}  fiber.Map
+// @Router       /api/v1/search [get]
+func (h *Handlers) Search(c *fiber.Ctx) error {
+	var query dtos.SearchQuery
+	if err := c.QueryParser(&query); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid query parameters")
+	}
+
+	results, err := h.services(c).searchService.Search(&query)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(results)
+}
+
+// GetAuditLog godoc
+// @Summary      List audit log entries
+// @Description  Retrieve a page of the audit log of catalog changes, newest first. Each entry names the actor, the client, the action and the resource before and after the change.
+// @Tags         audit
+// @Produce      json
+// @Param        limit           query     int     false  "Page size (default 50, max 200)"
+// @Param        cursor          query     string  false  "Cursor returned as next_cursor by the previous page"
+// @Param        resource        query     string  false  "Only return changes to this kind of resource: event, property or tracking_plan"
+// @Param        resource_id     query     int     false  "Only return changes to the resource with this ID"
+// @Param        actor           query     string  false  "Only return changes made by this user ID or email"
+// @Param        action          query     string  false  "Only return this action: create, update, delete or restore"
+// @Param        created_after   query     int     false  "Only return changes made at or after this unix time"
+// @Param        created_before  query     int     false  "Only return changes made before this unix time"
+// @Param        order           query     string  false  "Sort order: desc (default) or asc"
+// @Success      200             {object}  dtos.PageResponse
+// @Failure      400             {object}  fiber.Map
+// @Failure      500             {object}  fiber.Map
+// @Router       /api/v1/audit [get]
+func (h *Handlers) GetAuditLog(c *fiber.Ctx) error {
+	var query dtos.AuditQuery
+	if err := c.QueryParser(&query); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid query parameters")
+	}
+
+	page, err := h.services(c).auditService.ListAuditEntries(&query)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(page)
+}
+
+// CreateAPIClient godoc
+// @Summary      Register an API client
+// @Description  Create an API client with a generated secret. Scopes list the roles the client's users may act with (viewer, editor, admin), plus clients to manage API clients. The secret is only returned in this response.
+// @Tags         clients
+// @Accept       json
+// @Produce      json
+// @Param        client  body      dtos.CreateAPIClientRequest  true  "Client to create"
+// @Success      201     {object}  dtos.APIClientCredentials
+// @Failure      400     {object}  fiber.Map
+// @Failure      409     {object}  fiber.Map
+// @Router       /api/v1/clients [post]
+func (h *Handlers) CreateAPIClient(c *fiber.Ctx) error {
+	var req dtos.CreateAPIClientRequest
+	if err := c.BodyParser(&req); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid JSON payload")
+	}
+
+	client, err := h.apiClientService.CreateClient(&req)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(client)
+}
+
+// GetAPIClients godoc
+// @Summary      List API clients
+// @Description  Retrieve every registered API client, including revoked and expired ones. Secrets are never returned.
+// @Tags         clients
+// @Produce      json
+// @Success      200  {array}   models.APIClient
+// @Failure      500  {object}  fiber.Map
+// @Router       /api/v1/clients [get]
+func (h *Handlers) GetAPIClients(c *fiber.Ctx) error {
+	clients, err := h.apiClientService.GetAllClients()
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(clients)
+}
+
+// RotateAPIClientSecret godoc
+// @Summary      Rotate an API client secret
+// @Description  Replace the secret of an API client. The old secret stops working immediately and the new one is only returned in this response.
+// @Tags         clients
+// @Produce      json
+// @Param        id   path      int  true  "Client ID"
+// @Success      200  {object}  dtos.APIClientCredentials
+// @Failure      400  {object}  fiber.Map
+// @Failure      404  {object}  fiber.Map
+// @Failure      409  {object}  fiber.Map
+// @Router       /api/v1/clients/{id}/rotate [post]
+func (h *Handlers) RotateAPIClientSecret(c *fiber.Ctx) error {
+	id, err := utils.ParseUintID(c.Params("id"))
+	if err != nil {
+		return err
+	}
+
+	client, err := h.apiClientService.RotateClientSecret(id)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(client)
+}
+
+// RevokeAPIClient godoc
+// @Summary      Revoke an API client
+// @Description  Permanently stop an API client from authenticating. Revoking a revoked client is a no-op.
+// @Tags         clients
+// @Produce      json
+// @Param        id   path      int  true  "Client ID"
+// @Success      200  {object}  models.APIClient
+// @Failure      400  {object}  fiber.Map
+// @Failure      404  {object}  fiber.Map
+// @Router       /api/v1/clients/{id}/revoke [post]
+func (h *Handlers) RevokeAPIClient(c *fiber.Ctx) error {
+	id, err := utils.ParseUintID(c.Params("id"))
+	if err != nil {
+		return err
+	}
+
+	client, err := h.apiClientService.RevokeClient(id)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(client)
+}
+
+// HealthCheck godoc
+// @Summary      Health check
+// @Description  Returns the health status of the service
+// @Tags         health
+// @Produce      json
+// @Success      200  {object}  fiber.Map
+// @Router       /health [get]
+func (h *Handlers) HealthCheck(c *fiber.Ctx) error {
+	return c.JSON(fiber.Map{
+		"status":  "healthy",
+		"service": "datacatalog",
+		"version": "1.0.0",
+	})
+}
+
+// referenceConflictResponse renders a ReferenceConflictError as a 409 with the
+// referencing tracking plans, and passes any other error through.
+func referenceConflictResponse(c *fiber.Ctx, err error) error {
+	var conflict *services.ReferenceConflictError
+	if errors.As(err, &conflict) {
+		return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+			"error":          conflict.Message,
+			"tracking_plans": conflict.TrackingPlans,
+		})
+	}
+	return err
 }
@@ -0,0 +1,189 @@
+package jsonschema
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/shivamrajput1826/api-catalog/internal/models"
+)
+
+// FromTrackingPlanEvent renders a tracking plan event as a JSON Schema
+// document describing the whole Segment payload. The event's properties sit
+// under "properties" (or "traits" for identify calls).
+func FromTrackingPlanEvent(planEvent *models.TrackingPlanEvent, draft string) *Schema {
+	event := planEvent.Event
+	additional := planEvent.AdditionalProperties
+
+	fields := &Schema{
+		Type:                 TypeList{"object"},
+		Properties:           map[string]*Schema{},
+		Required:             []string{},
+		AdditionalProperties: &additional,
+	}
+	for _, planProp := range planEvent.Properties {
+		fields.Properties[planProp.Property.Name] = fromProperty(&planProp.Property, planProp.EffectiveConstraints())
+		if planProp.Required {
+			fields.Required = append(fields.Required, planProp.Property.Name)
+		}
+	}
+
+	fieldName := models.PayloadFieldFor(event.Type)
+	root := &Schema{
+		Schema:      DraftURIs[draft],
+		Title:       event.Name,
+		Description: event.Description,
+		Type:        TypeList{"object"},
+		Properties: map[string]*Schema{
+			"type":    {Const: event.Type},
+			fieldName: fields,
+		},
+		Required: []string{"type"},
+	}
+	if nameField := models.EventNameFieldFor(event.Type); nameField != "" {
+		root.Properties[nameField] = &Schema{Const: event.Name}
+		root.Required = append(root.Required, nameField)
+	}
+	if len(fields.Required) > 0 {
+		root.Required = append(root.Required, fieldName)
+	}
+
+	return root
+}
+
+// fromProperty renders a property with its constraints and children. For
+// arrays with typed items the constraints and children describe the elements,
+// so they go on the items schema.
+func fromProperty(property *models.Property, constraints models.PropertyConstraints) *Schema {
+	return fromField(property.Type, property.Items, property.Description, constraints, property.Properties)
+}
+
+func fromField(propertyType, items models.PropertyType, description string, constraints models.PropertyConstraints, children []models.PropertyField) *Schema {
+	schema := fromPropertyType(propertyType)
+	schema.Description = description
+	target := schema
+	if items != "" {
+		schema.Items = fromPropertyType(items)
+		target = schema.Items
+	}
+	applyConstraints(target, constraints)
+
+	if len(children) > 0 {
+		target.Properties = make(map[string]*Schema, len(children))
+		for _, child := range children {
+			target.Properties[child.Name] = fromField(child.Type, child.Items, child.Description, child.PropertyConstraints, child.Properties)
+			if child.Required {
+				target.Required = append(target.Required, child.Name)
+			}
+		}
+	}
+	return schema
+}
+
+func applyConstraints(schema *Schema, constraints models.PropertyConstraints) {
+	schema.Enum = constraints.Enum
+	schema.Pattern = constraints.Pattern
+	schema.Minimum = constraints.Minimum
+	schema.Maximum = constraints.Maximum
+	schema.MinLength = constraints.MinLength
+	schema.MaxLength = constraints.MaxLength
+	if constraints.Format != "" && schema.Format == "" {
+		schema.Format = constraints.Format
+	}
+}
+
+// fromPropertyType maps catalog types onto JSON Schema types. date-time is
+// not a JSON type, so it becomes a string with the date-time format unless
+// the union also accepts arbitrary strings.
+func fromPropertyType(propertyType models.PropertyType) *Schema {
+	schema := &Schema{}
+	for _, name := range propertyType.Types() {
+		if name == "date-time" {
+			if propertyType.Has("string") {
+				continue
+			}
+			schema.Format = "date-time"
+			name = "string"
+		}
+		schema.Type = append(schema.Type, name)
+	}
+	return schema
+}
+
+// ToPropertyType reads the catalog type back from a property schema,
+// turning strings with the date-time format into date-time.
+func ToPropertyType(schema *Schema) models.PropertyType {
+	names := make([]string, 0, len(schema.Type))
+	for _, name := range schema.Type {
+		if name == "string" && schema.Format == "date-time" {
+			name = "date-time"
+		}
+		names = append(names, name)
+	}
+	return models.NewPropertyType(names...)
+}
+
+// ToConstraints reads the value constraints of a property schema. A
+// date-time format that ToPropertyType already turned into the date-time type
+// is not repeated as a format constraint.
+func ToConstraints(schema *Schema) models.PropertyConstraints {
+	constraints := models.PropertyConstraints{
+		Enum:      schema.Enum,
+		Pattern:   schema.Pattern,
+		Minimum:   schema.Minimum,
+		Maximum:   schema.Maximum,
+		MinLength: schema.MinLength,
+		MaxLength: schema.MaxLength,
+		Format:    schema.Format,
+	}
+	if schema.Format == "date-time" && ToPropertyType(schema).Has("date-time") {
+		constraints.Format = ""
+	}
+	return constraints
+}
+
+// ToPropertyField reads a property schema back into a field definition,
+// including its constraints and, recursively, its children. path names the
+// property in error messages.
+func ToPropertyField(path string, schema *Schema, required bool) (*models.PropertyField, error) {
+	if schema == nil {
+		return nil, fmt.Errorf("property '%s' has an empty schema", path)
+	}
+	if len(schema.Type) == 0 {
+		return nil, fmt.Errorf("property '%s' must declare a type", path)
+	}
+	field := &models.PropertyField{
+		Name:        path,
+		Type:        ToPropertyType(schema),
+		Required:    required,
+		Description: schema.Description,
+	}
+
+	target := schema
+	childPath := path
+	if schema.Items != nil {
+		field.Items = ToPropertyType(schema.Items)
+		target = schema.Items
+		childPath = path + "[]"
+	}
+	field.PropertyConstraints = ToConstraints(target)
+
+	childRequired := make(map[string]bool, len(target.Required))
+	for _, name := range target.Required {
+		childRequired[name] = true
+	}
+	names := make([]string, 0, len(target.Properties))
+	for name := range target.Properties {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		child, err := ToPropertyField(childPath+"."+name, target.Properties[name], childRequired[name])
+		if err != nil {
+			return nil, err
+		}
+		child.Name = name
+		field.Properties = append(field.Properties, *child)
+	}
+	return field, nil
+}
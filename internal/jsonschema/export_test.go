@@ -0,0 +1,59 @@
+package jsonschema
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestToPropertyFieldRejectsNullChildren(t *testing.T) {
+	documents := map[string]string{
+		"address.street":      `{"type": "object", "properties": {"street": null}}`,
+		"address[].street":    `{"type": "array", "items": {"type": "object", "properties": {"street": null}}}`,
+		"address.geo.lat":     `{"type": "object", "properties": {"geo": {"type": "object", "properties": {"lat": null}}}}`,
+		"address.street.name": `{"type": "object", "properties": {"street": {"type": "object", "properties": {"name": null}}}}`,
+	}
+	for path, document := range documents {
+		var schema Schema
+		if err := json.Unmarshal([]byte(document), &schema); err != nil {
+			t.Fatalf("%s: %v", document, err)
+		}
+		_, err := ToPropertyField("address", &schema, false)
+		if err == nil {
+			t.Errorf("%s: expected an error", document)
+			continue
+		}
+		if want := "property '" + path + "' has an empty schema"; err.Error() != want {
+			t.Errorf("%s: got %q, want %q", document, err, want)
+		}
+	}
+}
+
+func TestToPropertyFieldReadsChildren(t *testing.T) {
+	document := `{
+		"type": "array",
+		"items": {
+			"type": "object",
+			"required": ["sku"],
+			"properties": {"sku": {"type": "string", "minLength": 3}, "quantity": {"type": "integer"}}
+		}
+	}`
+	var schema Schema
+	if err := json.Unmarshal([]byte(document), &schema); err != nil {
+		t.Fatal(err)
+	}
+
+	field, err := ToPropertyField("items", &schema, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(field.Properties) != 2 {
+		t.Fatalf("expected 2 children, got %+v", field.Properties)
+	}
+	quantity, sku := field.Properties[0], field.Properties[1]
+	if quantity.Name != "quantity" || quantity.Required {
+		t.Errorf("unexpected child %+v", quantity)
+	}
+	if sku.Name != "sku" || !sku.Required || sku.MinLength == nil || *sku.MinLength != 3 {
+		t.Errorf("unexpected child %+v", sku)
+	}
+}
@@ -0,0 +1,67 @@
+package jsonschema
+
+import (
+	"encoding/json"
+)
+
+const (
+	Draft07     = "draft-07"
+	Draft202012 = "2020-12"
+)
+
+var DraftURIs = map[string]string{
+	Draft07:     "http://json-schema.org/draft-07/schema#",
+	Draft202012: "https://json-schema.org/draft/2020-12/schema",
+}
+
+// Schema is the subset of JSON Schema the catalog reads and writes. It is
+// shared by draft-07 and 2020-12 documents; only the $schema URI differs.
+type Schema struct {
+	Schema               string             `json:"$schema,omitempty"`
+	ID                   string             `json:"$id,omitempty"`
+	Title                string             `json:"title,omitempty"`
+	Description          string             `json:"description,omitempty"`
+	Type                 TypeList           `json:"type,omitempty"`
+	Format               string             `json:"format,omitempty"`
+	Const                interface{}        `json:"const,omitempty"`
+	Enum                 []interface{}      `json:"enum,omitempty"`
+	Pattern              string             `json:"pattern,omitempty"`
+	Minimum              *float64           `json:"minimum,omitempty"`
+	Maximum              *float64           `json:"maximum,omitempty"`
+	MinLength            *int               `json:"minLength,omitempty"`
+	MaxLength            *int               `json:"maxLength,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	AdditionalProperties *bool              `json:"additionalProperties,omitempty"`
+}
+
+// TypeList holds the "type" keyword, which may be a single type name or an
+// array of names. A single entry is written back as a plain string.
+type TypeList []string
+
+func (t TypeList) MarshalJSON() ([]byte, error) {
+	if len(t) == 1 {
+		return json.Marshal(t[0])
+	}
+	return json.Marshal([]string(t))
+}
+
+func (t *TypeList) UnmarshalJSON(data []byte) error {
+	var single string
+	if err := json.Unmarshal(data, &single); err == nil {
+		*t = TypeList{single}
+		return nil
+	}
+	var many []string
+	if err := json.Unmarshal(data, &many); err != nil {
+		return err
+	}
+	*t = many
+	return nil
+}
+
+func DraftURI(draft string) (string, bool) {
+	uri, ok := DraftURIs[draft]
+	return uri, ok
+}
@@ -0,0 +1,188 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/shivamrajput1826/api-catalog/internal/models"
+	"gorm.io/gorm"
+)
+
+const (
+	startKey       = "metrics:start"
+	catalogTimeout = 5 * time.Second
+)
+
+var (
+	queryDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "db_query_duration_seconds",
+		Help:      "GORM statement latency by operation and table.",
+		Buckets:   []float64{.0005, .001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 2.5},
+	}, []string{"operation", "table"})
+
+	queryErrors = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "db_query_errors_total",
+		Help:      "GORM statements that failed, by operation and table. Lookups that find no record are not counted.",
+	}, []string{"operation", "table"})
+)
+
+// InstrumentDB times every statement run through db and registers the
+// connection pool statistics and the catalog size gauges. The gauges are
+// only split by workspace when perWorkspace is set, since workspace slugs are
+// client ids.
+func InstrumentDB(db *gorm.DB, perWorkspace bool) error {
+	if err := db.Use(queryTimer{}); err != nil {
+		return err
+	}
+	sqlDB, err := db.DB()
+	if err != nil {
+		return err
+	}
+	return registerAll(
+		collectors.NewDBStatsCollector(sqlDB, "api_catalog"),
+		newCatalogCollector(db, perWorkspace),
+	)
+}
+
+func registerAll(cs ...prometheus.Collector) error {
+	for _, c := range cs {
+		if err := Registry.Register(c); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// queryTimer is a GORM plugin that observes the duration of every statement.
+type queryTimer struct{}
+
+func (queryTimer) Name() string {
+	return "metrics"
+}
+
+func (queryTimer) Initialize(db *gorm.DB) error {
+	callbacks := db.Callback()
+	registrations := []struct {
+		operation string
+		before    error
+		after     error
+	}{
+		{"create",
+			callbacks.Create().Before("gorm:create").Register("metrics:before_create", startTimer),
+			callbacks.Create().After("gorm:create").Register("metrics:after_create", observe("create"))},
+		{"query",
+			callbacks.Query().Before("gorm:query").Register("metrics:before_query", startTimer),
+			callbacks.Query().After("gorm:query").Register("metrics:after_query", observe("query"))},
+		{"update",
+			callbacks.Update().Before("gorm:update").Register("metrics:before_update", startTimer),
+			callbacks.Update().After("gorm:update").Register("metrics:after_update", observe("update"))},
+		{"delete",
+			callbacks.Delete().Before("gorm:delete").Register("metrics:before_delete", startTimer),
+			callbacks.Delete().After("gorm:delete").Register("metrics:after_delete", observe("delete"))},
+		{"row",
+			callbacks.Row().Before("gorm:row").Register("metrics:before_row", startTimer),
+			callbacks.Row().After("gorm:row").Register("metrics:after_row", observe("row"))},
+		{"raw",
+			callbacks.Raw().Before("gorm:raw").Register("metrics:before_raw", startTimer),
+			callbacks.Raw().After("gorm:raw").Register("metrics:after_raw", observe("raw"))},
+	}
+	for _, registration := range registrations {
+		if err := errors.Join(registration.before, registration.after); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func startTimer(db *gorm.DB) {
+	db.InstanceSet(startKey, time.Now())
+}
+
+func observe(operation string) func(*gorm.DB) {
+	return func(db *gorm.DB) {
+		value, ok := db.InstanceGet(startKey)
+		if !ok {
+			return
+		}
+		table := db.Statement.Table
+		if table == "" {
+			table = "none"
+		}
+		queryDuration.WithLabelValues(operation, table).Observe(time.Since(value.(time.Time)).Seconds())
+		if db.Error != nil && !errors.Is(db.Error, gorm.ErrRecordNotFound) {
+			queryErrors.WithLabelValues(operation, table).Inc()
+		}
+	}
+}
+
+// catalogCollector counts the live events, properties and tracking plans,
+// in total or per workspace, when Prometheus scrapes.
+type catalogCollector struct {
+	db           *gorm.DB
+	perWorkspace bool
+	sizes        *prometheus.Desc
+}
+
+func newCatalogCollector(db *gorm.DB, perWorkspace bool) *catalogCollector {
+	help, labels := "Live (not soft-deleted) catalog items by kind.", []string{"kind"}
+	if perWorkspace {
+		help, labels = "Live (not soft-deleted) catalog items by kind and workspace.", []string{"kind", "workspace"}
+	}
+	return &catalogCollector{
+		db:           db,
+		perWorkspace: perWorkspace,
+		sizes:        prometheus.NewDesc(prometheus.BuildFQName(namespace, "catalog", "items"), help, labels, nil),
+	}
+}
+
+func (c *catalogCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.sizes
+}
+
+func (c *catalogCollector) Collect(ch chan<- prometheus.Metric) {
+	ctx, cancel := context.WithTimeout(context.Background(), catalogTimeout)
+	defer cancel()
+
+	kinds := []struct {
+		kind  string
+		model interface{}
+		table string
+	}{
+		{models.SearchKindEvent, &models.Event{}, "events"},
+		{models.SearchKindProperty, &models.Property{}, "properties"},
+		{models.SearchKindTrackingPlan, &models.TrackingPlan{}, "tracking_plans"},
+	}
+	for _, kind := range kinds {
+		if !c.perWorkspace {
+			var count int64
+			if err := c.db.WithContext(ctx).Model(kind.model).Count(&count).Error; err != nil {
+				ch <- prometheus.NewInvalidMetric(c.sizes, err)
+				continue
+			}
+			ch <- prometheus.MustNewConstMetric(c.sizes, prometheus.GaugeValue, float64(count), kind.kind)
+			continue
+		}
+
+		var counts []struct {
+			Workspace string
+			Count     int64
+		}
+		err := c.db.WithContext(ctx).Model(kind.model).
+			Select("workspaces.slug AS workspace, count(*) AS count").
+			Joins("JOIN workspaces ON workspaces.id = " + kind.table + ".workspace_id").
+			Group("workspaces.slug").
+			Scan(&counts).Error
+		if err != nil {
+			ch <- prometheus.NewInvalidMetric(c.sizes, err)
+			continue
+		}
+		for _, count := range counts {
+			ch <- prometheus.MustNewConstMetric(c.sizes, prometheus.GaugeValue, float64(count.Count), kind.kind, count.Workspace)
+		}
+	}
+}
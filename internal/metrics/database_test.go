@@ -0,0 +1,70 @@
+package metrics
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/glebarez/sqlite"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/shivamrajput1826/api-catalog/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+func TestCatalogCollectorLabelsWorkspacesOnlyWhenAsked(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open("file:catalog?mode=memory&cache=shared"), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := db.AutoMigrate(&models.Workspace{}, &models.Event{}, &models.Property{}, &models.TrackingPlan{}); err != nil {
+		t.Fatal(err)
+	}
+	for _, slug := range []string{"acme", "globex"} {
+		workspace := models.Workspace{Slug: slug}
+		if err := db.Create(&workspace).Error; err != nil {
+			t.Fatal(err)
+		}
+		if err := db.Create(&models.Event{WorkspaceID: workspace.ID, Name: "Signed Up", Type: "track"}).Error; err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	got := gatherCatalog(t, newCatalogCollector(db, false))
+	want := []string{"kind=event 2", "kind=property 0", "kind=tracking_plan 0"}
+	if !slices.Equal(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+
+	got = gatherCatalog(t, newCatalogCollector(db, true))
+	want = []string{"kind=event,workspace=acme 1", "kind=event,workspace=globex 1"}
+	if !slices.Equal(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
+
+// gatherCatalog scrapes c and renders each gauge as its labels and value.
+func gatherCatalog(t *testing.T, c prometheus.Collector) []string {
+	t.Helper()
+	registry := prometheus.NewPedanticRegistry()
+	registry.MustRegister(c)
+	families, err := registry.Gather()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var gauges []string
+	for _, family := range families {
+		for _, metric := range family.GetMetric() {
+			labels := make([]string, 0, len(metric.GetLabel()))
+			for _, label := range metric.GetLabel() {
+				labels = append(labels, label.GetName()+"="+label.GetValue())
+			}
+			gauges = append(gauges, fmt.Sprintf("%s %g", strings.Join(labels, ","), metric.GetGauge().GetValue()))
+		}
+	}
+	return gauges
+}
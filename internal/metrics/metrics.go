@@ -0,0 +1,89 @@
+// Package metrics collects the Prometheus metrics served at /metrics: HTTP
+// requests per route, GORM query durations, connection pool statistics and
+// the size of the catalog.
+package metrics
+
+import (
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/adaptor"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "api_catalog"
+
+// Registry holds every metric of the service, along with the Go runtime and
+// process collectors.
+var Registry = prometheus.NewRegistry()
+
+var (
+	httpRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "http_requests_total",
+		Help:      "HTTP requests by method, route and status code.",
+	}, []string{"method", "route", "status"})
+
+	httpDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "http_request_duration_seconds",
+		Help:      "HTTP request latency by method and route.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method", "route"})
+
+	httpErrors = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "http_request_errors_total",
+		Help:      "HTTP requests answered with a 4xx or 5xx status, by route and status code.",
+	}, []string{"route", "status"})
+)
+
+func init() {
+	Registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		httpRequests,
+		httpDuration,
+		httpErrors,
+		queryDuration,
+		queryErrors,
+	)
+}
+
+// Handler serves the registry in the Prometheus text format. A collector that
+// fails, e.g. the catalog gauges while the database is down, is left out
+// rather than failing the whole scrape.
+func Handler() fiber.Handler {
+	return adaptor.HTTPHandler(promhttp.HandlerFor(Registry, promhttp.HandlerOpts{ErrorHandling: promhttp.ContinueOnError}))
+}
+
+// Middleware records the count, latency and status of every request. Routes
+// are labelled by their pattern, e.g. /api/v1/events/:id, to keep the number
+// of series bounded. Errors returned by later handlers are counted with the
+// status they will be rendered with and passed on unchanged.
+func Middleware(c *fiber.Ctx) error {
+	start := time.Now()
+	err := c.Next()
+
+	status := c.Response().StatusCode()
+	if err != nil {
+		status = fiber.StatusInternalServerError
+		var fiberErr *fiber.Error
+		if errors.As(err, &fiberErr) {
+			status = fiberErr.Code
+		}
+	}
+
+	route := c.Route().Path
+	code := strconv.Itoa(status)
+	httpRequests.WithLabelValues(c.Method(), route, code).Inc()
+	httpDuration.WithLabelValues(c.Method(), route).Observe(time.Since(start).Seconds())
+	if status >= fiber.StatusBadRequest {
+		httpErrors.WithLabelValues(route, code).Inc()
+	}
+	return err
+}
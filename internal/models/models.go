@@ -1,34 +1,143 @@
 package models
 
 import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"slices"
+	"time"
+
+	"github.com/shivamrajput1826/api-catalog/common"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
+// Workspace owns a separate catalog of events, properties and tracking plans.
+// Each API client works in the workspace whose slug is its client id.
+type Workspace struct {
+	ID         uint   `json:"id" gorm:"primaryKey"`
+	Slug       string `json:"slug" gorm:"not null;uniqueIndex"`
+	Name       string `json:"name"`
+	CreateTime int64  `json:"create_time" gorm:"autoCreateTime"`
+}
+
 type Event struct {
-	ID          uint   `json:"id" gorm:"primaryKey"`
-	Name        string `json:"name" gorm:"not null;index:idx_event_name_type,unique"`
-	Type        string `json:"type" gorm:"not null;index:idx_event_name_type,unique"`
-	Description string `json:"description"`
-	CreateTime  int64  `json:"create_time" gorm:"autoCreateTime"`
-	UpdateTime  int64  `json:"update_time" gorm:"autoUpdateTime"`
+	ID          uint           `json:"id" gorm:"primaryKey"`
+	WorkspaceID uint           `json:"-" gorm:"not null;default:0;index:idx_event_workspace_name_type,unique,where:deleted_at IS NULL"`
+	Name        string         `json:"name" gorm:"not null;index:idx_event_workspace_name_type,unique,where:deleted_at IS NULL"`
+	Type        string         `json:"type" gorm:"not null;index:idx_event_workspace_name_type,unique,where:deleted_at IS NULL"`
+	Description string         `json:"description"`
+	Version     int            `json:"version" gorm:"not null;default:1"`
+	CreateTime  int64          `json:"create_time" gorm:"autoCreateTime"`
+	UpdateTime  int64          `json:"update_time" gorm:"autoUpdateTime"`
+	DeletedAt   gorm.DeletedAt `json:"deleted_at,omitempty" gorm:"index"`
+	DeletedBy   string         `json:"deleted_by,omitempty"`
 }
 
 type Property struct {
-	ID          uint   `json:"id" gorm:"primaryKey"`
-	Name        string `json:"name" gorm:"not null;index:idx_property_name_type,unique"`
-	Type        string `json:"type" gorm:"not null;index:idx_property_name_type,unique"`
-	Description string `json:"description"`
-	CreateTime  int64  `json:"create_time" gorm:"autoCreateTime"`
-	UpdateTime  int64  `json:"update_time" gorm:"autoUpdateTime"`
+	ID          uint         `json:"id" gorm:"primaryKey"`
+	WorkspaceID uint         `json:"-" gorm:"not null;default:0;index:idx_property_workspace_name_type,unique,where:deleted_at IS NULL"`
+	Name        string       `json:"name" gorm:"not null;index:idx_property_workspace_name_type,unique,where:deleted_at IS NULL"`
+	Type        PropertyType `json:"type" gorm:"not null;index:idx_property_workspace_name_type,unique,where:deleted_at IS NULL"`
+	Items       PropertyType `json:"items,omitempty" gorm:"not null;default:'';index:idx_property_workspace_name_type,unique,where:deleted_at IS NULL"`
+	Description string       `json:"description"`
+	PropertyConstraints
+	Properties []PropertyField `json:"properties,omitempty" gorm:"serializer:json;type:jsonb"`
+	Version    int             `json:"version" gorm:"not null;default:1"`
+	CreateTime int64           `json:"create_time" gorm:"autoCreateTime"`
+	UpdateTime int64           `json:"update_time" gorm:"autoUpdateTime"`
+	DeletedAt  gorm.DeletedAt  `json:"deleted_at,omitempty" gorm:"index"`
+	DeletedBy  string          `json:"deleted_by,omitempty"`
+}
+
+// PropertyField describes a child of an object property, or of the objects in
+// an array property. Children may nest further, so a property "products" of
+// type array with items object can declare "products[].sku".
+type PropertyField struct {
+	Name        string       `json:"name"`
+	Type        PropertyType `json:"type"`
+	Items       PropertyType `json:"items,omitempty"`
+	Required    bool         `json:"required"`
+	Description string       `json:"description,omitempty"`
+	PropertyConstraints
+	Properties []PropertyField `json:"properties,omitempty"`
+}
+
+// PropertyConstraints restricts the values a property accepts. Every field is
+// optional. For arrays with typed items the constraints apply to each element.
+type PropertyConstraints struct {
+	Enum      []interface{} `json:"enum,omitempty" gorm:"serializer:json;type:jsonb"`
+	Pattern   string        `json:"pattern,omitempty"`
+	Minimum   *float64      `json:"minimum,omitempty"`
+	Maximum   *float64      `json:"maximum,omitempty"`
+	MinLength *int          `json:"minLength,omitempty"`
+	MaxLength *int          `json:"maxLength,omitempty"`
+	Format    string        `json:"format,omitempty"`
+}
+
+func (c PropertyConstraints) IsZero() bool {
+	return len(c.Enum) == 0 && c.Pattern == "" && c.Minimum == nil && c.Maximum == nil &&
+		c.MinLength == nil && c.MaxLength == nil && c.Format == ""
+}
+
+// Merge returns c with every constraint that is set in override replacing
+// the corresponding one in c.
+func (c PropertyConstraints) Merge(override PropertyConstraints) PropertyConstraints {
+	if len(override.Enum) > 0 {
+		c.Enum = override.Enum
+	}
+	if override.Pattern != "" {
+		c.Pattern = override.Pattern
+	}
+	if override.Minimum != nil {
+		c.Minimum = override.Minimum
+	}
+	if override.Maximum != nil {
+		c.Maximum = override.Maximum
+	}
+	if override.MinLength != nil {
+		c.MinLength = override.MinLength
+	}
+	if override.MaxLength != nil {
+		c.MaxLength = override.MaxLength
+	}
+	if override.Format != "" {
+		c.Format = override.Format
+	}
+	return c
 }
 
 type TrackingPlan struct {
-	ID          uint                `json:"id" gorm:"primaryKey"`
-	Name        string              `json:"name" gorm:"not null;unique"`
-	Description string              `json:"description"`
-	Events      []TrackingPlanEvent `json:"events" gorm:"foreignKey:TrackingPlanID;constraint:OnDelete:CASCADE"`
-	CreateTime  int64               `json:"create_time" gorm:"autoCreateTime"`
-	UpdateTime  int64               `json:"update_time" gorm:"autoUpdateTime"`
+	ID          uint                  `json:"id" gorm:"primaryKey"`
+	WorkspaceID uint                  `json:"-" gorm:"not null;default:0;index:idx_tracking_plan_workspace_name,unique,where:deleted_at IS NULL"`
+	Name        string                `json:"name" gorm:"not null;index:idx_tracking_plan_workspace_name,unique,where:deleted_at IS NULL"`
+	Description string                `json:"description"`
+	Version     int                   `json:"version" gorm:"not null;default:0"`
+	Events      []TrackingPlanEvent   `json:"events" gorm:"foreignKey:TrackingPlanID;constraint:OnDelete:CASCADE"`
+	Versions    []TrackingPlanVersion `json:"-" gorm:"foreignKey:TrackingPlanID;constraint:OnDelete:CASCADE"`
+	CreateTime  int64                 `json:"create_time" gorm:"autoCreateTime"`
+	UpdateTime  int64                 `json:"update_time" gorm:"autoUpdateTime"`
+	DeletedAt   gorm.DeletedAt        `json:"deleted_at,omitempty" gorm:"index"`
+	DeletedBy   string                `json:"deleted_by,omitempty"`
+}
+
+// TrackingPlanVersion is an immutable snapshot of a tracking plan, written
+// every time the plan is saved.
+type TrackingPlanVersion struct {
+	ID             uint         `json:"id" gorm:"primaryKey"`
+	TrackingPlanID uint         `json:"tracking_plan_id" gorm:"not null;index:idx_tracking_plan_version,unique"`
+	Version        int          `json:"version" gorm:"not null;index:idx_tracking_plan_version,unique"`
+	Snapshot       TrackingPlan `json:"snapshot" gorm:"serializer:json;type:jsonb"`
+	CreateTime     int64        `json:"create_time" gorm:"autoCreateTime"`
+}
+
+// WorkspaceCondition keeps versions to the plans of a workspace.
+func (TrackingPlanVersion) WorkspaceCondition(workspaceID uint) clause.Expression {
+	return clause.Expr{
+		SQL:  "? IN (SELECT id FROM tracking_plans WHERE workspace_id = ?)",
+		Vars: []interface{}{clause.Column{Table: clause.CurrentTable, Name: "tracking_plan_id"}, workspaceID},
+	}
 }
 
 type TrackingPlanEvent struct {
@@ -46,43 +155,269 @@ type TrackingPlanEventProperty struct {
 	PropertyID          uint     `json:"property_id"`
 	Property            Property `json:"property" gorm:"foreignKey:PropertyID"`
 	Required            bool     `json:"required"`
+	PropertyConstraints
+}
+
+// WorkspaceCondition keeps plan events to the plans of a workspace.
+func (TrackingPlanEvent) WorkspaceCondition(workspaceID uint) clause.Expression {
+	return clause.Expr{
+		SQL:  "? IN (SELECT id FROM tracking_plans WHERE workspace_id = ?)",
+		Vars: []interface{}{clause.Column{Table: clause.CurrentTable, Name: "tracking_plan_id"}, workspaceID},
+	}
+}
+
+// WorkspaceCondition keeps plan event properties to the plans of a workspace.
+func (TrackingPlanEventProperty) WorkspaceCondition(workspaceID uint) clause.Expression {
+	return clause.Expr{
+		SQL: "? IN (SELECT tracking_plan_events.id FROM tracking_plan_events " +
+			"JOIN tracking_plans ON tracking_plans.id = tracking_plan_events.tracking_plan_id " +
+			"WHERE tracking_plans.workspace_id = ?)",
+		Vars: []interface{}{clause.Column{Table: clause.CurrentTable, Name: "tracking_plan_event_id"}, workspaceID},
+	}
+}
+
+// EffectiveConstraints combines the catalog constraints of the property with
+// the plan-level constraints of this usage, which take precedence.
+func (p *TrackingPlanEventProperty) EffectiveConstraints() PropertyConstraints {
+	return p.Property.PropertyConstraints.Merge(p.PropertyConstraints)
+}
+
+// PayloadFieldFor returns the payload key that carries the properties of a
+// Segment event: identify calls send traits, everything else sends properties.
+func PayloadFieldFor(eventType string) string {
+	if eventType == "identify" {
+		return "traits"
+	}
+	return "properties"
+}
+
+// EventNameFieldFor returns the payload key that names the event, or an empty
+// string for call types that are not named.
+func EventNameFieldFor(eventType string) string {
+	switch eventType {
+	case "track":
+		return "event"
+	case "page", "screen":
+		return "name"
+	}
+	return ""
 }
 
 func GetAllModels() []interface{} {
 	return []interface{}{
+		&Workspace{},
 		&Event{},
 		&Property{},
 		&TrackingPlan{},
 		&TrackingPlanEvent{},
 		&TrackingPlanEventProperty{},
+		&TrackingPlanVersion{},
+		&AuditEntry{},
+		&APIClient{},
 	}
 }
 
+// DeletionColumns are the columns written when a row is soft deleted. Soft
+// deleted rows are hidden from queries unless they are explicitly unscoped.
+func DeletionColumns(deletedBy string) map[string]interface{} {
+	return map[string]interface{}{
+		"deleted_at": time.Now(),
+		"deleted_by": deletedBy,
+	}
+}
+
+// RestoreColumns clear the soft-delete columns of a row.
+func RestoreColumns() map[string]interface{} {
+	return map[string]interface{}{
+		"deleted_at": nil,
+		"deleted_by": "",
+	}
+}
+
+// ListOptions controls filtering, ordering and keyset pagination of list
+// queries. AfterValue and AfterID come from the cursor of the previous page.
+type ListOptions struct {
+	Limit          int
+	Type           string
+	NamePrefix     string
+	CreatedAfter   int64
+	CreatedBefore  int64
+	UpdatedAfter   int64
+	UpdatedBefore  int64
+	SortBy         string
+	Descending     bool
+	AfterValue     interface{}
+	AfterID        uint
+	WithEvents     bool
+	IncludeDeleted bool
+}
+
 type EventRepository interface {
-	Create(event *Event) error
-	GetAll() ([]Event, error)
+	List(opts ListOptions) ([]Event, int64, error)
 	GetByID(id uint) (*Event, error)
-	Update(event *Event) error
-	Delete(id uint) error
-	GetByNameAndType(name, eventType string) (*Event, error)
 }
 
 type PropertyRepository interface {
-	Create(property *Property) error
-	GetAll() ([]Property, error)
+	List(opts ListOptions) ([]Property, int64, error)
 	GetByID(id uint) (*Property, error)
-	Update(property *Property) error
-	Delete(id uint) error
-	GetByNameAndType(name, propertyType string) (*Property, error)
 }
 
 type TrackingPlanRepository interface {
-	Create(plan *TrackingPlan) error
-	GetAll() ([]TrackingPlan, error)
+	List(opts ListOptions) ([]TrackingPlan, int64, error)
 	GetByID(id uint) (*TrackingPlan, error)
-	Update(plan *TrackingPlan) error
-	Delete(id uint) error
 	GetByName(name string) (*TrackingPlan, error)
+	GetEventUsages(eventID uint) ([]Usage, error)
+	GetPropertyUsages(propertyID uint) ([]Usage, error)
+}
+
+// Usage is one place where a tracking plan uses an event or property, with the
+// settings that plan applies to it. Required is only set for properties.
+type Usage struct {
+	TrackingPlanID       uint   `json:"tracking_plan_id"`
+	TrackingPlanName     string `json:"tracking_plan_name"`
+	TrackingPlanEventID  uint   `json:"tracking_plan_event_id"`
+	EventID              uint   `json:"event_id"`
+	EventName            string `json:"event_name"`
+	EventType            string `json:"event_type"`
+	Required             *bool  `json:"required,omitempty"`
+	AdditionalProperties bool   `json:"additionalProperties"`
+}
+
+const (
+	AuditResourceEvent        = "event"
+	AuditResourceProperty     = "property"
+	AuditResourceTrackingPlan = "tracking_plan"
+
+	AuditActionCreate  = "create"
+	AuditActionUpdate  = "update"
+	AuditActionDelete  = "delete"
+	AuditActionRestore = "restore"
+)
+
+// AuditEntry records one change to the catalog: who made it, from which
+// client, and the resource as it was before and after. Before is null for
+// creates and After is null for deletes.
+type AuditEntry struct {
+	ID          uint            `json:"id" gorm:"primaryKey"`
+	WorkspaceID uint            `json:"-" gorm:"not null;default:0;index"`
+	Resource    string          `json:"resource" gorm:"not null;index:idx_audit_resource"`
+	ResourceID  uint            `json:"resource_id" gorm:"not null;index:idx_audit_resource"`
+	Action      string          `json:"action" gorm:"not null"`
+	ActorID     string          `json:"actor_id" gorm:"index"`
+	ActorEmail  string          `json:"actor_email"`
+	ClientID    string          `json:"client_id"`
+	Before      json.RawMessage `json:"before" gorm:"serializer:json;type:jsonb"`
+	After       json.RawMessage `json:"after" gorm:"serializer:json;type:jsonb"`
+	CreateTime  int64           `json:"create_time" gorm:"autoCreateTime;index"`
+}
+
+// AuditFilter narrows an audit log listing. Actor matches either the actor's
+// id or email.
+type AuditFilter struct {
+	Resource   string
+	ResourceID uint
+	Actor      string
+	Action     string
+}
+
+type AuditRepository interface {
+	List(filter AuditFilter, opts ListOptions) ([]AuditEntry, int64, error)
+}
+
+type WorkspaceRepository interface {
+	FirstOrCreate(slug string) (*Workspace, error)
+}
+
+// ScopeClients lets a client manage API clients, on top of the roles listed
+// in its scopes.
+const ScopeClients = "clients"
+
+// APIClient is a consumer of the API. It authenticates with its client id and
+// a secret, of which only the SHA-256 hash is stored. Scopes list the roles
+// its users may act with, plus ScopeClients for client management. Expiry,
+// revocation and last-use times are unix seconds, zero when unset.
+type APIClient struct {
+	ID         uint     `json:"id" gorm:"primaryKey"`
+	ClientID   string   `json:"client_id" gorm:"not null;uniqueIndex"`
+	Name       string   `json:"name"`
+	SecretHash string   `json:"-" gorm:"not null"`
+	Scopes     []string `json:"scopes" gorm:"serializer:json;type:jsonb"`
+	ExpiresAt  int64    `json:"expires_at,omitempty"`
+	RevokedAt  int64    `json:"revoked_at,omitempty"`
+	LastUsedAt int64    `json:"last_used_at,omitempty"`
+	CreateTime int64    `json:"create_time" gorm:"autoCreateTime"`
+	UpdateTime int64    `json:"update_time" gorm:"autoUpdateTime"`
+}
+
+// HashClientSecret returns the stored form of a client secret. Secrets are
+// long random strings, so a plain SHA-256 is enough.
+func HashClientSecret(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:])
+}
+
+// CheckSecret reports whether secret is the client's current secret.
+func (c *APIClient) CheckSecret(secret string) bool {
+	return subtle.ConstantTimeCompare([]byte(HashClientSecret(secret)), []byte(c.SecretHash)) == 1
+}
+
+// Active reports whether the client may still authenticate at time now.
+func (c *APIClient) Active(now int64) bool {
+	return c.RevokedAt == 0 && (c.ExpiresAt == 0 || c.ExpiresAt > now)
+}
+
+// EffectiveRole caps a token's role at the highest role in the client's
+// scopes, so a client limited to viewer cannot write with an editor token.
+func (c *APIClient) EffectiveRole(role string) string {
+	granted := ""
+	for _, scope := range c.Scopes {
+		if common.RoleRanks[scope] > common.RoleRanks[granted] {
+			granted = scope
+		}
+	}
+	if common.RoleRanks[role] > common.RoleRanks[granted] {
+		return granted
+	}
+	return role
+}
+
+// HasScope reports whether the client was granted scope.
+func (c *APIClient) HasScope(scope string) bool {
+	return slices.Contains(c.Scopes, scope)
+}
+
+type APIClientRepository interface {
+	Create(client *APIClient) error
+	GetAll() ([]APIClient, error)
+	GetByID(id uint) (*APIClient, error)
+	GetByClientID(clientID string) (*APIClient, error)
+	UpdateSecret(id uint, secretHash string) error
+	Revoke(id uint, at int64) error
+	Touch(id uint, at int64) error
+}
+
+type TrackingPlanVersionRepository interface {
+	GetByTrackingPlanID(trackingPlanID uint) ([]TrackingPlanVersion, error)
+	GetByVersion(trackingPlanID uint, version int) (*TrackingPlanVersion, error)
+}
+
+const (
+	SearchKindEvent        = "event"
+	SearchKindProperty     = "property"
+	SearchKindTrackingPlan = "tracking_plan"
+)
+
+type SearchResult struct {
+	Kind        string  `json:"kind"`
+	ID          uint    `json:"id"`
+	Name        string  `json:"name"`
+	Type        string  `json:"type,omitempty"`
+	Description string  `json:"description"`
+	Rank        float64 `json:"rank"`
+}
+
+type SearchRepository interface {
+	Search(tsQuery string, kinds []string, limit int) ([]SearchResult, error)
 }
 
 type TransactionManager interface {
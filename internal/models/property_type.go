@@ -0,0 +1,95 @@
+package models
+
+import (
+	"encoding/json"
+	"sort"
+	"strings"
+)
+
+const propertyTypeSeparator = "|"
+
+// PropertyType is the declared type of a property. Unions such as
+// ["string","null"] are stored in a canonical form ("string|null") so the
+// same union always maps to the same catalog row. In JSON a single type is
+// written as a string and a union as an array.
+type PropertyType string
+
+// NewPropertyType builds the canonical form of a type or union: duplicates are
+// dropped, names are sorted and "null" always comes last.
+func NewPropertyType(types ...string) PropertyType {
+	seen := make(map[string]bool, len(types))
+	names := make([]string, 0, len(types))
+	hasNull := false
+	for _, name := range types {
+		name = strings.TrimSpace(name)
+		if name == "" || seen[name] {
+			continue
+		}
+		seen[name] = true
+		if name == "null" {
+			hasNull = true
+			continue
+		}
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	if hasNull {
+		names = append(names, "null")
+	}
+	return PropertyType(strings.Join(names, propertyTypeSeparator))
+}
+
+// Types returns the individual type names of the type or union.
+func (t PropertyType) Types() []string {
+	if t == "" {
+		return nil
+	}
+	return strings.Split(string(t), propertyTypeSeparator)
+}
+
+func (t PropertyType) Has(name string) bool {
+	for _, candidate := range t.Types() {
+		if candidate == name {
+			return true
+		}
+	}
+	return false
+}
+
+func (t PropertyType) IsUnion() bool {
+	return strings.Contains(string(t), propertyTypeSeparator)
+}
+
+func (t PropertyType) MarshalJSON() ([]byte, error) {
+	if t.IsUnion() {
+		return json.Marshal(t.Types())
+	}
+	return json.Marshal(string(t))
+}
+
+func (t *PropertyType) UnmarshalJSON(data []byte) error {
+	var single string
+	if err := json.Unmarshal(data, &single); err == nil {
+		*t = NewPropertyType(strings.Split(single, propertyTypeSeparator)...)
+		return nil
+	}
+	var union []string
+	if err := json.Unmarshal(data, &union); err != nil {
+		return err
+	}
+	*t = NewPropertyType(union...)
+	return nil
+}
+
+func (t PropertyType) String() string {
+	return string(t)
+}
+
+// TypeLabel describes a property's type for messages, including the element
+// type of arrays, e.g. "string|null" or "array<number>".
+func TypeLabel(propertyType, items PropertyType) string {
+	if items == "" {
+		return propertyType.String()
+	}
+	return propertyType.String() + "<" + items.String() + ">"
+}
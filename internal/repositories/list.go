@@ -0,0 +1,77 @@
+package repositories
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/shivamrajput1826/api-catalog/internal/models"
+	"gorm.io/gorm"
+)
+
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// list runs a filtered, keyset-paginated query against the model's table and
+// returns one page together with the total number of matching rows. Scopes
+// apply to the page query only, so preloads do not run for the count.
+func list[T any](db *gorm.DB, opts models.ListOptions, scopes ...func(*gorm.DB) *gorm.DB) ([]T, int64, error) {
+	if opts.IncludeDeleted {
+		db = db.Unscoped()
+	}
+	query := applyListFilters(db.Model(new(T)), opts).Session(&gorm.Session{})
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	direction := "ASC"
+	comparison := ">"
+	if opts.Descending {
+		direction = "DESC"
+		comparison = "<"
+	}
+
+	page := query.Scopes(scopes...)
+	if opts.AfterID != 0 {
+		if opts.SortBy == "id" {
+			page = page.Where(fmt.Sprintf("id %s ?", comparison), opts.AfterID)
+		} else {
+			page = page.Where(
+				fmt.Sprintf("(%[1]s %[2]s ? OR (%[1]s = ? AND id %[2]s ?))", opts.SortBy, comparison),
+				opts.AfterValue, opts.AfterValue, opts.AfterID,
+			)
+		}
+	}
+	if opts.SortBy != "id" {
+		page = page.Order(fmt.Sprintf("%s %s", opts.SortBy, direction))
+	}
+	page = page.Order("id " + direction)
+
+	var rows []T
+	if err := page.Limit(opts.Limit).Find(&rows).Error; err != nil {
+		return nil, 0, err
+	}
+	return rows, total, nil
+}
+
+func applyListFilters(query *gorm.DB, opts models.ListOptions) *gorm.DB {
+	if opts.Type != "" {
+		query = query.Where("type = ?", opts.Type)
+	}
+	if opts.NamePrefix != "" {
+		query = query.Where("name LIKE ?", likeEscaper.Replace(opts.NamePrefix)+"%")
+	}
+	if opts.CreatedAfter != 0 {
+		query = query.Where("create_time >= ?", opts.CreatedAfter)
+	}
+	if opts.CreatedBefore != 0 {
+		query = query.Where("create_time < ?", opts.CreatedBefore)
+	}
+	if opts.UpdatedAfter != 0 {
+		query = query.Where("update_time >= ?", opts.UpdatedAfter)
+	}
+	if opts.UpdatedBefore != 0 {
+		query = query.Where("update_time < ?", opts.UpdatedBefore)
+	}
+	return query
+}
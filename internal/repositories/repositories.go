@@ -3,6 +3,7 @@ package repositories
 import (
 	"github.com/shivamrajput1826/api-catalog/internal/models"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type EventRepositoryImpl struct {
@@ -13,17 +14,10 @@ func NewEventRepository(db *gorm.DB) *EventRepositoryImpl {
 	return &EventRepositoryImpl{db: db}
 }
 
-func (r *EventRepositoryImpl) Create(event *models.Event) error {
-	return r.db.Create(event).Error
+func (r *EventRepositoryImpl) List(opts models.ListOptions) ([]models.Event, int64, error) {
+	return list[models.Event](r.db, opts)
 }
 
-func (r *EventRepositoryImpl) GetAll() ([]models.Event, error) {
-	var events []models.Event
-	if err := r.db.Find(&events).Error; err != nil {
-		return nil, err
-	}
-	return events, nil
-}
 func (r *EventRepositoryImpl) GetByID(id uint) (*models.Event, error) {
 	var event models.Event
 	if err := r.db.First(&event, id).Error; err != nil {
@@ -32,22 +26,6 @@ func (r *EventRepositoryImpl) GetByID(id uint) (*models.Event, error) {
 	return &event, nil
 }
 
-func (r *EventRepositoryImpl) Update(event *models.Event) error {
-	return r.db.Save(event).Error
-}
-
-func (r *EventRepositoryImpl) Delete(id uint) error {
-	return r.db.Delete(&models.Event{}, id).Error
-}
-
-func (r *EventRepositoryImpl) GetByNameAndType(name, eventType string) (*models.Event, error) {
-	var event models.Event
-	if err := r.db.Where("name = ? AND type = ?", name, eventType).First(&event).Error; err != nil {
-		return nil, err
-	}
-	return &event, nil
-}
-
 type PropertyRepositoryImpl struct {
 	db *gorm.DB
 }
@@ -56,14 +34,8 @@ func NewPropertyRepository(db *gorm.DB) models.PropertyRepository {
 	return &PropertyRepositoryImpl{db: db}
 }
 
-func (r *PropertyRepositoryImpl) Create(property *models.Property) error {
-	return r.db.Create(property).Error
-}
-
-func (r *PropertyRepositoryImpl) GetAll() ([]models.Property, error) {
-	var properties []models.Property
-	err := r.db.Find(&properties).Error
-	return properties, err
+func (r *PropertyRepositoryImpl) List(opts models.ListOptions) ([]models.Property, int64, error) {
+	return list[models.Property](r.db, opts)
 }
 
 func (r *PropertyRepositoryImpl) GetByID(id uint) (*models.Property, error) {
@@ -75,68 +47,196 @@ func (r *PropertyRepositoryImpl) GetByID(id uint) (*models.Property, error) {
 	return &property, nil
 }
 
-func (r *PropertyRepositoryImpl) Update(property *models.Property) error {
-	return r.db.Save(property).Error
+type TrackingPlanRepositoryImpl struct {
+	db *gorm.DB
 }
 
-func (r *PropertyRepositoryImpl) Delete(id uint) error {
-	return r.db.Delete(&models.Property{}, id).Error
+func NewTrackingPlanRepository(db *gorm.DB) models.TrackingPlanRepository {
+	return &TrackingPlanRepositoryImpl{db: db}
 }
 
-func (r *PropertyRepositoryImpl) GetByNameAndType(name, propertyType string) (*models.Property, error) {
-	var property models.Property
-	err := r.db.Where("name = ? AND type = ?", name, propertyType).First(&property).Error
+func (r *TrackingPlanRepositoryImpl) List(opts models.ListOptions) ([]models.TrackingPlan, int64, error) {
+	if !opts.WithEvents {
+		return list[models.TrackingPlan](r.db, opts)
+	}
+	return list[models.TrackingPlan](r.db, opts, func(db *gorm.DB) *gorm.DB {
+		return db.Preload("Events.Event").Preload("Events.Properties.Property")
+	})
+}
+
+func (r *TrackingPlanRepositoryImpl) GetByID(id uint) (*models.TrackingPlan, error) {
+	var plan models.TrackingPlan
+	err := r.db.Preload("Events.Event").Preload("Events.Properties.Property").First(&plan, id).Error
 	if err != nil {
 		return nil, err
 	}
-	return &property, nil
+	return &plan, nil
 }
 
-type TrackingPlanRepositoryImpl struct {
-	db *gorm.DB
+func (r *TrackingPlanRepositoryImpl) GetByName(name string) (*models.TrackingPlan, error) {
+	var plan models.TrackingPlan
+	err := r.db.Where("name = ?", name).First(&plan).Error
+	if err != nil {
+		return nil, err
+	}
+	return &plan, nil
 }
 
-func NewTrackingPlanRepository(db *gorm.DB) models.TrackingPlanRepository {
-	return &TrackingPlanRepositoryImpl{db: db}
+const usageColumns = "tracking_plans.id AS tracking_plan_id, tracking_plans.name AS tracking_plan_name, " +
+	"tracking_plan_events.id AS tracking_plan_event_id, events.id AS event_id, events.name AS event_name, " +
+	"events.type AS event_type, tracking_plan_events.additional_properties"
+
+// usages selects one row per tracking plan event, skipping deleted plans.
+func (r *TrackingPlanRepositoryImpl) usages() *gorm.DB {
+	return r.db.Model(&models.TrackingPlanEvent{}).
+		Joins("JOIN tracking_plans ON tracking_plans.id = tracking_plan_events.tracking_plan_id AND tracking_plans.deleted_at IS NULL").
+		Joins("JOIN events ON events.id = tracking_plan_events.event_id").
+		Order("tracking_plans.id, tracking_plan_events.id")
 }
 
-func (r *TrackingPlanRepositoryImpl) Create(plan *models.TrackingPlan) error {
-	return r.db.Create(plan).Error
+func (r *TrackingPlanRepositoryImpl) GetEventUsages(eventID uint) ([]models.Usage, error) {
+	var usages []models.Usage
+	err := r.usages().
+		Select(usageColumns).
+		Where("tracking_plan_events.event_id = ?", eventID).
+		Scan(&usages).Error
+	return usages, err
 }
 
-func (r *TrackingPlanRepositoryImpl) GetAll() ([]models.TrackingPlan, error) {
-	var plans []models.TrackingPlan
-	err := r.db.Preload("Events.Event").Preload("Events.Properties.Property").Find(&plans).Error
+func (r *TrackingPlanRepositoryImpl) GetPropertyUsages(propertyID uint) ([]models.Usage, error) {
+	var usages []models.Usage
+	err := r.usages().
+		Select(usageColumns+", tracking_plan_event_properties.required").
+		Joins("JOIN tracking_plan_event_properties ON tracking_plan_event_properties.tracking_plan_event_id = tracking_plan_events.id").
+		Where("tracking_plan_event_properties.property_id = ?", propertyID).
+		Scan(&usages).Error
+	return usages, err
+}
+
+type TrackingPlanVersionRepositoryImpl struct {
+	db *gorm.DB
+}
+
+func NewTrackingPlanVersionRepository(db *gorm.DB) models.TrackingPlanVersionRepository {
+	return &TrackingPlanVersionRepositoryImpl{db: db}
+}
+
+func (r *TrackingPlanVersionRepositoryImpl) GetByTrackingPlanID(trackingPlanID uint) ([]models.TrackingPlanVersion, error) {
+	var versions []models.TrackingPlanVersion
+	err := r.db.Select("id", "tracking_plan_id", "version", "create_time").
+		Where("tracking_plan_id = ?", trackingPlanID).
+		Order("version DESC").
+		Find(&versions).Error
 	if err != nil {
 		return nil, err
 	}
-	return plans, nil
+	return versions, nil
 }
 
-func (r *TrackingPlanRepositoryImpl) GetByID(id uint) (*models.TrackingPlan, error) {
-	var plan models.TrackingPlan
-	err := r.db.Preload("Events.Event").Preload("Events.Properties.Property").First(&plan, id).Error
+func (r *TrackingPlanVersionRepositoryImpl) GetByVersion(trackingPlanID uint, version int) (*models.TrackingPlanVersion, error) {
+	var planVersion models.TrackingPlanVersion
+	err := r.db.Where("tracking_plan_id = ? AND version = ?", trackingPlanID, version).First(&planVersion).Error
 	if err != nil {
 		return nil, err
 	}
-	return &plan, nil
+	return &planVersion, nil
 }
 
-func (r *TrackingPlanRepositoryImpl) Update(plan *models.TrackingPlan) error {
-	return r.db.Save(plan).Error
+type AuditRepositoryImpl struct {
+	db *gorm.DB
 }
 
-func (r *TrackingPlanRepositoryImpl) Delete(id uint) error {
-	return r.db.Delete(&models.TrackingPlan{}, id).Error
+func NewAuditRepository(db *gorm.DB) models.AuditRepository {
+	return &AuditRepositoryImpl{db: db}
 }
 
-func (r *TrackingPlanRepositoryImpl) GetByName(name string) (*models.TrackingPlan, error) {
-	var plan models.TrackingPlan
-	err := r.db.Where("name = ?", name).First(&plan).Error
+func (r *AuditRepositoryImpl) List(filter models.AuditFilter, opts models.ListOptions) ([]models.AuditEntry, int64, error) {
+	query := r.db
+	if filter.Resource != "" {
+		query = query.Where("resource = ?", filter.Resource)
+	}
+	if filter.ResourceID != 0 {
+		query = query.Where("resource_id = ?", filter.ResourceID)
+	}
+	if filter.Actor != "" {
+		query = query.Where("actor_id = ? OR actor_email = ?", filter.Actor, filter.Actor)
+	}
+	if filter.Action != "" {
+		query = query.Where("action = ?", filter.Action)
+	}
+	return list[models.AuditEntry](query, opts)
+}
+
+type WorkspaceRepositoryImpl struct {
+	db *gorm.DB
+}
+
+func NewWorkspaceRepository(db *gorm.DB) models.WorkspaceRepository {
+	return &WorkspaceRepositoryImpl{db: db}
+}
+
+// FirstOrCreate returns the workspace with the slug, creating it on first use.
+func (r *WorkspaceRepositoryImpl) FirstOrCreate(slug string) (*models.Workspace, error) {
+	workspace := models.Workspace{Slug: slug, Name: slug}
+	err := r.db.Clauses(clause.OnConflict{Columns: []clause.Column{{Name: "slug"}}, DoNothing: true}).
+		Create(&workspace).Error
 	if err != nil {
 		return nil, err
 	}
-	return &plan, nil
+	if workspace.ID == 0 {
+		if err := r.db.Where("slug = ?", slug).First(&workspace).Error; err != nil {
+			return nil, err
+		}
+	}
+	return &workspace, nil
+}
+
+type APIClientRepositoryImpl struct {
+	db *gorm.DB
+}
+
+func NewAPIClientRepository(db *gorm.DB) models.APIClientRepository {
+	return &APIClientRepositoryImpl{db: db}
+}
+
+func (r *APIClientRepositoryImpl) Create(client *models.APIClient) error {
+	return r.db.Create(client).Error
+}
+
+func (r *APIClientRepositoryImpl) GetAll() ([]models.APIClient, error) {
+	var clients []models.APIClient
+	err := r.db.Order("id").Find(&clients).Error
+	return clients, err
+}
+
+func (r *APIClientRepositoryImpl) GetByID(id uint) (*models.APIClient, error) {
+	var client models.APIClient
+	if err := r.db.First(&client, id).Error; err != nil {
+		return nil, err
+	}
+	return &client, nil
+}
+
+func (r *APIClientRepositoryImpl) GetByClientID(clientID string) (*models.APIClient, error) {
+	var client models.APIClient
+	if err := r.db.Where("client_id = ?", clientID).First(&client).Error; err != nil {
+		return nil, err
+	}
+	return &client, nil
+}
+
+func (r *APIClientRepositoryImpl) UpdateSecret(id uint, secretHash string) error {
+	return r.db.Model(&models.APIClient{ID: id}).Update("secret_hash", secretHash).Error
+}
+
+func (r *APIClientRepositoryImpl) Revoke(id uint, at int64) error {
+	return r.db.Model(&models.APIClient{ID: id}).Update("revoked_at", at).Error
+}
+
+// Touch records when the client last authenticated. It skips the update
+// hooks so the client's update time only reflects changes made by admins.
+func (r *APIClientRepositoryImpl) Touch(id uint, at int64) error {
+	return r.db.Model(&models.APIClient{ID: id}).UpdateColumn("last_used_at", at).Error
 }
 
 type TransactionManagerImpl struct {
@@ -0,0 +1,86 @@
+package repositories
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/shivamrajput1826/api-catalog/internal/models"
+	"github.com/shivamrajput1826/api-catalog/internal/workspace"
+	"gorm.io/gorm"
+)
+
+// searchDocument is the weighted text vector searched for every catalog
+// table. The GIN indexes are built on exactly this expression so Postgres can
+// use them; keep the two in sync.
+const searchDocument = "(setweight(to_tsvector('english', coalesce(name, '')), 'A') || " +
+	"setweight(to_tsvector('english', coalesce(description, '')), 'B'))"
+
+var searchTables = map[string]string{
+	models.SearchKindEvent:        "events",
+	models.SearchKindProperty:     "properties",
+	models.SearchKindTrackingPlan: "tracking_plans",
+}
+
+// CreateSearchIndexes adds the full-text indexes used by Search. AutoMigrate
+// cannot express expression indexes, so they are created here.
+func CreateSearchIndexes(db *gorm.DB) error {
+	for _, table := range searchTables {
+		statement := fmt.Sprintf("CREATE INDEX IF NOT EXISTS idx_%s_search ON %s USING GIN (%s)", table, table, searchDocument)
+		if err := db.Exec(statement).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+type SearchRepositoryImpl struct {
+	db *gorm.DB
+}
+
+func NewSearchRepository(db *gorm.DB) models.SearchRepository {
+	return &SearchRepositoryImpl{db: db}
+}
+
+// Search runs a raw query, which the workspace plugin cannot rewrite, so it
+// applies the workspace of the session itself.
+func (r *SearchRepositoryImpl) Search(tsQuery string, kinds []string, limit int) ([]models.SearchResult, error) {
+	scope := ""
+	workspaceID, scoped := workspace.FromContext(r.db.Statement.Context)
+	if scoped {
+		scope = " AND " + workspace.Column + " = ?"
+	}
+
+	selects := make([]string, 0, len(kinds))
+	args := make([]interface{}, 0, len(kinds))
+	for _, kind := range kinds {
+		table, ok := searchTables[kind]
+		if !ok {
+			return nil, fmt.Errorf("unknown search kind %q", kind)
+		}
+		typeColumn := "type"
+		if kind == models.SearchKindTrackingPlan {
+			typeColumn = "''"
+		}
+		selects = append(selects, fmt.Sprintf(
+			"SELECT '%s' AS kind, id, name, %s AS type, description, ts_rank(%s, q) AS rank "+
+				"FROM %s, to_tsquery('english', ?) q WHERE %s @@ q AND deleted_at IS NULL%s",
+			kind, typeColumn, searchDocument, table, searchDocument, scope,
+		))
+		args = append(args, tsQuery)
+		if scoped {
+			args = append(args, workspaceID)
+		}
+	}
+
+	statement := fmt.Sprintf(
+		"SELECT kind, id, name, type, description, rank FROM (%s) results ORDER BY rank DESC, kind, id LIMIT ?",
+		strings.Join(selects, " UNION ALL "),
+	)
+	args = append(args, limit)
+
+	var results []models.SearchResult
+	if err := workspace.AllowRaw(r.db).Raw(statement, args...).Scan(&results).Error; err != nil {
+		return nil, err
+	}
+	return results, nil
+}
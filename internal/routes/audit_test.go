@@ -0,0 +1,96 @@
+package routes
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/shivamrajput1826/api-catalog/common"
+)
+
+type auditEntry struct {
+	Resource   string          `json:"resource"`
+	ResourceID uint            `json:"resource_id"`
+	Action     string          `json:"action"`
+	ActorID    string          `json:"actor_id"`
+	Before     json.RawMessage `json:"before"`
+	After      json.RawMessage `json:"after"`
+}
+
+func (c *client) auditLog() []auditEntry {
+	var page struct {
+		Data []auditEntry `json:"data"`
+	}
+	c.do(http.MethodGet, "/api/v1/audit?order=asc&limit=100", nil).expect(http.StatusOK).decode(&page)
+	return page.Data
+}
+
+func TestAuditLogRecordsCommittedChanges(t *testing.T) {
+	api := newTestAPI(t)
+	admin := api.client("acme", common.RoleAdmin)
+
+	eventID := admin.do(http.MethodPost, "/api/v1/events", map[string]interface{}{
+		"name": "Signed Up", "type": "track",
+	}).expect(http.StatusCreated).id()
+	admin.do(http.MethodPut, fmt.Sprintf("/api/v1/events/%d", eventID), map[string]interface{}{
+		"name": "Signed Up", "type": "track", "description": "A user signed up",
+	}).expect(http.StatusOK)
+	// A change rejected by its precondition is not logged.
+	admin.do(http.MethodPut, fmt.Sprintf("/api/v1/events/%d", eventID), map[string]interface{}{
+		"name": "Signed Up", "type": "track",
+	}, "If-Match", `"1"`).expect(http.StatusPreconditionFailed)
+
+	planID := admin.do(http.MethodPost, "/api/v1/tracking-plans", map[string]interface{}{
+		"name":   "Web",
+		"events": []map[string]interface{}{{"name": "Signed Up", "type": "track"}},
+	}).expect(http.StatusCreated).id()
+
+	// A delete blocked by a referencing plan is not logged either.
+	admin.do(http.MethodDelete, fmt.Sprintf("/api/v1/events/%d", eventID), nil).expect(http.StatusConflict)
+	admin.do(http.MethodDelete, fmt.Sprintf("/api/v1/events/%d?cascade=true", eventID), nil).expect(http.StatusNoContent)
+	admin.do(http.MethodPost, fmt.Sprintf("/api/v1/events/%d/restore", eventID), nil).expect(http.StatusOK)
+
+	want := []struct {
+		resource string
+		id       uint
+		action   string
+	}{
+		{"event", eventID, "create"},
+		{"event", eventID, "update"},
+		{"tracking_plan", planID, "create"},
+		{"event", eventID, "delete"},
+		{"tracking_plan", planID, "update"},
+		{"event", eventID, "restore"},
+	}
+	entries := admin.auditLog()
+	if len(entries) != len(want) {
+		t.Fatalf("got %d audit entries, want %d: %+v", len(entries), len(want), entries)
+	}
+	for i, entry := range entries {
+		if entry.Resource != want[i].resource || entry.ResourceID != want[i].id || entry.Action != want[i].action {
+			t.Errorf("entry %d: got %s %d %s, want %s %d %s", i,
+				entry.Resource, entry.ResourceID, entry.Action, want[i].resource, want[i].id, want[i].action)
+		}
+		if entry.ActorID != "acme-user" {
+			t.Errorf("entry %d: got actor %q", i, entry.ActorID)
+		}
+	}
+
+	// The cascade entry of the plan goes from the plan with the event to the
+	// plan without it.
+	var before, after struct {
+		Events []struct {
+			EventID uint `json:"event_id"`
+		} `json:"events"`
+	}
+	if err := json.Unmarshal(entries[4].Before, &before); err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal(entries[4].After, &after); err != nil {
+		t.Fatal(err)
+	}
+	if len(before.Events) != 1 || before.Events[0].EventID != eventID || len(after.Events) != 0 {
+		t.Errorf("unexpected cascade entry: before %s, after %s", entries[4].Before, entries[4].After)
+	}
+}
@@ -0,0 +1,143 @@
+package routes
+
+import (
+	"net/http"
+	"reflect"
+	"testing"
+
+	"github.com/shivamrajput1826/api-catalog/common"
+	"github.com/shivamrajput1826/api-catalog/internal/dtos"
+	"github.com/shivamrajput1826/api-catalog/internal/services"
+)
+
+func (c *client) bulkImport(resource, mode, contentType, body string, status int) dtos.BulkImportResponse {
+	c.api.t.Helper()
+	var result dtos.BulkImportResponse
+	c.do(http.MethodPost, "/api/v1/"+resource+"/bulk?mode="+mode, []byte(body), "Content-Type", contentType).
+		expect(status).decode(&result)
+	return result
+}
+
+func (c *client) count(resource string) int {
+	c.api.t.Helper()
+	var page struct {
+		Data []struct{} `json:"data"`
+	}
+	c.do(http.MethodGet, "/api/v1/"+resource, nil).expect(http.StatusOK).decode(&page)
+	return len(page.Data)
+}
+
+func statuses(result dtos.BulkImportResponse) []string {
+	var statuses []string
+	for _, row := range result.Results {
+		statuses = append(statuses, row.Status)
+	}
+	return statuses
+}
+
+func TestBulkImportEvents(t *testing.T) {
+	api := newTestAPI(t)
+	editor := api.client("acme", common.RoleEditor)
+
+	editor.do(http.MethodPost, "/api/v1/events", map[string]interface{}{
+		"name": "Signed Up", "type": "track",
+	}).expect(http.StatusCreated)
+
+	rows := `{"name":"Signed Up","type":"track"}
+{"name":"Logged In","type":"track"}
+{"name":"Broken","type":"unknown"}
+{"name":"Home","type":"page"}
+`
+
+	// One invalid row abandons an atomic import before anything is saved:
+	// every valid row is skipped and nothing is written.
+	result := editor.bulkImport("events", services.BulkModeAtomic, services.NDJSONContentType, rows, http.StatusUnprocessableEntity)
+	want := []string{services.BulkStatusSkipped, services.BulkStatusSkipped, services.BulkStatusFailed, services.BulkStatusSkipped}
+	if result.Committed || !reflect.DeepEqual(statuses(result), want) {
+		t.Fatalf("atomic: got committed %t, statuses %v, want %v", result.Committed, statuses(result), want)
+	}
+	if result.Skipped != 3 || result.Failed != 1 || result.Created != 0 || result.Existing != 0 {
+		t.Errorf("atomic: unexpected counts %+v", result)
+	}
+	if result.Results[2].Error == "" {
+		t.Error("atomic: failed row has no error")
+	}
+	if n := editor.count("events"); n != 1 {
+		t.Fatalf("atomic: got %d events after an abandoned import, want 1", n)
+	}
+
+	// Best effort writes every valid row and only fails the invalid one.
+	result = editor.bulkImport("events", services.BulkModeBestEffort, services.NDJSONContentType, rows, http.StatusOK)
+	want = []string{services.BulkStatusExisting, services.BulkStatusCreated, services.BulkStatusFailed, services.BulkStatusCreated}
+	if !result.Committed || !reflect.DeepEqual(statuses(result), want) {
+		t.Fatalf("best effort: got committed %t, statuses %v, want %v", result.Committed, statuses(result), want)
+	}
+	if result.Created != 2 || result.Existing != 1 || result.Failed != 1 || result.Skipped != 0 {
+		t.Errorf("best effort: unexpected counts %+v", result)
+	}
+	if result.Results[1].ID == 0 || result.Results[3].ID == 0 {
+		t.Errorf("best effort: created rows have no id: %+v", result.Results)
+	}
+	if n := editor.count("events"); n != 3 {
+		t.Fatalf("best effort: got %d events, want 3", n)
+	}
+
+	// A fully valid atomic import commits, and repeating it is a no-op.
+	rows = `{"name":"Logged Out","type":"track"}
+{"name":"Home","type":"page"}
+`
+	result = editor.bulkImport("events", services.BulkModeAtomic, services.NDJSONContentType, rows, http.StatusOK)
+	want = []string{services.BulkStatusCreated, services.BulkStatusExisting}
+	if !result.Committed || !reflect.DeepEqual(statuses(result), want) {
+		t.Fatalf("valid atomic: got committed %t, statuses %v, want %v", result.Committed, statuses(result), want)
+	}
+	result = editor.bulkImport("events", services.BulkModeAtomic, services.NDJSONContentType, rows, http.StatusOK)
+	if result.Created != 0 || result.Existing != 2 {
+		t.Errorf("repeated atomic: unexpected counts %+v", result)
+	}
+	if n := editor.count("events"); n != 4 {
+		t.Fatalf("got %d events, want 4", n)
+	}
+
+	editor.do(http.MethodPost, "/api/v1/events/bulk?mode=eventually", []byte(rows),
+		"Content-Type", services.NDJSONContentType).expect(http.StatusBadRequest)
+	editor.do(http.MethodPost, "/api/v1/events/bulk", []byte(rows),
+		"Content-Type", "application/xml").expect(http.StatusUnsupportedMediaType)
+}
+
+func TestBulkImportPropertiesCSV(t *testing.T) {
+	api := newTestAPI(t)
+	editor := api.client("acme", common.RoleEditor)
+
+	rows := "name,type,description,minimum\n" +
+		"plan,string,Billing plan,\n" +
+		"seats,integer,Seat count,1\n" +
+		"price,number,Price,not-a-number\n"
+
+	result := editor.bulkImport("properties", services.BulkModeAtomic, services.CSVContentType, rows, http.StatusUnprocessableEntity)
+	want := []string{services.BulkStatusSkipped, services.BulkStatusSkipped, services.BulkStatusFailed}
+	if result.Committed || !reflect.DeepEqual(statuses(result), want) {
+		t.Fatalf("atomic: got committed %t, statuses %v, want %v", result.Committed, statuses(result), want)
+	}
+	if n := editor.count("properties"); n != 0 {
+		t.Fatalf("atomic: got %d properties after an abandoned import, want 0", n)
+	}
+
+	result = editor.bulkImport("properties", services.BulkModeBestEffort, services.CSVContentType, rows, http.StatusOK)
+	want = []string{services.BulkStatusCreated, services.BulkStatusCreated, services.BulkStatusFailed}
+	if !result.Committed || !reflect.DeepEqual(statuses(result), want) {
+		t.Fatalf("best effort: got committed %t, statuses %v, want %v", result.Committed, statuses(result), want)
+	}
+	if result.Results[0].Name != "plan" || result.Results[1].Name != "seats" {
+		t.Errorf("best effort: unexpected names %+v", result.Results)
+	}
+	if n := editor.count("properties"); n != 2 {
+		t.Fatalf("best effort: got %d properties, want 2", n)
+	}
+
+	result = editor.bulkImport("properties", services.BulkModeBestEffort, services.CSVContentType, rows, http.StatusOK)
+	want = []string{services.BulkStatusExisting, services.BulkStatusExisting, services.BulkStatusFailed}
+	if !reflect.DeepEqual(statuses(result), want) {
+		t.Fatalf("repeated best effort: got statuses %v, want %v", statuses(result), want)
+	}
+}
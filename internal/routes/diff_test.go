@@ -0,0 +1,72 @@
+package routes
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/shivamrajput1826/api-catalog/common"
+	"github.com/shivamrajput1826/api-catalog/internal/dtos"
+	"github.com/shivamrajput1826/api-catalog/internal/services"
+)
+
+func TestCompareTrackingPlanVersions(t *testing.T) {
+	api := newTestAPI(t)
+	editor := api.client("acme", common.RoleEditor)
+
+	signedUp := map[string]interface{}{
+		"name": "Signed Up", "type": "track",
+		"properties": []map[string]interface{}{{"name": "plan", "type": "string", "required": true}},
+	}
+	webID := editor.do(http.MethodPost, "/api/v1/tracking-plans", map[string]interface{}{
+		"name": "Web", "events": []interface{}{signedUp},
+	}).expect(http.StatusCreated).id()
+
+	signedUp["properties"] = []map[string]interface{}{{"name": "plan", "type": "string"}}
+	editor.do(http.MethodPut, fmt.Sprintf("/api/v1/tracking-plans/%d", webID), map[string]interface{}{
+		"name": "Web", "events": []interface{}{signedUp, map[string]interface{}{"name": "Home", "type": "page"}},
+	}).expect(http.StatusOK)
+
+	diff := func(query string) dtos.TrackingPlanDiffResponse {
+		t.Helper()
+		var result dtos.TrackingPlanDiffResponse
+		editor.do(http.MethodGet, "/api/v1/tracking-plans/diff?"+query, nil).expect(http.StatusOK).decode(&result)
+		return result
+	}
+	kinds := func(result dtos.TrackingPlanDiffResponse) []string {
+		kinds := []string{}
+		for _, change := range result.Changes {
+			kinds = append(kinds, change.Kind)
+		}
+		return kinds
+	}
+
+	// Version 1 against the current plan, which is version 2.
+	result := diff(fmt.Sprintf("base=%d&base_version=1&head=%d", webID, webID))
+	want := []string{services.ChangeRequiredChanged, services.ChangeEventAdded}
+	if fmt.Sprint(kinds(result)) != fmt.Sprint(want) {
+		t.Errorf("version 1 to current: got %v, want %v", kinds(result), want)
+	}
+
+	// The same versions compared explicitly, in reverse.
+	result = diff(fmt.Sprintf("base=%d&base_version=2&head=%d&head_version=1", webID, webID))
+	want = []string{services.ChangeRequiredChanged, services.ChangeEventRemoved}
+	if fmt.Sprint(kinds(result)) != fmt.Sprint(want) {
+		t.Errorf("version 2 to 1: got %v, want %v", kinds(result), want)
+	}
+
+	// Two separate plans.
+	appID := editor.do(http.MethodPost, "/api/v1/tracking-plans", map[string]interface{}{
+		"name": "App", "events": []interface{}{signedUp},
+	}).expect(http.StatusCreated).id()
+	result = diff(fmt.Sprintf("base=%d&head=%d", webID, appID))
+	want = []string{services.ChangeEventRemoved}
+	if fmt.Sprint(kinds(result)) != fmt.Sprint(want) || result.Changes[0].Event != "Home" {
+		t.Errorf("web to app: got %+v", result.Changes)
+	}
+
+	editor.do(http.MethodGet, fmt.Sprintf("/api/v1/tracking-plans/diff?base=%d&base_version=9&head=%d", webID, webID), nil).
+		expect(http.StatusNotFound)
+	editor.do(http.MethodGet, fmt.Sprintf("/api/v1/tracking-plans/diff?head=%d", webID), nil).
+		expect(http.StatusBadRequest)
+}
@@ -0,0 +1,193 @@
+package routes
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/shivamrajput1826/api-catalog/common"
+)
+
+type planMembers struct {
+	ID      uint `json:"id"`
+	Version int  `json:"version"`
+	Events  []struct {
+		ID                   uint `json:"id"`
+		EventID              uint `json:"event_id"`
+		AdditionalProperties bool `json:"additionalProperties"`
+		Event                struct {
+			Name string `json:"name"`
+			Type string `json:"type"`
+		} `json:"event"`
+		Properties []struct {
+			ID         uint `json:"id"`
+			PropertyID uint `json:"property_id"`
+			Required   bool `json:"required"`
+			MinLength  *int `json:"minLength"`
+			Property   struct {
+				Name string `json:"name"`
+			} `json:"property"`
+		} `json:"properties"`
+	} `json:"events"`
+}
+
+// ids maps the event and property names of a plan to the ids of their plan
+// rows, as "event" and "event.property".
+func (p planMembers) ids() map[string]uint {
+	ids := make(map[string]uint)
+	for _, event := range p.Events {
+		ids[event.Event.Name] = event.ID
+		for _, prop := range event.Properties {
+			ids[event.Event.Name+"."+prop.Property.Name] = prop.ID
+		}
+	}
+	return ids
+}
+
+func TestTrackingPlanMembership(t *testing.T) {
+	api := newTestAPI(t)
+	editor := api.client("acme", common.RoleEditor)
+
+	planID := editor.do(http.MethodPost, "/api/v1/tracking-plans", map[string]interface{}{
+		"name": "Web",
+		"events": []map[string]interface{}{{
+			"name": "Signed Up", "type": "track",
+			"properties": []map[string]interface{}{{"name": "plan", "type": "string", "required": true}},
+		}},
+	}).expect(http.StatusCreated).id()
+	planPath := fmt.Sprintf("/api/v1/tracking-plans/%d", planID)
+	propertyID := editor.do(http.MethodPost, "/api/v1/properties", map[string]interface{}{
+		"name": "coupon", "type": "string",
+	}).expect(http.StatusCreated).id()
+
+	// Events added to a plan must have a valid type.
+	for _, eventType := range []string{"", "bogus"} {
+		editor.do(http.MethodPost, planPath+"/events", map[string]interface{}{
+			"name": "Checked Out", "type": eventType,
+		}, "If-Match", `"1"`).expect(http.StatusBadRequest)
+	}
+	if n := editor.count("events"); n != 1 {
+		t.Fatalf("got %d events after rejected adds, want 1", n)
+	}
+
+	var plan planMembers
+	resp := editor.do(http.MethodPost, planPath+"/events", map[string]interface{}{
+		"name": "Checked Out", "type": "track",
+	}, "If-Match", `"1"`).expect(http.StatusCreated)
+	resp.decode(&plan)
+	if plan.Version != 2 || len(plan.Events) != 2 || resp.header.Get("ETag") != `"2"` {
+		t.Fatalf("add event: got version %d with %d events, ETag %s", plan.Version, len(plan.Events), resp.header.Get("ETag"))
+	}
+	checkedOut := plan.Events[1].EventID
+	editor.do(http.MethodPost, planPath+"/events", map[string]interface{}{
+		"name": "Checked Out", "type": "track",
+	}).expect(http.StatusConflict)
+
+	eventPath := fmt.Sprintf("%s/events/%d", planPath, checkedOut)
+	editor.do(http.MethodPut, fmt.Sprintf("%s/properties/%d", eventPath, propertyID), map[string]interface{}{
+		"required": true, "minLength": 3,
+	}, "If-Match", `"2"`).expect(http.StatusOK).decode(&plan)
+	props := plan.Events[1].Properties
+	if plan.Version != 3 || len(props) != 1 || props[0].PropertyID != propertyID || !props[0].Required ||
+		props[0].MinLength == nil || *props[0].MinLength != 3 {
+		t.Fatalf("set property: got %+v", plan)
+	}
+	propRowID := props[0].ID
+
+	// Setting the property again updates the same row.
+	plan = planMembers{}
+	editor.do(http.MethodPut, fmt.Sprintf("%s/properties/%d", eventPath, propertyID), map[string]interface{}{
+		"required": false,
+	}).expect(http.StatusOK).decode(&plan)
+	props = plan.Events[1].Properties
+	if len(props) != 1 || props[0].ID != propRowID || props[0].Required || props[0].MinLength != nil {
+		t.Fatalf("update property: got %+v", props)
+	}
+
+	editor.do(http.MethodPut, fmt.Sprintf("%s/properties/999", eventPath), map[string]interface{}{}).expect(http.StatusNotFound)
+	editor.do(http.MethodPut, fmt.Sprintf("%s/events/999/properties/%d", planPath, propertyID), map[string]interface{}{}).expect(http.StatusNotFound)
+	editor.do(http.MethodDelete, fmt.Sprintf("%s/properties/%d", eventPath, propertyID), nil, "If-Match", `"1"`).expect(http.StatusPreconditionFailed)
+
+	editor.do(http.MethodDelete, fmt.Sprintf("%s/properties/%d", eventPath, propertyID), nil).expect(http.StatusNoContent)
+	editor.do(http.MethodDelete, fmt.Sprintf("%s/properties/%d", eventPath, propertyID), nil).expect(http.StatusNotFound)
+	editor.do(http.MethodDelete, eventPath, nil).expect(http.StatusNoContent)
+	editor.do(http.MethodDelete, eventPath, nil).expect(http.StatusNotFound)
+
+	editor.do(http.MethodGet, planPath, nil).expect(http.StatusOK).decode(&plan)
+	if plan.Version != 6 || len(plan.Events) != 1 || plan.Events[0].Event.Name != "Signed Up" {
+		t.Fatalf("after removals: got %+v", plan)
+	}
+	// Detaching leaves the catalog untouched.
+	if n := editor.count("events"); n != 2 {
+		t.Errorf("got %d events, want 2", n)
+	}
+	if n := editor.count("properties"); n != 2 {
+		t.Errorf("got %d properties, want 2", n)
+	}
+}
+
+func TestUpdateTrackingPlanKeepsRowIDs(t *testing.T) {
+	api := newTestAPI(t)
+	editor := api.client("acme", common.RoleEditor)
+
+	signedUp := map[string]interface{}{
+		"name": "Signed Up", "type": "track",
+		"properties": []map[string]interface{}{
+			{"name": "plan", "type": "string", "required": true},
+			{"name": "referrer", "type": "string"},
+		},
+	}
+	home := map[string]interface{}{"name": "Home", "type": "page"}
+
+	var plan planMembers
+	editor.do(http.MethodPost, "/api/v1/tracking-plans", map[string]interface{}{
+		"name": "Web", "events": []interface{}{signedUp, home},
+	}).expect(http.StatusCreated).decode(&plan)
+	before := plan.ids()
+	planPath := fmt.Sprintf("/api/v1/tracking-plans/%d", plan.ID)
+
+	// Reorder the events, flip a property, drop one property and add another.
+	signedUp["additionalProperties"] = true
+	signedUp["properties"] = []map[string]interface{}{
+		{"name": "plan", "type": "string"},
+		{"name": "coupon", "type": "string"},
+	}
+	editor.do(http.MethodPut, planPath, map[string]interface{}{
+		"name": "Web", "events": []interface{}{home, signedUp},
+	}).expect(http.StatusOK).decode(&plan)
+	after := plan.ids()
+
+	for _, key := range []string{"Signed Up", "Home", "Signed Up.plan"} {
+		if after[key] == 0 || after[key] != before[key] {
+			t.Errorf("%s: row id changed from %d to %d", key, before[key], after[key])
+		}
+	}
+	if _, ok := after["Signed Up.referrer"]; ok {
+		t.Error("removed property is still on the event")
+	}
+	if after["Signed Up.coupon"] == 0 {
+		t.Error("added property is missing")
+	}
+	for _, event := range plan.Events {
+		if event.Event.Name != "Signed Up" {
+			continue
+		}
+		if !event.AdditionalProperties {
+			t.Error("additionalProperties was not updated")
+		}
+		for _, prop := range event.Properties {
+			if prop.Property.Name == "plan" && prop.Required {
+				t.Error("required was not updated")
+			}
+		}
+	}
+
+	// Dropping an event deletes only its row.
+	editor.do(http.MethodPut, planPath, map[string]interface{}{
+		"name": "Web", "events": []interface{}{signedUp},
+	}).expect(http.StatusOK).decode(&plan)
+	final := plan.ids()
+	if len(plan.Events) != 1 || final["Signed Up"] != before["Signed Up"] || final["Signed Up.plan"] != before["Signed Up.plan"] {
+		t.Errorf("after dropping an event: got %v, started from %v", final, before)
+	}
+}
@@ -0,0 +1,42 @@
+package routes
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/spf13/viper"
+)
+
+func TestMetricsNeedToken(t *testing.T) {
+	scrape := func(api *testAPI, authorization string) int {
+		req := httptest.NewRequest(http.MethodGet, "/metrics", nil)
+		if authorization != "" {
+			req.Header.Set("Authorization", authorization)
+		}
+		resp, err := api.app.Test(req, -1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		return resp.StatusCode
+	}
+
+	if status := scrape(newTestAPI(t), "Bearer "); status != http.StatusNotFound {
+		t.Errorf("without METRICS_TOKEN: got status %d, want 404", status)
+	}
+
+	viper.Set("METRICS_TOKEN", "scrape-token")
+	t.Cleanup(func() { viper.Set("METRICS_TOKEN", "") })
+	api := newTestAPI(t)
+	for authorization, want := range map[string]int{
+		"":                    http.StatusUnauthorized,
+		"Bearer wrong-token":  http.StatusUnauthorized,
+		"scrape-token":        http.StatusUnauthorized,
+		"Bearer scrape-token": http.StatusOK,
+	} {
+		if status := scrape(api, authorization); status != want {
+			t.Errorf("Authorization %q: got status %d, want %d", authorization, status, want)
+		}
+	}
+}
@@ -0,0 +1,85 @@
+package routes
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/shivamrajput1826/api-catalog/common"
+	"github.com/shivamrajput1826/api-catalog/internal/services"
+)
+
+type patchedEvent struct {
+	Name        string `json:"name"`
+	Type        string `json:"type"`
+	Description string `json:"description"`
+	Version     int    `json:"version"`
+}
+
+func TestPatchEvent(t *testing.T) {
+	api := newTestAPI(t)
+	editor := api.client("acme", common.RoleEditor)
+
+	eventID := editor.do(http.MethodPost, "/api/v1/events", map[string]interface{}{
+		"name": "Signed Up", "type": "track", "description": "A user signed up",
+	}).expect(http.StatusCreated).id()
+	path := fmt.Sprintf("/api/v1/events/%d", eventID)
+	get := func() patchedEvent {
+		var event patchedEvent
+		editor.do(http.MethodGet, path, nil).expect(http.StatusOK).decode(&event)
+		return event
+	}
+
+	var event patchedEvent
+	resp := editor.do(http.MethodPatch, path, []byte(`{"description":"Account created"}`),
+		"Content-Type", services.MergePatchContentType, "If-Match", `"1"`).expect(http.StatusOK)
+	resp.decode(&event)
+	if event.Name != "Signed Up" || event.Type != "track" || event.Description != "Account created" || event.Version != 2 {
+		t.Fatalf("merge patch: got %+v", event)
+	}
+	if etag := resp.header.Get("ETag"); etag != `"2"` {
+		t.Errorf("merge patch: got ETag %s", etag)
+	}
+
+	editor.do(http.MethodPatch, path,
+		[]byte(`[{"op":"test","path":"/description","value":"Account created"},{"op":"replace","path":"/name","value":"Account Created"}]`),
+		"Content-Type", services.JSONPatchContentType).expect(http.StatusOK).decode(&event)
+	if event.Name != "Account Created" || event.Description != "Account created" || event.Version != 3 {
+		t.Fatalf("JSON Patch: got %+v", event)
+	}
+
+	rejected := []struct {
+		name        string
+		body        string
+		contentType string
+		ifMatch     string
+		status      int
+	}{
+		{"failed test op", `[{"op":"test","path":"/description","value":"stale"},{"op":"replace","path":"/name","value":"Lost"}]`, services.JSONPatchContentType, "", http.StatusConflict},
+		{"missing path", `[{"op":"replace","path":"/missing/field","value":"Lost"}]`, services.JSONPatchContentType, "", http.StatusConflict},
+		{"malformed JSON Patch", `{"op":"replace"}`, services.JSONPatchContentType, "", http.StatusBadRequest},
+		{"malformed merge patch", `{"name":`, services.MergePatchContentType, "", http.StatusBadRequest},
+		{"wrong field type", `{"name":42}`, services.MergePatchContentType, "", http.StatusBadRequest},
+		{"invalid result", `{"type":"unknown"}`, services.MergePatchContentType, "", http.StatusBadRequest},
+		{"plain JSON", `{"name":"Lost"}`, "application/json", "", http.StatusUnsupportedMediaType},
+		{"stale If-Match", `{"name":"Lost"}`, services.MergePatchContentType, `"1"`, http.StatusPreconditionFailed},
+	}
+	for _, tc := range rejected {
+		headers := []string{"Content-Type", tc.contentType}
+		if tc.ifMatch != "" {
+			headers = append(headers, "If-Match", tc.ifMatch)
+		}
+		resp := editor.do(http.MethodPatch, path, []byte(tc.body), headers...)
+		if resp.status != tc.status {
+			t.Errorf("%s: got status %d, want %d: %s", tc.name, resp.status, tc.status, resp.body)
+		}
+	}
+
+	// None of the rejected patches changed the event.
+	if got := get(); got.Name != "Account Created" || got.Description != "Account created" || got.Version != 3 {
+		t.Errorf("rejected patches changed the event: %+v", got)
+	}
+
+	editor.do(http.MethodPatch, "/api/v1/events/999", []byte(`{"name":"Lost"}`),
+		"Content-Type", services.MergePatchContentType).expect(http.StatusNotFound)
+}
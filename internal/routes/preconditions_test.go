@@ -0,0 +1,74 @@
+package routes
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/shivamrajput1826/api-catalog/common"
+)
+
+func TestETagsAndIfMatch(t *testing.T) {
+	api := newTestAPI(t)
+	admin := api.client("acme", common.RoleAdmin)
+
+	etag := func(resp *response) string {
+		t.Helper()
+		return resp.header.Get("ETag")
+	}
+
+	resources := []struct {
+		path   string
+		create map[string]interface{}
+		update map[string]interface{}
+	}{
+		{
+			"/api/v1/events",
+			map[string]interface{}{"name": "Signed Up", "type": "track"},
+			map[string]interface{}{"name": "Signed Up", "type": "track", "description": "A user signed up"},
+		},
+		{
+			"/api/v1/properties",
+			map[string]interface{}{"name": "plan", "type": "string"},
+			map[string]interface{}{"name": "plan", "type": "string", "description": "Billing plan"},
+		},
+		{
+			"/api/v1/tracking-plans",
+			map[string]interface{}{"name": "Web", "events": []map[string]interface{}{{"name": "Home", "type": "page"}}},
+			map[string]interface{}{"name": "Web", "description": "Website", "events": []map[string]interface{}{{"name": "Home", "type": "page"}}},
+		},
+	}
+	for _, resource := range resources {
+		resp := admin.do(http.MethodPost, resource.path, resource.create).expect(http.StatusCreated)
+		path := fmt.Sprintf("%s/%d", resource.path, resp.id())
+		if got := etag(resp); got != `"1"` {
+			t.Errorf("POST %s: got ETag %s, want \"1\"", resource.path, got)
+		}
+
+		// Every PUT bumps the version, and GET reports the same ETag.
+		for version := 2; version <= 3; version++ {
+			want := fmt.Sprintf(`"%d"`, version)
+			if got := etag(admin.do(http.MethodPut, path, resource.update, "If-Match", fmt.Sprintf(`"%d"`, version-1)).expect(http.StatusOK)); got != want {
+				t.Errorf("PUT %s: got ETag %s, want %s", path, got, want)
+			}
+			if got := etag(admin.do(http.MethodGet, path, nil).expect(http.StatusOK)); got != want {
+				t.Errorf("GET %s: got ETag %s, want %s", path, got, want)
+			}
+		}
+		admin.do(http.MethodPut, path, resource.update, "If-Match", `"2"`).expect(http.StatusPreconditionFailed)
+
+		// A stale or weak If-Match keeps the row; a list holding the current
+		// version deletes it.
+		admin.do(http.MethodDelete, path, nil, "If-Match", `"2"`).expect(http.StatusPreconditionFailed)
+		admin.do(http.MethodDelete, path, nil, "If-Match", `W/"3"`).expect(http.StatusPreconditionFailed)
+		admin.do(http.MethodGet, path, nil).expect(http.StatusOK)
+		admin.do(http.MethodDelete, path, nil, "If-Match", `"1", "3"`).expect(http.StatusNoContent)
+		admin.do(http.MethodGet, path, nil).expect(http.StatusNotFound)
+	}
+
+	// "*" matches any version.
+	path := fmt.Sprintf("/api/v1/events/%d", admin.do(http.MethodPost, "/api/v1/events", map[string]interface{}{
+		"name": "Logged In", "type": "track",
+	}).expect(http.StatusCreated).id())
+	admin.do(http.MethodDelete, path, nil, "If-Match", "*").expect(http.StatusNoContent)
+}
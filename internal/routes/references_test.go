@@ -0,0 +1,114 @@
+package routes
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/shivamrajput1826/api-catalog/common"
+)
+
+func TestDeleteReferencedItems(t *testing.T) {
+	api := newTestAPI(t)
+	admin := api.client("acme", common.RoleAdmin)
+
+	newPlan := func(name string) uint {
+		return admin.do(http.MethodPost, "/api/v1/tracking-plans", map[string]interface{}{
+			"name": name,
+			"events": []map[string]interface{}{
+				{
+					"name": "Signed Up", "type": "track",
+					"properties": []map[string]interface{}{{"name": "plan", "type": "string", "required": true}},
+				},
+				{
+					"name": "Home", "type": "page",
+					"properties": []map[string]interface{}{{"name": "plan", "type": "string"}},
+				},
+			},
+		}).expect(http.StatusCreated).id()
+	}
+	webID, appID, oldID := newPlan("Web"), newPlan("App"), newPlan("Old")
+	admin.do(http.MethodDelete, fmt.Sprintf("/api/v1/tracking-plans/%d", oldID), nil).expect(http.StatusNoContent)
+
+	var events struct {
+		Data []struct {
+			ID   uint   `json:"id"`
+			Name string `json:"name"`
+		} `json:"data"`
+	}
+	admin.do(http.MethodGet, "/api/v1/events?name_prefix=Signed", nil).expect(http.StatusOK).decode(&events)
+	eventPath := fmt.Sprintf("/api/v1/events/%d", events.Data[0].ID)
+	var properties struct {
+		Data []struct {
+			ID uint `json:"id"`
+		} `json:"data"`
+	}
+	admin.do(http.MethodGet, "/api/v1/properties", nil).expect(http.StatusOK).decode(&properties)
+	propertyPath := fmt.Sprintf("/api/v1/properties/%d", properties.Data[0].ID)
+
+	// Deletes of items in use list the live plans using them.
+	for _, path := range []string{eventPath, propertyPath} {
+		var conflict struct {
+			Error         string `json:"error"`
+			TrackingPlans []struct {
+				TrackingPlanID uint   `json:"tracking_plan_id"`
+				Name           string `json:"name"`
+			} `json:"tracking_plans"`
+		}
+		admin.do(http.MethodDelete, path, nil).expect(http.StatusConflict).decode(&conflict)
+		plans := conflict.TrackingPlans
+		if conflict.Error == "" || len(plans) != 2 || plans[0].TrackingPlanID != webID || plans[1].TrackingPlanID != appID {
+			t.Errorf("DELETE %s: got conflict %+v", path, conflict)
+		}
+	}
+
+	type members struct {
+		Version int `json:"version"`
+		Events  []struct {
+			Event struct {
+				Name string `json:"name"`
+			} `json:"event"`
+			Properties []struct {
+				PropertyID uint `json:"property_id"`
+			} `json:"properties"`
+		} `json:"events"`
+	}
+	plan := func(id uint) members {
+		t.Helper()
+		var plan members
+		admin.do(http.MethodGet, fmt.Sprintf("/api/v1/tracking-plans/%d", id), nil).expect(http.StatusOK).decode(&plan)
+		return plan
+	}
+
+	// Cascading detaches the property from every event of the live plans,
+	// with one new version per plan.
+	admin.do(http.MethodDelete, propertyPath+"?cascade=true", nil).expect(http.StatusNoContent)
+	for _, id := range []uint{webID, appID} {
+		got := plan(id)
+		if got.Version != 2 || len(got.Events) != 2 || len(got.Events[0].Properties) != 0 || len(got.Events[1].Properties) != 0 {
+			t.Errorf("plan %d after property cascade: got %+v", id, got)
+		}
+	}
+
+	admin.do(http.MethodDelete, eventPath+"?cascade=true", nil).expect(http.StatusNoContent)
+	for _, id := range []uint{webID, appID} {
+		got := plan(id)
+		if got.Version != 3 || len(got.Events) != 1 || got.Events[0].Event.Name != "Home" {
+			t.Errorf("plan %d after event cascade: got %+v", id, got)
+		}
+	}
+	admin.do(http.MethodGet, eventPath, nil).expect(http.StatusNotFound)
+	admin.do(http.MethodGet, propertyPath, nil).expect(http.StatusNotFound)
+
+	// The deleted plan kept its members and its version, so it can be
+	// restored in full once the items it uses are back.
+	restorePath := fmt.Sprintf("/api/v1/tracking-plans/%d/restore", oldID)
+	admin.do(http.MethodPost, restorePath, nil).expect(http.StatusConflict)
+	admin.do(http.MethodPost, eventPath+"/restore", nil).expect(http.StatusOK)
+	admin.do(http.MethodPost, propertyPath+"/restore", nil).expect(http.StatusOK)
+	admin.do(http.MethodPost, restorePath, nil).expect(http.StatusOK)
+	got := plan(oldID)
+	if got.Version != 1 || len(got.Events) != 2 || len(got.Events[0].Properties) != 1 || len(got.Events[1].Properties) != 1 {
+		t.Errorf("restored plan: got %+v", got)
+	}
+}
@@ -0,0 +1,68 @@
+package routes
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/shivamrajput1826/api-catalog/common"
+)
+
+func TestRolesGuardRoutes(t *testing.T) {
+	api := newTestAPI(t)
+	admin := api.client("acme", common.RoleAdmin)
+	editor := admin.as(common.RoleEditor)
+	viewer := admin.as(common.RoleViewer)
+
+	forbidden := func(resp *response, role string) {
+		t.Helper()
+		var body struct {
+			MissingPermission string `json:"missing_permission"`
+		}
+		resp.expect(http.StatusForbidden).decode(&body)
+		if body.MissingPermission != role {
+			t.Errorf("%s %s: got missing_permission %q, want %q", resp.method, resp.path, body.MissingPermission, role)
+		}
+	}
+
+	event := map[string]interface{}{"name": "Signed Up", "type": "track"}
+	forbidden(viewer.do(http.MethodPost, "/api/v1/events", event), common.RoleEditor)
+	eventID := editor.do(http.MethodPost, "/api/v1/events", event).expect(http.StatusCreated).id()
+	eventPath := fmt.Sprintf("/api/v1/events/%d", eventID)
+	viewer.do(http.MethodGet, eventPath, nil).expect(http.StatusOK)
+	forbidden(viewer.do(http.MethodPut, eventPath, event), common.RoleEditor)
+
+	planID := editor.do(http.MethodPost, "/api/v1/tracking-plans", map[string]interface{}{
+		"name": "Web", "events": []interface{}{event},
+	}).expect(http.StatusCreated).id()
+	planPath := fmt.Sprintf("/api/v1/tracking-plans/%d", planID)
+
+	// Deletes and restores need the admin role.
+	forbidden(editor.do(http.MethodDelete, planPath, nil), common.RoleAdmin)
+	admin.do(http.MethodDelete, planPath, nil).expect(http.StatusNoContent)
+	forbidden(editor.do(http.MethodPost, planPath+"/restore", nil), common.RoleAdmin)
+	admin.do(http.MethodPost, planPath+"/restore", nil).expect(http.StatusOK)
+
+	forbidden(editor.do(http.MethodDelete, eventPath+"?cascade=true", nil), common.RoleAdmin)
+	admin.do(http.MethodDelete, eventPath+"?cascade=true", nil).expect(http.StatusNoContent)
+	forbidden(editor.do(http.MethodPost, eventPath+"/restore", nil), common.RoleAdmin)
+	admin.do(http.MethodPost, eventPath+"/restore", nil).expect(http.StatusOK)
+
+	propertyPath := fmt.Sprintf("/api/v1/properties/%d", editor.do(http.MethodPost, "/api/v1/properties", map[string]interface{}{
+		"name": "plan", "type": "string",
+	}).expect(http.StatusCreated).id())
+	forbidden(editor.do(http.MethodDelete, propertyPath, nil), common.RoleAdmin)
+	admin.do(http.MethodDelete, propertyPath, nil).expect(http.StatusNoContent)
+	forbidden(editor.do(http.MethodPost, propertyPath+"/restore", nil), common.RoleAdmin)
+	admin.do(http.MethodPost, propertyPath+"/restore", nil).expect(http.StatusOK)
+
+	// Managing clients needs the admin role.
+	forbidden(editor.do(http.MethodGet, "/api/v1/clients", nil), common.RoleAdmin)
+	admin.do(http.MethodGet, "/api/v1/clients", nil).expect(http.StatusOK)
+
+	// Requests without a valid token never reach the role checks.
+	viewer.do(http.MethodGet, "/api/v1/events", nil, "Authorization", "").expect(http.StatusUnauthorized)
+	viewer.do(http.MethodGet, "/api/v1/events", nil, "Authorization", "Bearer not-a-token").expect(http.StatusUnauthorized)
+	// A valid token without a role is refused even for reads.
+	admin.as("").do(http.MethodGet, "/api/v1/events", nil).expect(http.StatusForbidden)
+}
@@ -2,34 +2,84 @@ package routes
 
 import (
 	"github.com/gofiber/fiber/v2"
+	"github.com/shivamrajput1826/api-catalog/common"
+	"github.com/shivamrajput1826/api-catalog/config"
 	"github.com/shivamrajput1826/api-catalog/internal/handlers"
+	"github.com/shivamrajput1826/api-catalog/internal/metrics"
+	"github.com/shivamrajput1826/api-catalog/internal/models"
+	"github.com/shivamrajput1826/api-catalog/middleware"
 )
 
 func Setup(app *fiber.App, h *handlers.Handlers) {
 	app.Get("/health", h.HealthCheck)
+	// Metrics are only served to scrapers holding METRICS_TOKEN.
+	if token := config.GetConfigValue("METRICS_TOKEN"); token != "" {
+		app.Get("/metrics", middleware.RequireBearerToken(token), metrics.Handler())
+	}
 
-	api := app.Group("/api/v1")
+	// Everything under /api/v1 needs a valid token and the credentials of a
+	// registered API client, and works in the workspace of that client. Reads
+	// need the viewer role, writes the editor role, and deletes and restores
+	// of catalog items the admin role. Removing an event or property from a
+	// plan only edits the plan, the same change an editor can make by putting
+	// the plan without it, so it stays an editor write. Managing API clients
+	// also needs the clients scope.
+	api := app.Group("/api/v1", middleware.AuthMiddleware, h.AuthenticateClient, h.ResolveWorkspace)
+	viewer := middleware.RequireRole(common.RoleViewer)
+	editor := middleware.RequireRole(common.RoleEditor)
+	admin := middleware.RequireRole(common.RoleAdmin)
+
+	api.Get("/search", viewer, h.Search)
+	api.Get("/audit", viewer, h.GetAuditLog)
+
+	clients := api.Group("/clients", admin, middleware.RequireScope(models.ScopeClients))
+	clients.Post("/", h.CreateAPIClient)
+	clients.Get("/", h.GetAPIClients)
+	clients.Post("/:id/rotate", h.RotateAPIClientSecret)
+	clients.Post("/:id/revoke", h.RevokeAPIClient)
 
 	events := api.Group("/events")
-	events.Post("/", h.CreateEvent)
-	events.Get("/", h.GetEvents)
-	events.Get("/:id", h.GetEvent)
-	events.Put("/:id", h.UpdateEvent)
-	events.Delete("/:id", h.DeleteEvent)
+	events.Post("/", editor, h.CreateEvent)
+	events.Post("/bulk", editor, h.BulkImportEvents)
+	events.Get("/", viewer, h.GetEvents)
+	events.Get("/:id", viewer, h.GetEvent)
+	events.Get("/:id/usages", viewer, h.GetEventUsages)
+	events.Put("/:id", editor, h.UpdateEvent)
+	events.Patch("/:id", editor, h.PatchEvent)
+	events.Delete("/:id", admin, h.DeleteEvent)
+	events.Post("/:id/restore", admin, h.RestoreEvent)
 
 	properties := api.Group("/properties")
-	properties.Post("/", h.CreateProperty)
-	properties.Get("/", h.GetProperties)
-	properties.Get("/:id", h.GetProperty)
-	properties.Put("/:id", h.UpdateProperty)
-	properties.Delete("/:id", h.DeleteProperty)
+	properties.Post("/", editor, h.CreateProperty)
+	properties.Post("/bulk", editor, h.BulkImportProperties)
+	properties.Get("/", viewer, h.GetProperties)
+	properties.Get("/:id", viewer, h.GetProperty)
+	properties.Get("/:id/usages", viewer, h.GetPropertyUsages)
+	properties.Put("/:id", editor, h.UpdateProperty)
+	properties.Patch("/:id", editor, h.PatchProperty)
+	properties.Delete("/:id", admin, h.DeleteProperty)
+	properties.Post("/:id/restore", admin, h.RestoreProperty)
 
 	trackingPlans := api.Group("/tracking-plans")
-	trackingPlans.Post("/", h.CreateTrackingPlan)
-	trackingPlans.Get("/", h.GetTrackingPlans)
-	trackingPlans.Get("/:id", h.GetTrackingPlan)
-	trackingPlans.Put("/:id", h.UpdateTrackingPlan)
-	trackingPlans.Delete("/:id", h.DeleteTrackingPlan)
+	trackingPlans.Post("/", editor, h.CreateTrackingPlan)
+	trackingPlans.Get("/", viewer, h.GetTrackingPlans)
+	trackingPlans.Post("/import", editor, h.ImportTrackingPlanSchema)
+	trackingPlans.Post("/import/spreadsheet", editor, h.ImportTrackingPlanSpreadsheet)
+	trackingPlans.Get("/diff", viewer, h.CompareTrackingPlans)
+	trackingPlans.Get("/:id", viewer, h.GetTrackingPlan)
+	trackingPlans.Put("/:id", editor, h.UpdateTrackingPlan)
+	trackingPlans.Patch("/:id", editor, h.PatchTrackingPlan)
+	trackingPlans.Delete("/:id", admin, h.DeleteTrackingPlan)
+	trackingPlans.Post("/:id/restore", admin, h.RestoreTrackingPlan)
+	trackingPlans.Post("/:id/events", editor, h.AddTrackingPlanEvent)
+	trackingPlans.Delete("/:id/events/:eventId", editor, h.RemoveTrackingPlanEvent)
+	trackingPlans.Put("/:id/events/:eventId/properties/:propertyId", editor, h.SetTrackingPlanEventProperty)
+	trackingPlans.Delete("/:id/events/:eventId/properties/:propertyId", editor, h.RemoveTrackingPlanEventProperty)
+	trackingPlans.Get("/:id/versions", viewer, h.GetTrackingPlanVersions)
+	trackingPlans.Get("/:id/versions/:n", viewer, h.GetTrackingPlanVersion)
+	trackingPlans.Post("/:id/validate", viewer, h.ValidateTrackingPlanPayload)
+	trackingPlans.Get("/:id/schema", viewer, h.GetTrackingPlanSchema)
+	trackingPlans.Get("/:id/spreadsheet", viewer, h.GetTrackingPlanSpreadsheet)
 
 	app.Use("*", func(c *fiber.Ctx) error {
 		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
@@ -0,0 +1,186 @@
+package routes
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/glebarez/sqlite"
+	"github.com/gofiber/fiber/v2"
+	"github.com/golang-jwt/jwt"
+	"github.com/shivamrajput1826/api-catalog/common"
+	"github.com/shivamrajput1826/api-catalog/internal/handlers"
+	"github.com/shivamrajput1826/api-catalog/internal/models"
+	"github.com/shivamrajput1826/api-catalog/internal/workspace"
+	"github.com/shivamrajput1826/api-catalog/middleware"
+	"github.com/spf13/viper"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+const testJWTSecret = "test-secret"
+
+// testAPI is the full API backed by a private in-memory SQLite database.
+// SQLite ignores the row locks the services take, which these tests do not
+// exercise.
+type testAPI struct {
+	t   *testing.T
+	app *fiber.App
+	db  *gorm.DB
+}
+
+// client is a registered API client, which is also a workspace, acting with
+// the role of its token.
+type client struct {
+	api    *testAPI
+	id     string
+	secret string
+	token  string
+}
+
+func newTestAPI(t *testing.T) *testAPI {
+	t.Helper()
+	dsn := fmt.Sprintf("file:%s?mode=memory&cache=shared", strings.ReplaceAll(t.Name(), "/", "_"))
+	db, err := gorm.Open(sqlite.Open(dsn), &gorm.Config{
+		Logger:         logger.Default.LogMode(logger.Silent),
+		TranslateError: true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sqlDB, err := db.DB()
+	if err != nil {
+		t.Fatal(err)
+	}
+	sqlDB.SetMaxOpenConns(1)
+	t.Cleanup(func() { sqlDB.Close() })
+
+	if err := db.Use(workspace.Plugin{}); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.AutoMigrate(models.GetAllModels()...); err != nil {
+		t.Fatal(err)
+	}
+
+	viper.Set("JWT_SECRET", testJWTSecret)
+	if err := middleware.LoadJWTVerifier(); err != nil {
+		t.Fatal(err)
+	}
+
+	app := fiber.New(fiber.Config{Immutable: true})
+	app.Use(middleware.RequestContextMiddleware)
+	Setup(app, handlers.New(db))
+	return &testAPI{t: t, app: app, db: db}
+}
+
+// client registers an API client with every scope and returns it acting as
+// a user with role.
+func (a *testAPI) client(clientID, role string) *client {
+	a.t.Helper()
+	secret := clientID + "-secret"
+	err := a.db.Create(&models.APIClient{
+		ClientID:   clientID,
+		Name:       clientID,
+		SecretHash: models.HashClientSecret(secret),
+		Scopes:     []string{common.RoleViewer, common.RoleEditor, common.RoleAdmin, models.ScopeClients},
+	}).Error
+	if err != nil {
+		a.t.Fatal(err)
+	}
+
+	c := &client{api: a, id: clientID, secret: secret}
+	return c.as(role)
+}
+
+// as returns the client acting as a user with role.
+func (c *client) as(role string) *client {
+	c.api.t.Helper()
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"user_id": c.id + "-user",
+		"email":   c.id + "@example.com",
+		"role":    role,
+		"exp":     time.Now().Add(time.Hour).Unix(),
+	}).SignedString([]byte(testJWTSecret))
+	if err != nil {
+		c.api.t.Fatal(err)
+	}
+	return &client{api: c.api, id: c.id, secret: c.secret, token: token}
+}
+
+// do sends a request with the client's credentials. body is sent as is when
+// it is a []byte and encoded as JSON otherwise. headers are extra headers as
+// name, value pairs.
+func (c *client) do(method, path string, body interface{}, headers ...string) *response {
+	c.api.t.Helper()
+	var reader io.Reader
+	if raw, ok := body.([]byte); ok {
+		reader = bytes.NewReader(raw)
+	} else if body != nil {
+		encoded, err := json.Marshal(body)
+		if err != nil {
+			c.api.t.Fatal(err)
+		}
+		reader = bytes.NewReader(encoded)
+	}
+	req := httptest.NewRequest(method, path, reader)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+c.token)
+	req.Header.Set("client-id", c.id)
+	req.Header.Set("client-secret", c.secret)
+	for i := 0; i+1 < len(headers); i += 2 {
+		req.Header.Set(headers[i], headers[i+1])
+	}
+
+	resp, err := c.api.app.Test(req, -1)
+	if err != nil {
+		c.api.t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		c.api.t.Fatal(err)
+	}
+	return &response{t: c.api.t, method: method, path: path, status: resp.StatusCode, header: resp.Header, body: data}
+}
+
+type response struct {
+	t      *testing.T
+	method string
+	path   string
+	status int
+	header http.Header
+	body   []byte
+}
+
+// expect fails the test unless the response has the given status.
+func (r *response) expect(status int) *response {
+	r.t.Helper()
+	if r.status != status {
+		r.t.Fatalf("%s %s: got status %d, want %d: %s", r.method, r.path, r.status, status, r.body)
+	}
+	return r
+}
+
+// decode reads the JSON body into v.
+func (r *response) decode(v interface{}) {
+	r.t.Helper()
+	if err := json.Unmarshal(r.body, v); err != nil {
+		r.t.Fatalf("%s %s: %v: %s", r.method, r.path, err, r.body)
+	}
+}
+
+// id returns the id field of a JSON object body.
+func (r *response) id() uint {
+	r.t.Helper()
+	var row struct {
+		ID uint `json:"id"`
+	}
+	r.decode(&row)
+	return row.ID
+}
@@ -0,0 +1,112 @@
+package routes
+
+import (
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/shivamrajput1826/api-catalog/common"
+)
+
+func TestImportTrackingPlanSchemaRejectsInvalidEventType(t *testing.T) {
+	api := newTestAPI(t)
+	editor := api.client("acme", common.RoleEditor)
+
+	schema := func(eventType string) map[string]interface{} {
+		return map[string]interface{}{
+			"title": "Signed Up",
+			"type":  "object",
+			"properties": map[string]interface{}{
+				"type":  map[string]interface{}{"const": eventType},
+				"event": map[string]interface{}{"const": "Signed Up"},
+			},
+		}
+	}
+
+	resp := editor.do(http.MethodPost, "/api/v1/tracking-plans/import", map[string]interface{}{
+		"name":    "Web",
+		"schemas": []interface{}{schema("bogus")},
+	}).expect(http.StatusBadRequest)
+	if !strings.Contains(string(resp.body), "event[0].type 'bogus'") {
+		t.Errorf("unexpected error: %s", resp.body)
+	}
+	if n := editor.count("events"); n != 0 {
+		t.Fatalf("got %d events after a rejected import, want 0", n)
+	}
+	if n := editor.count("tracking-plans"); n != 0 {
+		t.Fatalf("got %d tracking plans after a rejected import, want 0", n)
+	}
+
+	var plan struct {
+		Events []struct {
+			Event struct {
+				Name string `json:"name"`
+				Type string `json:"type"`
+			} `json:"event"`
+		} `json:"events"`
+	}
+	editor.do(http.MethodPost, "/api/v1/tracking-plans/import", map[string]interface{}{
+		"name":    "Web",
+		"schemas": []interface{}{schema("track")},
+	}).expect(http.StatusCreated).decode(&plan)
+	if len(plan.Events) != 1 || plan.Events[0].Event.Name != "Signed Up" || plan.Events[0].Event.Type != "track" {
+		t.Errorf("unexpected imported plan: %+v", plan)
+	}
+}
+
+// Updating a plan, directly or by importing over it, keeps the type each
+// event was given instead of treating every event as a track call.
+func TestUpdateTrackingPlanKeepsEventTypes(t *testing.T) {
+	api := newTestAPI(t)
+	editor := api.client("acme", common.RoleEditor)
+
+	type plan struct {
+		ID     uint `json:"id"`
+		Events []struct {
+			Event struct {
+				Name string `json:"name"`
+				Type string `json:"type"`
+			} `json:"event"`
+		} `json:"events"`
+	}
+	types := func(p plan) map[string]string {
+		types := make(map[string]string)
+		for _, event := range p.Events {
+			types[event.Event.Name] = event.Event.Type
+		}
+		return types
+	}
+
+	var created plan
+	editor.do(http.MethodPost, "/api/v1/tracking-plans", map[string]interface{}{
+		"name": "Web", "events": []map[string]interface{}{{"name": "Signed Up", "type": "track"}},
+	}).expect(http.StatusCreated).decode(&created)
+
+	var updated plan
+	editor.do(http.MethodPut, fmt.Sprintf("/api/v1/tracking-plans/%d", created.ID), map[string]interface{}{
+		"name": "Web",
+		"events": []map[string]interface{}{
+			{"name": "Signed Up", "type": "track"},
+			{"name": "Home", "type": "page"},
+			{"name": "User", "type": "identify"},
+		},
+	}).expect(http.StatusOK).decode(&updated)
+	want := map[string]string{"Signed Up": "track", "Home": "page", "User": "identify"}
+	if got := types(updated); !reflect.DeepEqual(got, want) {
+		t.Errorf("PUT: got event types %v, want %v", got, want)
+	}
+
+	var imported plan
+	editor.do(http.MethodPost, "/api/v1/tracking-plans/import", map[string]interface{}{
+		"name": "Web",
+		"schemas": []interface{}{map[string]interface{}{
+			"title":      "Settings",
+			"properties": map[string]interface{}{"type": map[string]interface{}{"const": "screen"}},
+		}},
+	}).expect(http.StatusOK).decode(&imported)
+	if got := types(imported); imported.ID != created.ID || !reflect.DeepEqual(got, map[string]string{"Settings": "screen"}) {
+		t.Errorf("import over plan %d: got plan %d with event types %v", created.ID, imported.ID, got)
+	}
+}
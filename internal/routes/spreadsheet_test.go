@@ -0,0 +1,60 @@
+package routes
+
+import (
+	"fmt"
+	"net/http"
+	"reflect"
+	"testing"
+
+	"github.com/shivamrajput1826/api-catalog/common"
+)
+
+func TestSpreadsheetImportKeepsConstraintsWithoutConstraintsColumn(t *testing.T) {
+	api := newTestAPI(t)
+	editor := api.client("acme", common.RoleEditor)
+
+	planID := editor.do(http.MethodPost, "/api/v1/tracking-plans", map[string]interface{}{
+		"name": "Web",
+		"events": []map[string]interface{}{{
+			"name": "Signed Up", "type": "track",
+			"properties": []map[string]interface{}{{"name": "plan", "type": "string", "enum": []string{"free", "pro"}}},
+		}},
+	}).expect(http.StatusCreated).id()
+
+	constraints := func() map[string]interface{} {
+		var plan struct {
+			Events []struct {
+				Properties []map[string]interface{} `json:"properties"`
+			} `json:"events"`
+		}
+		editor.do(http.MethodGet, fmt.Sprintf("/api/v1/tracking-plans/%d", planID), nil).expect(http.StatusOK).decode(&plan)
+		planProp := plan.Events[0].Properties[0]
+		return map[string]interface{}{"enum": planProp["enum"], "required": planProp["required"]}
+	}
+	importCSV := func(csv string) {
+		editor.do(http.MethodPost, "/api/v1/tracking-plans/import/spreadsheet?name=Web", []byte(csv),
+			"Content-Type", "text/csv").expect(http.StatusOK)
+	}
+
+	// A sheet from before the Constraints column keeps the enum.
+	importCSV("Event Name,Property Name,Property Type,Required\nSigned Up,plan,string,yes\n")
+	want := map[string]interface{}{"enum": []interface{}{"free", "pro"}, "required": true}
+	if got := constraints(); !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+
+	// An exported sheet carries the constraints back.
+	export := editor.do(http.MethodGet, fmt.Sprintf("/api/v1/tracking-plans/%d/spreadsheet?format=csv", planID), nil).
+		expect(http.StatusOK)
+	importCSV(string(export.body))
+	if got := constraints(); !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %v after a round trip, want %v", got, want)
+	}
+
+	// A blank Constraints cell clears them.
+	importCSV("Event Name,Property Name,Property Type,Required,Constraints\nSigned Up,plan,string,yes,\n")
+	want = map[string]interface{}{"enum": nil, "required": true}
+	if got := constraints(); !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+}
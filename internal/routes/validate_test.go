@@ -0,0 +1,62 @@
+package routes
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/shivamrajput1826/api-catalog/common"
+	"github.com/shivamrajput1826/api-catalog/internal/dtos"
+	"github.com/shivamrajput1826/api-catalog/internal/validation"
+)
+
+func TestValidateTrackingPlanPayload(t *testing.T) {
+	api := newTestAPI(t)
+	editor := api.client("acme", common.RoleEditor)
+
+	planID := editor.do(http.MethodPost, "/api/v1/tracking-plans", map[string]interface{}{
+		"name": "Web",
+		"events": []map[string]interface{}{
+			{
+				"name": "Signed Up", "type": "track",
+				"properties": []map[string]interface{}{{"name": "plan", "type": "string", "required": true}},
+			},
+			{
+				"name": "Home", "type": "page",
+				"properties": []map[string]interface{}{{"name": "path", "type": "string", "required": true}},
+			},
+		},
+	}).expect(http.StatusCreated).id()
+	path := fmt.Sprintf("/api/v1/tracking-plans/%d/validate", planID)
+
+	tests := []struct {
+		name  string
+		body  map[string]interface{}
+		event string
+		codes []string
+	}{
+		{"valid track", map[string]interface{}{"type": "track", "event": "Signed Up", "properties": map[string]interface{}{"plan": "pro"}}, "Signed Up", nil},
+		{"invalid track", map[string]interface{}{"type": "track", "event": "Signed Up", "properties": map[string]interface{}{"plan": 1}}, "Signed Up", []string{validation.ViolationInvalidType}},
+		{"page by name", map[string]interface{}{"type": "page", "name": "Home"}, "Home", []string{validation.ViolationMissingProperty}},
+		{"unknown event", map[string]interface{}{"type": "track", "event": "Logged In"}, "Logged In", []string{validation.ViolationUnknownEvent}},
+		{"type must match", map[string]interface{}{"type": "screen", "name": "Home"}, "Home", []string{validation.ViolationUnknownEvent}},
+	}
+	for _, tt := range tests {
+		var result dtos.ValidatePayloadResponse
+		editor.do(http.MethodPost, path, tt.body).expect(http.StatusOK).decode(&result)
+		if result.Valid != (len(tt.codes) == 0) || result.Event != tt.event || len(result.Violations) != len(tt.codes) {
+			t.Errorf("%s: got %+v", tt.name, result)
+			continue
+		}
+		for i, code := range tt.codes {
+			if result.Violations[i].Code != code {
+				t.Errorf("%s: got violation %+v, want code %s", tt.name, result.Violations[i], code)
+			}
+		}
+	}
+
+	editor.do(http.MethodPost, path, map[string]interface{}{"type": "track"}).expect(http.StatusBadRequest)
+	editor.do(http.MethodPost, "/api/v1/tracking-plans/999/validate", map[string]interface{}{
+		"type": "track", "event": "Signed Up",
+	}).expect(http.StatusNotFound)
+}
@@ -0,0 +1,111 @@
+package routes
+
+import (
+	"fmt"
+	"net/http"
+	"reflect"
+	"sync"
+	"testing"
+
+	"github.com/shivamrajput1826/api-catalog/common"
+	"github.com/shivamrajput1826/api-catalog/internal/dtos"
+	"github.com/shivamrajput1826/api-catalog/internal/services"
+)
+
+func TestTrackingPlanVersions(t *testing.T) {
+	api := newTestAPI(t)
+	editor := api.client("acme", common.RoleEditor)
+
+	home := map[string]interface{}{"name": "Home", "type": "page"}
+	planID := editor.do(http.MethodPost, "/api/v1/tracking-plans", map[string]interface{}{
+		"name": "Web", "events": []interface{}{home},
+	}).expect(http.StatusCreated).id()
+	planPath := fmt.Sprintf("/api/v1/tracking-plans/%d", planID)
+
+	// Each kind of change records the next version.
+	editor.do(http.MethodPut, planPath, map[string]interface{}{
+		"name": "Web", "description": "Website", "events": []interface{}{home},
+	}).expect(http.StatusOK)
+	editor.do(http.MethodPost, planPath+"/events", map[string]interface{}{
+		"name": "Signed Up", "type": "track",
+	}).expect(http.StatusCreated)
+	editor.do(http.MethodPatch, planPath, []byte(`{"name":"Website"}`),
+		"Content-Type", services.MergePatchContentType).expect(http.StatusOK)
+
+	// Concurrent updates take turns and each gets a version of its own.
+	const concurrent = 5
+	statuses := make([]int, concurrent)
+	var wg sync.WaitGroup
+	for i := 0; i < concurrent; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			statuses[i] = editor.do(http.MethodPut, planPath, map[string]interface{}{
+				"name": "Website", "description": fmt.Sprintf("Update %d", i), "events": []interface{}{home},
+			}).status
+		}(i)
+	}
+	wg.Wait()
+	for i, status := range statuses {
+		if status != http.StatusOK {
+			t.Fatalf("concurrent update %d: got status %d", i, status)
+		}
+	}
+
+	var summaries []dtos.TrackingPlanVersionSummary
+	editor.do(http.MethodGet, planPath+"/versions", nil).expect(http.StatusOK).decode(&summaries)
+	const latest = 4 + concurrent
+	got := make([]int, 0, len(summaries))
+	for _, summary := range summaries {
+		got = append(got, summary.Version)
+	}
+	want := make([]int, 0, latest)
+	for version := latest; version >= 1; version-- {
+		want = append(want, version)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("got versions %v, want %v", got, want)
+	}
+
+	type snapshot struct {
+		Version  int `json:"version"`
+		Snapshot struct {
+			Name        string `json:"name"`
+			Description string `json:"description"`
+			Version     int    `json:"version"`
+			Events      []struct {
+				Event struct {
+					Name string `json:"name"`
+				} `json:"event"`
+			} `json:"events"`
+		} `json:"snapshot"`
+	}
+	versions := []struct {
+		version     int
+		name        string
+		description string
+		events      int
+	}{
+		{1, "Web", "", 1},
+		{2, "Web", "Website", 1},
+		{3, "Web", "Website", 2},
+		{4, "Website", "Website", 2},
+	}
+	for _, v := range versions {
+		var got snapshot
+		editor.do(http.MethodGet, fmt.Sprintf("%s/versions/%d", planPath, v.version), nil).expect(http.StatusOK).decode(&got)
+		if got.Version != v.version || got.Snapshot.Version != v.version || got.Snapshot.Name != v.name ||
+			got.Snapshot.Description != v.description || len(got.Snapshot.Events) != v.events {
+			t.Errorf("version %d: got %+v", v.version, got)
+		}
+	}
+
+	var current struct {
+		Version int `json:"version"`
+	}
+	editor.do(http.MethodGet, planPath, nil).expect(http.StatusOK).decode(&current)
+	if current.Version != latest {
+		t.Errorf("got plan version %d, want %d", current.Version, latest)
+	}
+	editor.do(http.MethodGet, fmt.Sprintf("%s/versions/%d", planPath, latest+1), nil).expect(http.StatusNotFound)
+}
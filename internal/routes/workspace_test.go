@@ -0,0 +1,127 @@
+package routes
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/shivamrajput1826/api-catalog/common"
+)
+
+// acmeCatalog creates an event and a property in the workspace of client,
+// and a plan using both.
+func acmeCatalog(client *client) (eventID, propertyID, planID uint) {
+	eventID = client.do(http.MethodPost, "/api/v1/events", map[string]interface{}{
+		"name": "Signed Up", "type": "track",
+	}).expect(http.StatusCreated).id()
+	propertyID = client.do(http.MethodPost, "/api/v1/properties", map[string]interface{}{
+		"name": "plan", "type": "string",
+	}).expect(http.StatusCreated).id()
+	planID = client.do(http.MethodPost, "/api/v1/tracking-plans", map[string]interface{}{
+		"name": "Web",
+		"events": []map[string]interface{}{{
+			"name": "Signed Up", "type": "track",
+			"properties": []map[string]interface{}{{"name": "plan", "type": "string"}},
+		}},
+	}).expect(http.StatusCreated).id()
+	return eventID, propertyID, planID
+}
+
+func TestOtherWorkspacesGetNotFound(t *testing.T) {
+	api := newTestAPI(t)
+	acme := api.client("acme", common.RoleAdmin)
+	globex := api.client("globex", common.RoleAdmin)
+	eventID, propertyID, planID := acmeCatalog(acme)
+
+	event := fmt.Sprintf("/api/v1/events/%d", eventID)
+	property := fmt.Sprintf("/api/v1/properties/%d", propertyID)
+	plan := fmt.Sprintf("/api/v1/tracking-plans/%d", planID)
+	planEvent := fmt.Sprintf("%s/events/%d", plan, eventID)
+	planEventProperty := fmt.Sprintf("%s/properties/%d", planEvent, propertyID)
+	mergePatch := []string{"Content-Type", "application/merge-patch+json"}
+
+	requests := []struct {
+		method  string
+		path    string
+		body    interface{}
+		headers []string
+	}{
+		{http.MethodGet, event, nil, nil},
+		{http.MethodGet, event + "/usages", nil, nil},
+		{http.MethodPut, event, map[string]interface{}{"name": "Signed Up", "type": "track", "description": "x"}, nil},
+		{http.MethodPatch, event, map[string]interface{}{"description": "x"}, mergePatch},
+		{http.MethodDelete, event + "?cascade=true", nil, nil},
+		{http.MethodPost, event + "/restore", nil, nil},
+
+		{http.MethodGet, property, nil, nil},
+		{http.MethodGet, property + "/usages", nil, nil},
+		{http.MethodPut, property, map[string]interface{}{"name": "plan", "type": "string", "description": "x"}, nil},
+		{http.MethodPatch, property, map[string]interface{}{"description": "x"}, mergePatch},
+		{http.MethodDelete, property + "?cascade=true", nil, nil},
+		{http.MethodPost, property + "/restore", nil, nil},
+
+		{http.MethodGet, plan, nil, nil},
+		{http.MethodPut, plan, map[string]interface{}{"name": "Web", "events": []map[string]interface{}{{"name": "Logged In", "type": "track"}}}, nil},
+		{http.MethodPatch, plan, map[string]interface{}{"description": "x"}, mergePatch},
+		{http.MethodDelete, plan, nil, nil},
+		{http.MethodPost, plan + "/restore", nil, nil},
+		{http.MethodPost, plan + "/events", map[string]interface{}{"name": "Logged In", "type": "track"}, nil},
+		{http.MethodDelete, planEvent, nil, nil},
+		{http.MethodPut, planEventProperty, map[string]interface{}{"required": true}, nil},
+		{http.MethodDelete, planEventProperty, nil, nil},
+		{http.MethodGet, plan + "/versions", nil, nil},
+		{http.MethodGet, plan + "/versions/1", nil, nil},
+		{http.MethodPost, plan + "/validate", map[string]interface{}{"type": "track", "event": "Signed Up"}, nil},
+		{http.MethodGet, plan + "/schema", nil, nil},
+		{http.MethodGet, plan + "/spreadsheet", nil, nil},
+		{http.MethodGet, fmt.Sprintf("/api/v1/tracking-plans/diff?base=%d&head=%d", planID, planID), nil, nil},
+	}
+	for _, req := range requests {
+		globex.do(req.method, req.path, req.body, req.headers...).expect(http.StatusNotFound)
+	}
+
+	// Lists and search only show the caller's own catalog.
+	for _, path := range []string{"/api/v1/events", "/api/v1/properties", "/api/v1/tracking-plans", "/api/v1/audit"} {
+		var page struct {
+			Total int64 `json:"total"`
+		}
+		globex.do(http.MethodGet, path, nil).expect(http.StatusOK).decode(&page)
+		if page.Total != 0 {
+			t.Errorf("%s: globex sees %d of acme's rows", path, page.Total)
+		}
+	}
+
+	// Nothing of acme's catalog changed.
+	var current struct {
+		Version int `json:"version"`
+		Events  []struct {
+			Properties []struct {
+				Required bool `json:"required"`
+			} `json:"properties"`
+		} `json:"events"`
+	}
+	acme.do(http.MethodGet, plan, nil).expect(http.StatusOK).decode(&current)
+	if current.Version != 1 || len(current.Events) != 1 || len(current.Events[0].Properties) != 1 || current.Events[0].Properties[0].Required {
+		t.Errorf("acme's plan changed: %+v", current)
+	}
+	for _, path := range []string{event, property} {
+		var row struct {
+			Version     int    `json:"version"`
+			Description string `json:"description"`
+		}
+		acme.do(http.MethodGet, path, nil).expect(http.StatusOK).decode(&row)
+		if row.Version != 1 || row.Description != "" {
+			t.Errorf("%s changed: %+v", path, row)
+		}
+	}
+}
+
+func TestSameNamesInSeparateWorkspaces(t *testing.T) {
+	api := newTestAPI(t)
+	acmeEvent, _, acmePlan := acmeCatalog(api.client("acme", common.RoleAdmin))
+	globexEvent, _, globexPlan := acmeCatalog(api.client("globex", common.RoleAdmin))
+
+	if acmeEvent == globexEvent || acmePlan == globexPlan {
+		t.Errorf("workspaces share rows: events %d and %d, plans %d and %d", acmeEvent, globexEvent, acmePlan, globexPlan)
+	}
+}
@@ -0,0 +1,157 @@
+package services
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/shivamrajput1826/api-catalog/internal/dtos"
+	"github.com/shivamrajput1826/api-catalog/internal/models"
+	"github.com/shivamrajput1826/api-catalog/internal/validation"
+	"gorm.io/gorm"
+)
+
+// lastUsedResolution limits how often authenticating a client writes its
+// last-used time.
+const lastUsedResolution = int64(time.Minute / time.Second)
+
+var ErrInvalidClient = errors.New("invalid client credentials")
+
+type APIClientService struct {
+	clientRepo models.APIClientRepository
+	validator  *validation.Validator
+}
+
+func NewAPIClientService(clientRepo models.APIClientRepository, validator *validation.Validator) *APIClientService {
+	return &APIClientService{
+		clientRepo: clientRepo,
+		validator:  validator,
+	}
+}
+
+func (s *APIClientService) CreateClient(req *dtos.CreateAPIClientRequest) (*dtos.APIClientCredentials, error) {
+	if err := s.validator.ValidateCreateAPIClient(req); err != nil {
+		return nil, err
+	}
+
+	if _, err := s.clientRepo.GetByClientID(req.ClientID); err == nil {
+		return nil, fiber.NewError(fiber.StatusConflict, "Another client with the same client_id exists")
+	} else if err != gorm.ErrRecordNotFound {
+		return nil, fiber.NewError(fiber.StatusInternalServerError, "Failed to fetch client")
+	}
+
+	secret, err := newClientSecret()
+	if err != nil {
+		return nil, fiber.NewError(fiber.StatusInternalServerError, "Failed to generate client secret")
+	}
+
+	client := &models.APIClient{
+		ClientID:   req.ClientID,
+		Name:       req.Name,
+		SecretHash: models.HashClientSecret(secret),
+		Scopes:     req.Scopes,
+		ExpiresAt:  req.ExpiresAt,
+	}
+	if err := s.clientRepo.Create(client); err != nil {
+		// A concurrent request registered the same client_id after the check.
+		if errors.Is(err, gorm.ErrDuplicatedKey) {
+			return nil, fiber.NewError(fiber.StatusConflict, "Another client with the same client_id exists")
+		}
+		return nil, fiber.NewError(fiber.StatusInternalServerError, "Failed to create client")
+	}
+	return &dtos.APIClientCredentials{APIClient: *client, Secret: secret}, nil
+}
+
+func (s *APIClientService) GetAllClients() ([]models.APIClient, error) {
+	clients, err := s.clientRepo.GetAll()
+	if err != nil {
+		return nil, fiber.NewError(fiber.StatusInternalServerError, "Failed to fetch clients")
+	}
+	return clients, nil
+}
+
+func (s *APIClientService) GetClientByID(id uint) (*models.APIClient, error) {
+	client, err := s.clientRepo.GetByID(id)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fiber.NewError(fiber.StatusNotFound, "Client not found")
+		}
+		return nil, fiber.NewError(fiber.StatusInternalServerError, "Failed to fetch client")
+	}
+	return client, nil
+}
+
+// RotateClientSecret replaces the secret of a client. The old secret stops
+// working immediately.
+func (s *APIClientService) RotateClientSecret(id uint) (*dtos.APIClientCredentials, error) {
+	client, err := s.GetClientByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if client.RevokedAt != 0 {
+		return nil, fiber.NewError(fiber.StatusConflict, "Client is revoked")
+	}
+
+	secret, err := newClientSecret()
+	if err != nil {
+		return nil, fiber.NewError(fiber.StatusInternalServerError, "Failed to generate client secret")
+	}
+	client.SecretHash = models.HashClientSecret(secret)
+	if err := s.clientRepo.UpdateSecret(client.ID, client.SecretHash); err != nil {
+		return nil, fiber.NewError(fiber.StatusInternalServerError, "Failed to rotate client secret")
+	}
+	return &dtos.APIClientCredentials{APIClient: *client, Secret: secret}, nil
+}
+
+// RevokeClient permanently disables a client. Revoking it again is a no-op.
+func (s *APIClientService) RevokeClient(id uint) (*models.APIClient, error) {
+	client, err := s.GetClientByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if client.RevokedAt != 0 {
+		return client, nil
+	}
+
+	client.RevokedAt = time.Now().Unix()
+	if err := s.clientRepo.Revoke(client.ID, client.RevokedAt); err != nil {
+		return nil, fiber.NewError(fiber.StatusInternalServerError, "Failed to revoke client")
+	}
+	return client, nil
+}
+
+// AuthenticateClient checks a client id and secret and returns the client
+// when it is active. Unknown clients and wrong secrets both give
+// ErrInvalidClient so callers cannot tell them apart.
+func (s *APIClientService) AuthenticateClient(clientID, secret string) (*models.APIClient, error) {
+	client, err := s.clientRepo.GetByClientID(clientID)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, ErrInvalidClient
+		}
+		return nil, err
+	}
+
+	now := time.Now().Unix()
+	if !client.CheckSecret(secret) || !client.Active(now) {
+		return nil, ErrInvalidClient
+	}
+
+	if now-client.LastUsedAt >= lastUsedResolution {
+		client.LastUsedAt = now
+		if err := s.clientRepo.Touch(client.ID, now); err != nil {
+			return nil, err
+		}
+	}
+	return client, nil
+}
+
+func newClientSecret() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(buf), nil
+}
@@ -0,0 +1,37 @@
+package services
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/shivamrajput1826/api-catalog/internal/dtos"
+	"github.com/shivamrajput1826/api-catalog/internal/models"
+	"github.com/shivamrajput1826/api-catalog/internal/validation"
+	"gorm.io/gorm"
+)
+
+// racingClientRepository finds no client, like a request that checks just
+// before a concurrent one creates the same client id, and then fails the
+// create on the unique index.
+type racingClientRepository struct {
+	models.APIClientRepository
+}
+
+func (racingClientRepository) GetByClientID(string) (*models.APIClient, error) {
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (racingClientRepository) Create(*models.APIClient) error {
+	return gorm.ErrDuplicatedKey
+}
+
+func TestCreateClientMapsDuplicateKeyToConflict(t *testing.T) {
+	service := NewAPIClientService(racingClientRepository{}, validation.New())
+	_, err := service.CreateClient(&dtos.CreateAPIClientRequest{ClientID: "acme", Scopes: []string{"viewer"}})
+
+	var fiberErr *fiber.Error
+	if !errors.As(err, &fiberErr) || fiberErr.Code != fiber.StatusConflict {
+		t.Fatalf("got %v, want a 409", err)
+	}
+}
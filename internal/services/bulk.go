@@ -0,0 +1,461 @@
+package services
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/shivamrajput1826/api-catalog/internal/dtos"
+	"github.com/shivamrajput1826/api-catalog/internal/models"
+	"gorm.io/gorm"
+)
+
+const (
+	NDJSONContentType = "application/x-ndjson"
+	CSVContentType    = "text/csv"
+
+	// BulkModeAtomic writes every row in one transaction, and nothing at all
+	// if any row fails. BulkModeBestEffort writes each valid row on its own.
+	BulkModeAtomic     = "atomic"
+	BulkModeBestEffort = "best_effort"
+
+	BulkStatusCreated  = "created"
+	BulkStatusExisting = "existing"
+	BulkStatusFailed   = "failed"
+	// BulkStatusSkipped marks rows that were not written because an atomic
+	// import was abandoned.
+	BulkStatusSkipped = "skipped"
+
+	maxBulkRows = 5000
+)
+
+// csvColumn says how a CSV cell is turned into the JSON value of a field.
+type csvColumn int
+
+const (
+	csvString csvColumn = iota
+	csvNumber
+	csvJSON
+	// csvType cells hold a type name, a union such as "string|null", or a
+	// JSON array of type names.
+	csvType
+)
+
+var eventCSVColumns = map[string]csvColumn{
+	"name":        csvString,
+	"type":        csvString,
+	"description": csvString,
+}
+
+var propertyCSVColumns = map[string]csvColumn{
+	"name":        csvString,
+	"type":        csvType,
+	"items":       csvType,
+	"description": csvString,
+	"enum":        csvJSON,
+	"pattern":     csvString,
+	"minimum":     csvNumber,
+	"maximum":     csvNumber,
+	"minLength":   csvNumber,
+	"maxLength":   csvNumber,
+	"format":      csvString,
+	"properties":  csvJSON,
+}
+
+// bulkRow is one entry of an upload in its JSON form, or the reason it could
+// not be read.
+type bulkRow struct {
+	Line int
+	Data json.RawMessage
+	Err  error
+}
+
+// BulkImportEvents creates the events listed in an NDJSON or CSV upload. Rows
+// matching an event that already exists are reported as existing.
+func (s *EventService) BulkImportEvents(body []byte, contentType, mode string) (*dtos.BulkImportResponse, error) {
+	if err := validateBulkMode(mode); err != nil {
+		return nil, err
+	}
+	rows, err := parseBulkRows(body, contentType, eventCSVColumns)
+	if err != nil {
+		return nil, err
+	}
+
+	return runBulkImport(s.txManager, mode, rows,
+		s.validator.ValidateCreateEvent,
+		func(req *dtos.CreateEventRequest) string { return req.Name },
+		s.saveBulkEvent,
+	)
+}
+
+// BulkImportProperties creates the properties listed in an NDJSON or CSV
+// upload. Rows matching a property that already exists are reported as
+// existing.
+func (s *PropertyService) BulkImportProperties(body []byte, contentType, mode string) (*dtos.BulkImportResponse, error) {
+	if err := validateBulkMode(mode); err != nil {
+		return nil, err
+	}
+	rows, err := parseBulkRows(body, contentType, propertyCSVColumns)
+	if err != nil {
+		return nil, err
+	}
+
+	return runBulkImport(s.txManager, mode, rows,
+		s.validator.ValidateCreateProperty,
+		func(req *dtos.CreatePropertyRequest) string { return req.Name },
+		s.saveBulkProperty,
+	)
+}
+
+func (s *EventService) saveBulkEvent(tx *gorm.DB, req *dtos.CreateEventRequest) (uint, bool, error) {
+	var event models.Event
+	err := tx.Where("name = ? AND type = ?", req.Name, req.Type).First(&event).Error
+	if err == nil {
+		return event.ID, false, nil
+	}
+	if err != gorm.ErrRecordNotFound {
+		return 0, false, fiber.NewError(fiber.StatusInternalServerError, "Failed to query event")
+	}
+
+	event = models.Event{
+		Name:        req.Name,
+		Type:        req.Type,
+		Description: req.Description,
+	}
+	if err := tx.Create(&event).Error; err != nil {
+		return 0, false, fiber.NewError(fiber.StatusInternalServerError, "Failed to create event")
+	}
+	if err := s.auditor.Record(tx, models.AuditResourceEvent, event.ID, models.AuditActionCreate, nil, &event); err != nil {
+		return 0, false, err
+	}
+	return event.ID, true, nil
+}
+
+func (s *PropertyService) saveBulkProperty(tx *gorm.DB, req *dtos.CreatePropertyRequest) (uint, bool, error) {
+	var property models.Property
+	err := tx.Where("name = ? AND type = ? AND items = ?", req.Name, req.Type, req.Items).First(&property).Error
+	if err == nil {
+		return property.ID, false, nil
+	}
+	if err != gorm.ErrRecordNotFound {
+		return 0, false, fiber.NewError(fiber.StatusInternalServerError, "Failed to query property")
+	}
+
+	property = models.Property{
+		Name:                req.Name,
+		Type:                req.Type,
+		Items:               req.Items,
+		Description:         req.Description,
+		PropertyConstraints: req.PropertyConstraints,
+		Properties:          req.Properties,
+	}
+	if err := tx.Create(&property).Error; err != nil {
+		return 0, false, fiber.NewError(fiber.StatusInternalServerError, "Failed to create property")
+	}
+	if err := s.auditor.Record(tx, models.AuditResourceProperty, property.ID, models.AuditActionCreate, nil, &property); err != nil {
+		return 0, false, err
+	}
+	return property.ID, true, nil
+}
+
+func validateBulkMode(mode string) error {
+	switch mode {
+	case "", BulkModeAtomic, BulkModeBestEffort:
+		return nil
+	}
+	return fiber.NewError(fiber.StatusBadRequest,
+		fmt.Sprintf("invalid mode '%s'. Must be one of: %s, %s", mode, BulkModeAtomic, BulkModeBestEffort))
+}
+
+// runBulkImport decodes and validates every row, then saves the valid ones.
+// In atomic mode a single invalid or failing row stops the whole import and
+// nothing is written; in best-effort mode every row is saved in its own
+// transaction and failures only affect their own row.
+func runBulkImport[T any](
+	txManager models.TransactionManager,
+	mode string,
+	rows []bulkRow,
+	validate func(req *T) error,
+	name func(req *T) string,
+	save func(tx *gorm.DB, req *T) (uint, bool, error),
+) (*dtos.BulkImportResponse, error) {
+	if mode == "" {
+		mode = BulkModeAtomic
+	}
+
+	results := make([]dtos.BulkImportResult, len(rows))
+	requests := make([]*T, len(rows))
+	invalid := false
+	for i, row := range rows {
+		results[i].Line = row.Line
+		if row.Err != nil {
+			results[i].Status, results[i].Error = BulkStatusFailed, row.Err.Error()
+			invalid = true
+			continue
+		}
+
+		req := new(T)
+		if err := json.Unmarshal(row.Data, req); err != nil {
+			results[i].Status, results[i].Error = BulkStatusFailed, "invalid row: "+err.Error()
+			invalid = true
+			continue
+		}
+		results[i].Name = name(req)
+		if err := validate(req); err != nil {
+			results[i].Status, results[i].Error = BulkStatusFailed, bulkErrorMessage(err)
+			invalid = true
+			continue
+		}
+		requests[i] = req
+	}
+
+	if mode == BulkModeBestEffort {
+		for i, req := range requests {
+			if req == nil {
+				continue
+			}
+			saveBulkRow(&results[i], txManager.BeginTransaction(), req, save, true)
+		}
+		return summarizeBulkImport(mode, true, results), nil
+	}
+
+	if invalid {
+		skipPendingRows(results)
+		return summarizeBulkImport(mode, false, results), nil
+	}
+
+	tx := txManager.BeginTransaction()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+	for i, req := range requests {
+		if !saveBulkRow(&results[i], tx, req, save, false) {
+			tx.Rollback()
+			skipPendingRows(results)
+			return summarizeBulkImport(mode, false, results), nil
+		}
+	}
+	if err := tx.Commit().Error; err != nil {
+		return nil, fiber.NewError(fiber.StatusInternalServerError, "Failed to commit transaction")
+	}
+	return summarizeBulkImport(mode, true, results), nil
+}
+
+// saveBulkRow saves one request and records the outcome in result. With
+// commit set, tx belongs to this row alone and is committed or rolled back
+// here.
+func saveBulkRow[T any](result *dtos.BulkImportResult, tx *gorm.DB, req *T, save func(tx *gorm.DB, req *T) (uint, bool, error), commit bool) bool {
+	id, created, err := save(tx, req)
+	if err == nil && commit {
+		err = tx.Commit().Error
+	}
+	if err != nil {
+		if commit {
+			tx.Rollback()
+		}
+		result.Status, result.Error = BulkStatusFailed, bulkErrorMessage(err)
+		return false
+	}
+
+	result.ID = id
+	result.Status = BulkStatusExisting
+	if created {
+		result.Status = BulkStatusCreated
+	}
+	return true
+}
+
+// skipPendingRows marks every row that was going to be, or was, written by an
+// abandoned atomic import as skipped.
+func skipPendingRows(results []dtos.BulkImportResult) {
+	for i := range results {
+		switch results[i].Status {
+		case "", BulkStatusCreated:
+			results[i].Status = BulkStatusSkipped
+			results[i].ID = 0
+		}
+	}
+}
+
+func summarizeBulkImport(mode string, committed bool, results []dtos.BulkImportResult) *dtos.BulkImportResponse {
+	response := &dtos.BulkImportResponse{Mode: mode, Committed: committed, Results: results}
+	for _, result := range results {
+		switch result.Status {
+		case BulkStatusCreated:
+			response.Created++
+		case BulkStatusExisting:
+			response.Existing++
+		case BulkStatusFailed:
+			response.Failed++
+		case BulkStatusSkipped:
+			response.Skipped++
+		}
+	}
+	return response
+}
+
+func bulkErrorMessage(err error) string {
+	var fiberErr *fiber.Error
+	if errors.As(err, &fiberErr) {
+		return fiberErr.Message
+	}
+	return err.Error()
+}
+
+// parseBulkRows splits an upload into rows. Problems with a single row are
+// recorded on that row; problems with the upload as a whole are returned.
+func parseBulkRows(body []byte, contentType string, columns map[string]csvColumn) ([]bulkRow, error) {
+	var rows []bulkRow
+	var err error
+	switch contentType {
+	case NDJSONContentType, "application/ndjson", "application/jsonl":
+		rows, err = parseNDJSONRows(body)
+	case CSVContentType:
+		rows, err = parseCSVRows(body, columns)
+	default:
+		return nil, fiber.NewError(fiber.StatusUnsupportedMediaType,
+			fmt.Sprintf("Content-Type must be %s or %s", NDJSONContentType, CSVContentType))
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if len(rows) == 0 {
+		return nil, fiber.NewError(fiber.StatusBadRequest, "upload contains no rows")
+	}
+	if len(rows) > maxBulkRows {
+		return nil, fiber.NewError(fiber.StatusBadRequest,
+			fmt.Sprintf("upload contains %d rows; at most %d are allowed", len(rows), maxBulkRows))
+	}
+	return rows, nil
+}
+
+func parseNDJSONRows(body []byte) ([]bulkRow, error) {
+	rows := []bulkRow{}
+	scanner := bufio.NewScanner(bytes.NewReader(body))
+	scanner.Buffer(make([]byte, 0, 64*1024), len(body)+1)
+	for line := 1; scanner.Scan(); line++ {
+		text := bytes.TrimSpace(scanner.Bytes())
+		if len(text) == 0 {
+			continue
+		}
+		if !json.Valid(text) {
+			rows = append(rows, bulkRow{Line: line, Err: errors.New("invalid JSON")})
+			continue
+		}
+		rows = append(rows, bulkRow{Line: line, Data: json.RawMessage(append([]byte(nil), text...))})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fiber.NewError(fiber.StatusBadRequest, "Failed to read NDJSON upload: "+err.Error())
+	}
+	return rows, nil
+}
+
+// parseCSVRows reads a CSV upload whose first line names the columns. Each
+// record becomes a JSON object keyed by column name; empty cells are left
+// out.
+func parseCSVRows(body []byte, columns map[string]csvColumn) ([]bulkRow, error) {
+	reader := csv.NewReader(bytes.NewReader(bytes.TrimPrefix(body, []byte("\xef\xbb\xbf"))))
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fiber.NewError(fiber.StatusBadRequest, "CSV upload must start with a header row")
+	}
+	seen := make(map[string]bool, len(header))
+	for i, column := range header {
+		column = strings.TrimSpace(column)
+		header[i] = column
+		if _, ok := columns[column]; !ok {
+			return nil, fiber.NewError(fiber.StatusBadRequest, fmt.Sprintf("unknown CSV column '%s'", column))
+		}
+		if seen[column] {
+			return nil, fiber.NewError(fiber.StatusBadRequest, fmt.Sprintf("duplicate CSV column '%s'", column))
+		}
+		seen[column] = true
+	}
+	if !seen["name"] {
+		return nil, fiber.NewError(fiber.StatusBadRequest, "CSV header must include a name column")
+	}
+
+	rows := []bulkRow{}
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		var parseErr *csv.ParseError
+		if errors.As(err, &parseErr) && errors.Is(err, csv.ErrFieldCount) {
+			rows = append(rows, bulkRow{
+				Line: parseErr.StartLine,
+				Err:  fmt.Errorf("expected %d fields, got %d", len(header), len(record)),
+			})
+			continue
+		}
+		if err != nil {
+			return nil, fiber.NewError(fiber.StatusBadRequest, "Failed to read CSV upload: "+err.Error())
+		}
+		line, _ := reader.FieldPos(0)
+
+		object := make(map[string]interface{}, len(record))
+		var cellErr error
+		for i, cell := range record {
+			if cell = strings.TrimSpace(cell); cell == "" {
+				continue
+			}
+			value, err := csvValue(columns[header[i]], cell)
+			if err != nil {
+				cellErr = fmt.Errorf("%s: %s", header[i], err.Error())
+				break
+			}
+			object[header[i]] = value
+		}
+		if cellErr != nil {
+			rows = append(rows, bulkRow{Line: line, Err: cellErr})
+			continue
+		}
+
+		data, err := json.Marshal(object)
+		if err != nil {
+			rows = append(rows, bulkRow{Line: line, Err: err})
+			continue
+		}
+		rows = append(rows, bulkRow{Line: line, Data: data})
+	}
+	return rows, nil
+}
+
+func csvValue(column csvColumn, cell string) (interface{}, error) {
+	switch column {
+	case csvNumber:
+		number, err := strconv.ParseFloat(cell, 64)
+		if err != nil {
+			return nil, fmt.Errorf("'%s' is not a number", cell)
+		}
+		return number, nil
+	case csvJSON:
+		var value interface{}
+		if err := json.Unmarshal([]byte(cell), &value); err != nil {
+			return nil, errors.New("invalid JSON")
+		}
+		return value, nil
+	case csvType:
+		if strings.HasPrefix(cell, "[") {
+			var union []string
+			if err := json.Unmarshal([]byte(cell), &union); err != nil {
+				return nil, errors.New("invalid type list")
+			}
+			return union, nil
+		}
+	}
+	return cell, nil
+}
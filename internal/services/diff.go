@@ -0,0 +1,176 @@
+package services
+
+import (
+	"reflect"
+
+	"github.com/shivamrajput1826/api-catalog/internal/dtos"
+	"github.com/shivamrajput1826/api-catalog/internal/models"
+)
+
+const (
+	ChangeEventAdded                  = "event_added"
+	ChangeEventRemoved                = "event_removed"
+	ChangePropertyAdded               = "property_added"
+	ChangePropertyRemoved             = "property_removed"
+	ChangePropertyTypeChanged         = "property_type_changed"
+	ChangeRequiredChanged             = "required_changed"
+	ChangeAdditionalPropertiesChanged = "additional_properties_changed"
+	ChangeConstraintsChanged          = "constraints_changed"
+)
+
+type eventKey struct {
+	name      string
+	eventType string
+}
+
+// DiffTrackingPlans compares two tracking plan trees and lists the changes
+// needed to turn base into head. Events are matched by name and type,
+// properties by name within their event.
+func DiffTrackingPlans(base, head *models.TrackingPlan) []dtos.TrackingPlanChange {
+	changes := []dtos.TrackingPlanChange{}
+
+	baseEvents := indexPlanEvents(base)
+	headEvents := indexPlanEvents(head)
+
+	for _, headEvent := range head.Events {
+		key := eventKey{name: headEvent.Event.Name, eventType: headEvent.Event.Type}
+		baseEvent, ok := baseEvents[key]
+		if !ok {
+			changes = append(changes, dtos.TrackingPlanChange{
+				Kind:      ChangeEventAdded,
+				Event:     key.name,
+				EventType: key.eventType,
+			})
+			continue
+		}
+		changes = append(changes, diffPlanEvent(baseEvent, headEvent)...)
+	}
+
+	for _, baseEvent := range base.Events {
+		key := eventKey{name: baseEvent.Event.Name, eventType: baseEvent.Event.Type}
+		if _, ok := headEvents[key]; !ok {
+			changes = append(changes, dtos.TrackingPlanChange{
+				Kind:      ChangeEventRemoved,
+				Event:     key.name,
+				EventType: key.eventType,
+			})
+		}
+	}
+
+	return changes
+}
+
+func diffPlanEvent(base, head models.TrackingPlanEvent) []dtos.TrackingPlanChange {
+	changes := []dtos.TrackingPlanChange{}
+	change := func(kind, property string, from, to interface{}) {
+		changes = append(changes, dtos.TrackingPlanChange{
+			Kind:      kind,
+			Event:     head.Event.Name,
+			EventType: head.Event.Type,
+			Property:  property,
+			From:      from,
+			To:        to,
+		})
+	}
+
+	if base.AdditionalProperties != head.AdditionalProperties {
+		change(ChangeAdditionalPropertiesChanged, "", base.AdditionalProperties, head.AdditionalProperties)
+	}
+
+	baseProps := indexPlanEventProperties(base)
+	headProps := indexPlanEventProperties(head)
+
+	for _, headProp := range head.Properties {
+		name := headProp.Property.Name
+		baseProp, ok := baseProps[name]
+		if !ok {
+			change(ChangePropertyAdded, name, nil, propertyTypeLabel(headProp.Property))
+			continue
+		}
+		if baseLabel, headLabel := propertyTypeLabel(baseProp.Property), propertyTypeLabel(headProp.Property); baseLabel != headLabel {
+			change(ChangePropertyTypeChanged, name, baseLabel, headLabel)
+		}
+		if baseProp.Required != headProp.Required {
+			change(ChangeRequiredChanged, name, baseProp.Required, headProp.Required)
+		}
+		if baseConstraints, headConstraints := baseProp.EffectiveConstraints(), headProp.EffectiveConstraints(); !reflect.DeepEqual(baseConstraints, headConstraints) {
+			change(ChangeConstraintsChanged, name, baseConstraints, headConstraints)
+		}
+		diffPropertyFields(change, childPath(name, headProp.Property.Items), baseProp.Property.Properties, headProp.Property.Properties)
+	}
+
+	for _, baseProp := range base.Properties {
+		name := baseProp.Property.Name
+		if _, ok := headProps[name]; !ok {
+			change(ChangePropertyRemoved, name, propertyTypeLabel(baseProp.Property), nil)
+		}
+	}
+
+	return changes
+}
+
+// diffPropertyFields compares the children of an object property, or of the
+// objects in an array property, reporting them by path such as
+// "products[].sku".
+func diffPropertyFields(change func(kind, property string, from, to interface{}), prefix string, base, head []models.PropertyField) {
+	baseFields := make(map[string]models.PropertyField, len(base))
+	for _, field := range base {
+		baseFields[field.Name] = field
+	}
+	headFields := make(map[string]bool, len(head))
+
+	for _, headField := range head {
+		headFields[headField.Name] = true
+		path := prefix + "." + headField.Name
+		baseField, ok := baseFields[headField.Name]
+		if !ok {
+			change(ChangePropertyAdded, path, nil, models.TypeLabel(headField.Type, headField.Items))
+			continue
+		}
+		if baseLabel, headLabel := models.TypeLabel(baseField.Type, baseField.Items), models.TypeLabel(headField.Type, headField.Items); baseLabel != headLabel {
+			change(ChangePropertyTypeChanged, path, baseLabel, headLabel)
+		}
+		if baseField.Required != headField.Required {
+			change(ChangeRequiredChanged, path, baseField.Required, headField.Required)
+		}
+		if !reflect.DeepEqual(baseField.PropertyConstraints, headField.PropertyConstraints) {
+			change(ChangeConstraintsChanged, path, baseField.PropertyConstraints, headField.PropertyConstraints)
+		}
+		diffPropertyFields(change, childPath(path, headField.Items), baseField.Properties, headField.Properties)
+	}
+
+	for _, baseField := range base {
+		if !headFields[baseField.Name] {
+			change(ChangePropertyRemoved, prefix+"."+baseField.Name, models.TypeLabel(baseField.Type, baseField.Items), nil)
+		}
+	}
+}
+
+// childPath is the prefix for the children of a property: the elements of an
+// array are marked with "[]".
+func childPath(path string, items models.PropertyType) string {
+	if items != "" {
+		return path + "[]"
+	}
+	return path
+}
+
+func indexPlanEvents(plan *models.TrackingPlan) map[eventKey]models.TrackingPlanEvent {
+	index := make(map[eventKey]models.TrackingPlanEvent, len(plan.Events))
+	for _, planEvent := range plan.Events {
+		index[eventKey{name: planEvent.Event.Name, eventType: planEvent.Event.Type}] = planEvent
+	}
+	return index
+}
+
+func indexPlanEventProperties(planEvent models.TrackingPlanEvent) map[string]models.TrackingPlanEventProperty {
+	index := make(map[string]models.TrackingPlanEventProperty, len(planEvent.Properties))
+	for _, planProp := range planEvent.Properties {
+		index[planProp.Property.Name] = planProp
+	}
+	return index
+}
+
+func propertyTypeLabel(property models.Property) string {
+	return models.TypeLabel(property.Type, property.Items)
+}
@@ -0,0 +1,155 @@
+package services
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/shivamrajput1826/api-catalog/internal/models"
+)
+
+// diffTestPlan is a tracking plan with a "Signed Up" track event using the
+// properties plan (required string), seats (integer) and address (object
+// with a city), and a "Home" page event.
+func diffTestPlan() *models.TrackingPlan {
+	return &models.TrackingPlan{
+		Name: "Web",
+		Events: []models.TrackingPlanEvent{
+			{
+				Event: models.Event{Name: "Signed Up", Type: "track"},
+				Properties: []models.TrackingPlanEventProperty{
+					{Property: models.Property{Name: "plan", Type: "string"}, Required: true},
+					{Property: models.Property{Name: "seats", Type: "integer"}},
+					{Property: models.Property{
+						Name: "address", Type: "object",
+						Properties: []models.PropertyField{{Name: "city", Type: "string", Required: true}},
+					}},
+				},
+			},
+			{Event: models.Event{Name: "Home", Type: "page"}},
+		},
+	}
+}
+
+func TestDiffTrackingPlans(t *testing.T) {
+	minLength := 2
+
+	tests := []struct {
+		name   string
+		change func(head *models.TrackingPlan)
+		want   []string
+	}{
+		{
+			name:   "identical",
+			change: func(head *models.TrackingPlan) {},
+		},
+		{
+			name: "event added and removed",
+			change: func(head *models.TrackingPlan) {
+				head.Events[1] = models.TrackingPlanEvent{Event: models.Event{Name: "Logged In", Type: "track"}}
+			},
+			want: []string{
+				"event_added track Logged In",
+				"event_removed page Home",
+			},
+		},
+		{
+			name: "same name with another type is another event",
+			change: func(head *models.TrackingPlan) {
+				head.Events[1].Event.Type = "screen"
+			},
+			want: []string{
+				"event_added screen Home",
+				"event_removed page Home",
+			},
+		},
+		{
+			name: "property added and removed",
+			change: func(head *models.TrackingPlan) {
+				props := head.Events[0].Properties
+				head.Events[0].Properties = []models.TrackingPlanEventProperty{
+					props[0], props[2],
+					{Property: models.Property{Name: "tags", Type: "array", Items: "string"}},
+				}
+			},
+			want: []string{
+				"property_added track Signed Up tags <nil> -> array<string>",
+				"property_removed track Signed Up seats integer -> <nil>",
+			},
+		},
+		{
+			name: "property type changed",
+			change: func(head *models.TrackingPlan) {
+				head.Events[0].Properties[1].Property.Type = "number|null"
+			},
+			want: []string{"property_type_changed track Signed Up seats integer -> number|null"},
+		},
+		{
+			name: "required flipped",
+			change: func(head *models.TrackingPlan) {
+				head.Events[0].Properties[0].Required = false
+			},
+			want: []string{"required_changed track Signed Up plan true -> false"},
+		},
+		{
+			name: "additional properties",
+			change: func(head *models.TrackingPlan) {
+				head.Events[1].AdditionalProperties = true
+			},
+			want: []string{"additional_properties_changed page Home  false -> true"},
+		},
+		{
+			name: "plan-level constraints",
+			change: func(head *models.TrackingPlan) {
+				head.Events[0].Properties[0].MinLength = &minLength
+			},
+			want: []string{"constraints_changed track Signed Up plan"},
+		},
+		{
+			name: "catalog constraints",
+			change: func(head *models.TrackingPlan) {
+				head.Events[0].Properties[0].Property.Enum = []interface{}{"free", "pro"}
+			},
+			want: []string{"constraints_changed track Signed Up plan"},
+		},
+		{
+			name: "child properties",
+			change: func(head *models.TrackingPlan) {
+				head.Events[0].Properties[2].Property.Properties = []models.PropertyField{
+					{Name: "city", Type: "string"},
+					{Name: "zip", Type: "string"},
+				}
+			},
+			want: []string{
+				"required_changed track Signed Up address.city true -> false",
+				"property_added track Signed Up address.zip <nil> -> string",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			head := diffTestPlan()
+			tt.change(head)
+
+			got := []string{}
+			for _, change := range DiffTrackingPlans(diffTestPlan(), head) {
+				line := fmt.Sprintf("%s %s %s", change.Kind, change.EventType, change.Event)
+				if change.Property != "" || change.Kind == ChangeAdditionalPropertiesChanged {
+					line += " " + change.Property
+				}
+				// Constraint values are structs; only their presence matters here.
+				if change.Kind != ChangeConstraintsChanged && (change.From != nil || change.To != nil) {
+					line += fmt.Sprintf(" %v -> %v", change.From, change.To)
+				}
+				got = append(got, line)
+			}
+			if tt.want == nil {
+				tt.want = []string{}
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got changes\n%q\nwant\n%q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,132 @@
+package services
+
+import (
+	"math"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/shivamrajput1826/api-catalog/internal/dtos"
+	"github.com/shivamrajput1826/api-catalog/internal/models"
+	"github.com/shivamrajput1826/api-catalog/internal/utils"
+	"github.com/shivamrajput1826/api-catalog/internal/validation"
+)
+
+type listKey struct {
+	id         uint
+	name       string
+	createTime int64
+	updateTime int64
+}
+
+// listOptionsFromQuery validates a list query and turns it into repository
+// options. The returned options ask for one row more than the page size so
+// the caller can tell whether a next page exists.
+func listOptionsFromQuery(validator *validation.Validator, query *dtos.ListQuery, validTypes map[string]bool) (models.ListOptions, error) {
+	if err := validator.ValidateListQuery(query, validTypes); err != nil {
+		return models.ListOptions{}, err
+	}
+
+	limit := query.Limit
+	if limit == 0 {
+		limit = validation.DefaultListLimit
+	}
+	sortBy := query.Sort
+	if sortBy == "" {
+		sortBy = "id"
+	}
+
+	opts := models.ListOptions{
+		Limit:          limit + 1,
+		Type:           query.Type,
+		NamePrefix:     query.NamePrefix,
+		IncludeDeleted: query.IncludeDeleted,
+		CreatedAfter:   query.CreatedAfter,
+		CreatedBefore:  query.CreatedBefore,
+		UpdatedAfter:   query.UpdatedAfter,
+		UpdatedBefore:  query.UpdatedBefore,
+		SortBy:         sortBy,
+		Descending:     query.Order == "desc",
+		WithEvents:     query.Expand == "events",
+	}
+
+	if query.Cursor != "" {
+		cursor, err := utils.DecodeCursor(query.Cursor)
+		if err != nil {
+			return models.ListOptions{}, err
+		}
+		if cursor.SortBy != opts.SortBy || cursor.Descending != opts.Descending {
+			return models.ListOptions{}, fiber.NewError(fiber.StatusBadRequest, "cursor does not match the requested sort order")
+		}
+		opts.AfterID = cursor.ID
+		if opts.AfterValue, err = cursorValue(cursor); err != nil {
+			return models.ListOptions{}, err
+		}
+	}
+
+	return opts, nil
+}
+
+// cursorValue checks the sort key value of a cursor against the column it
+// sorts by, so a tampered cursor is rejected instead of failing the query.
+// Times arrive as JSON numbers and must be whole.
+func cursorValue(cursor *utils.Cursor) (interface{}, error) {
+	switch cursor.SortBy {
+	case "id":
+		return nil, nil
+	case "name":
+		if value, ok := cursor.Value.(string); ok {
+			return value, nil
+		}
+	case "create_time", "update_time":
+		if value, ok := cursor.Value.(float64); ok && value == math.Trunc(value) && math.Abs(value) < 1<<53 {
+			return int64(value), nil
+		}
+	}
+	return nil, fiber.NewError(fiber.StatusBadRequest, "Invalid cursor")
+}
+
+// buildPage trims the extra row fetched by listOptionsFromQuery and, when it
+// was present, issues a cursor pointing past the last row of this page.
+func buildPage[T any](rows []T, total int64, opts models.ListOptions, key func(T) listKey) *dtos.PageResponse {
+	limit := opts.Limit - 1
+	page := &dtos.PageResponse{
+		Total: total,
+		Limit: limit,
+	}
+
+	if len(rows) > limit {
+		rows = rows[:limit]
+		last := key(rows[len(rows)-1])
+		cursor := utils.Cursor{
+			SortBy:     opts.SortBy,
+			Descending: opts.Descending,
+			ID:         last.id,
+		}
+		switch opts.SortBy {
+		case "name":
+			cursor.Value = last.name
+		case "create_time":
+			cursor.Value = last.createTime
+		case "update_time":
+			cursor.Value = last.updateTime
+		}
+		page.NextCursor = utils.EncodeCursor(cursor)
+	}
+
+	if rows == nil {
+		rows = []T{}
+	}
+	page.Data = rows
+	return page
+}
+
+func eventListKey(event models.Event) listKey {
+	return listKey{id: event.ID, name: event.Name, createTime: event.CreateTime, updateTime: event.UpdateTime}
+}
+
+func propertyListKey(property models.Property) listKey {
+	return listKey{id: property.ID, name: property.Name, createTime: property.CreateTime, updateTime: property.UpdateTime}
+}
+
+func trackingPlanListKey(plan models.TrackingPlan) listKey {
+	return listKey{id: plan.ID, name: plan.Name, createTime: plan.CreateTime, updateTime: plan.UpdateTime}
+}
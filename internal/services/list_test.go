@@ -0,0 +1,51 @@
+package services
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/shivamrajput1826/api-catalog/internal/dtos"
+	"github.com/shivamrajput1826/api-catalog/internal/utils"
+	"github.com/shivamrajput1826/api-catalog/internal/validation"
+)
+
+func TestListOptionsRejectCursorValuesOfTheWrongType(t *testing.T) {
+	cursors := []utils.Cursor{
+		{SortBy: "name", ID: 1, Value: 42.0},
+		{SortBy: "name", ID: 1},
+		{SortBy: "create_time", ID: 1, Value: "yesterday"},
+		{SortBy: "create_time", ID: 1, Value: 1.5},
+		{SortBy: "update_time", ID: 1, Value: []interface{}{1.0}},
+		{SortBy: "update_time", ID: 1, Value: map[string]interface{}{}},
+	}
+	for _, cursor := range cursors {
+		query := &dtos.ListQuery{Sort: cursor.SortBy, Cursor: utils.EncodeCursor(cursor)}
+		_, err := listOptionsFromQuery(validation.New(), query, nil)
+
+		var fiberErr *fiber.Error
+		if !errors.As(err, &fiberErr) || fiberErr.Code != fiber.StatusBadRequest {
+			t.Errorf("%+v: got %v, want a 400", cursor, err)
+		}
+	}
+}
+
+func TestListOptionsReadCursorValues(t *testing.T) {
+	for _, tc := range []struct {
+		cursor utils.Cursor
+		want   interface{}
+	}{
+		{utils.Cursor{SortBy: "id", ID: 7}, nil},
+		{utils.Cursor{SortBy: "name", ID: 7, Value: "Signed Up"}, "Signed Up"},
+		{utils.Cursor{SortBy: "create_time", ID: 7, Value: 1700000000}, int64(1700000000)},
+	} {
+		query := &dtos.ListQuery{Sort: tc.cursor.SortBy, Cursor: utils.EncodeCursor(tc.cursor)}
+		opts, err := listOptionsFromQuery(validation.New(), query, nil)
+		if err != nil {
+			t.Fatalf("%+v: %v", tc.cursor, err)
+		}
+		if opts.AfterID != 7 || opts.AfterValue != tc.want {
+			t.Errorf("%+v: got %v after %d, want %v", tc.cursor, opts.AfterValue, opts.AfterID, tc.want)
+		}
+	}
+}
@@ -0,0 +1,305 @@
+package services
+
+import (
+	"fmt"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/shivamrajput1826/api-catalog/internal/dtos"
+	"github.com/shivamrajput1826/api-catalog/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// AddTrackingPlanEvent adds one event, with its properties, to a tracking
+// plan. Events and properties missing from the catalog are created.
+func (s *TrackingPlanService) AddTrackingPlanEvent(planID uint, req *dtos.TrackingPlanEventRequest, ifMatch []int) (*models.TrackingPlan, error) {
+	if err := s.validator.ValidateTrackingPlanEvent(req); err != nil {
+		return nil, err
+	}
+
+	return s.modifyTrackingPlan(planID, ifMatch, func(tx *gorm.DB, plan *models.TrackingPlan) error {
+		event, err := s.findOrCreateEvent(tx, req.Name, req.Type, req.Description)
+		if err != nil {
+			return err
+		}
+		if findPlanEvent(plan, event.ID) != nil {
+			return fiber.NewError(fiber.StatusConflict,
+				fmt.Sprintf("Event '%s' is already part of the tracking plan", event.Name))
+		}
+
+		planEvent := &models.TrackingPlanEvent{TrackingPlanID: plan.ID, EventID: event.ID}
+		return s.saveTrackingPlanEvent(tx, planEvent, req)
+	})
+}
+
+// RemoveTrackingPlanEvent takes an event out of a tracking plan. The event
+// itself stays in the catalog.
+func (s *TrackingPlanService) RemoveTrackingPlanEvent(planID, eventID uint, ifMatch []int) (*models.TrackingPlan, error) {
+	return s.modifyTrackingPlan(planID, ifMatch, func(tx *gorm.DB, plan *models.TrackingPlan) error {
+		planEvent := findPlanEvent(plan, eventID)
+		if planEvent == nil {
+			return fiber.NewError(fiber.StatusNotFound, "Event is not part of the tracking plan")
+		}
+		if err := tx.Delete(planEvent).Error; err != nil {
+			return fiber.NewError(fiber.StatusInternalServerError, "Failed to remove event from tracking plan")
+		}
+		return nil
+	})
+}
+
+// SetTrackingPlanEventProperty adds a catalog property to an event of a
+// tracking plan, or updates how the event uses it if it is already there.
+func (s *TrackingPlanService) SetTrackingPlanEventProperty(planID, eventID, propertyID uint, req *dtos.TrackingPlanEventPropertyRequest, ifMatch []int) (*models.TrackingPlan, error) {
+	return s.modifyTrackingPlan(planID, ifMatch, func(tx *gorm.DB, plan *models.TrackingPlan) error {
+		// The share lock keeps the property from being deleted until the plan
+		// that now references it has committed.
+		var property models.Property
+		if err := tx.Clauses(clause.Locking{Strength: "SHARE"}).First(&property, propertyID).Error; err != nil {
+			if err == gorm.ErrRecordNotFound {
+				return fiber.NewError(fiber.StatusNotFound, "Property not found")
+			}
+			return fiber.NewError(fiber.StatusInternalServerError, "Failed to fetch property")
+		}
+		if err := s.validator.ValidatePropertyConstraints(&property, req.PropertyConstraints); err != nil {
+			return err
+		}
+
+		planEvent := findPlanEvent(plan, eventID)
+		if planEvent == nil {
+			return fiber.NewError(fiber.StatusNotFound, "Event is not part of the tracking plan")
+		}
+
+		planProp := findPlanEventProperty(planEvent, propertyID)
+		if planProp == nil {
+			planProp = &models.TrackingPlanEventProperty{
+				TrackingPlanEventID: planEvent.ID,
+				PropertyID:          property.ID,
+			}
+		}
+		planProp.Required = req.Required
+		planProp.PropertyConstraints = req.PropertyConstraints
+		return saveTrackingPlanEventProperty(tx, planProp)
+	})
+}
+
+// RemoveTrackingPlanEventProperty takes a property off an event of a
+// tracking plan. The property itself stays in the catalog.
+func (s *TrackingPlanService) RemoveTrackingPlanEventProperty(planID, eventID, propertyID uint, ifMatch []int) (*models.TrackingPlan, error) {
+	return s.modifyTrackingPlan(planID, ifMatch, func(tx *gorm.DB, plan *models.TrackingPlan) error {
+		planEvent := findPlanEvent(plan, eventID)
+		if planEvent == nil {
+			return fiber.NewError(fiber.StatusNotFound, "Event is not part of the tracking plan")
+		}
+		planProp := findPlanEventProperty(planEvent, propertyID)
+		if planProp == nil {
+			return fiber.NewError(fiber.StatusNotFound, "Property is not part of the event")
+		}
+		if err := tx.Delete(planProp).Error; err != nil {
+			return fiber.NewError(fiber.StatusInternalServerError, "Failed to remove property from event")
+		}
+		return nil
+	})
+}
+
+// modifyTrackingPlan runs modify against a locked tracking plan and records a
+// new plan version and an audit entry in the same transaction. The plan passed
+// to modify has its events and their properties loaded.
+func (s *TrackingPlanService) modifyTrackingPlan(id uint, ifMatch []int, modify func(tx *gorm.DB, plan *models.TrackingPlan) error) (*models.TrackingPlan, error) {
+	err := runInTransaction(s.txManager, func(tx *gorm.DB) error {
+		plan, err := lockRow[models.TrackingPlan](tx, id)
+		if err != nil {
+			if err == gorm.ErrRecordNotFound {
+				return fiber.NewError(fiber.StatusNotFound, "Tracking plan not found")
+			}
+			return fiber.NewError(fiber.StatusInternalServerError, "Failed to fetch tracking plan")
+		}
+		if err := checkIfMatch("Tracking plan", ifMatch, plan.Version); err != nil {
+			return err
+		}
+		if plan.Events, err = loadTrackingPlanEvents(tx, plan.ID); err != nil {
+			return err
+		}
+
+		// modify changes the loaded plan in place, so take the before state now.
+		before, err := auditSnapshot(plan)
+		if err != nil {
+			return fiber.NewError(fiber.StatusInternalServerError, "Failed to record audit entry")
+		}
+		if err := modify(tx, plan); err != nil {
+			return err
+		}
+
+		after, err := recordTrackingPlanVersion(tx, plan.ID)
+		if err != nil {
+			return err
+		}
+		return s.auditor.Record(tx, models.AuditResourceTrackingPlan, plan.ID, models.AuditActionUpdate, before, after)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := s.trackingPlanRepo.GetByID(id)
+	if err != nil {
+		return nil, fiber.NewError(fiber.StatusInternalServerError, "Failed to fetch updated tracking plan")
+	}
+	return result, nil
+}
+
+// syncTrackingPlanEvents makes the events of a plan match the request. Rows
+// for events that stay in the plan are updated in place so they keep their
+// IDs; only events and properties that are gone are deleted.
+func (s *TrackingPlanService) syncTrackingPlanEvents(tx *gorm.DB, trackingPlanID uint, events []dtos.TrackingPlanEventRequest) error {
+	existing, err := loadTrackingPlanEvents(tx, trackingPlanID)
+	if err != nil {
+		return err
+	}
+	byEventID := make(map[uint]*models.TrackingPlanEvent, len(existing))
+	for i := range existing {
+		byEventID[existing[i].EventID] = &existing[i]
+	}
+
+	kept := make(map[uint]bool, len(events))
+	for i := range events {
+		eventReq := &events[i]
+		event, err := s.findOrCreateEvent(tx, eventReq.Name, eventReq.Type, eventReq.Description)
+		if err != nil {
+			return err
+		}
+		if kept[event.ID] {
+			return fiber.NewError(fiber.StatusBadRequest,
+				fmt.Sprintf("event '%s' of type '%s' is listed more than once", event.Name, event.Type))
+		}
+		kept[event.ID] = true
+
+		planEvent, ok := byEventID[event.ID]
+		if !ok {
+			planEvent = &models.TrackingPlanEvent{TrackingPlanID: trackingPlanID, EventID: event.ID}
+		}
+		if err := s.saveTrackingPlanEvent(tx, planEvent, eventReq); err != nil {
+			return err
+		}
+	}
+
+	removed := make([]uint, 0)
+	for _, planEvent := range existing {
+		if !kept[planEvent.EventID] {
+			removed = append(removed, planEvent.ID)
+		}
+	}
+	if len(removed) > 0 {
+		if err := tx.Delete(&models.TrackingPlanEvent{}, removed).Error; err != nil {
+			return fiber.NewError(fiber.StatusInternalServerError, "Failed to delete tracking plan events")
+		}
+	}
+	return nil
+}
+
+// saveTrackingPlanEvent creates or updates a plan event row and syncs its
+// properties with the request.
+func (s *TrackingPlanService) saveTrackingPlanEvent(tx *gorm.DB, planEvent *models.TrackingPlanEvent, req *dtos.TrackingPlanEventRequest) error {
+	if planEvent.ID == 0 {
+		planEvent.AdditionalProperties = req.AdditionalProperties
+		if err := tx.Omit("Event", "Properties").Create(planEvent).Error; err != nil {
+			return fiber.NewError(fiber.StatusInternalServerError, "Failed to create tracking plan event")
+		}
+	} else if planEvent.AdditionalProperties != req.AdditionalProperties {
+		if err := tx.Model(planEvent).UpdateColumn("additional_properties", req.AdditionalProperties).Error; err != nil {
+			return fiber.NewError(fiber.StatusInternalServerError, "Failed to update tracking plan event")
+		}
+	}
+
+	existing := make(map[uint]*models.TrackingPlanEventProperty, len(planEvent.Properties))
+	for i := range planEvent.Properties {
+		existing[planEvent.Properties[i].PropertyID] = &planEvent.Properties[i]
+	}
+
+	kept := make(map[uint]bool, len(req.Properties))
+	for i := range req.Properties {
+		propReq := &req.Properties[i]
+		property, err := s.findOrCreateProperty(tx, propReq)
+		if err != nil {
+			return err
+		}
+		if kept[property.ID] {
+			return fiber.NewError(fiber.StatusBadRequest,
+				fmt.Sprintf("property '%s' is listed more than once on event '%s'", property.Name, req.Name))
+		}
+		kept[property.ID] = true
+
+		planProp, ok := existing[property.ID]
+		if !ok {
+			planProp = &models.TrackingPlanEventProperty{
+				TrackingPlanEventID: planEvent.ID,
+				PropertyID:          property.ID,
+			}
+		}
+		planProp.Required = propReq.Required
+		if !ok || !propReq.KeepConstraints {
+			planProp.PropertyConstraints = propReq.PropertyConstraints
+		}
+		if err := saveTrackingPlanEventProperty(tx, planProp); err != nil {
+			return err
+		}
+	}
+
+	removed := make([]uint, 0)
+	for _, planProp := range planEvent.Properties {
+		if !kept[planProp.PropertyID] {
+			removed = append(removed, planProp.ID)
+		}
+	}
+	if len(removed) > 0 {
+		if err := tx.Delete(&models.TrackingPlanEventProperty{}, removed).Error; err != nil {
+			return fiber.NewError(fiber.StatusInternalServerError, "Failed to delete tracking plan event properties")
+		}
+	}
+	return nil
+}
+
+func saveTrackingPlanEventProperty(tx *gorm.DB, planProp *models.TrackingPlanEventProperty) error {
+	if planProp.ID == 0 {
+		if err := tx.Omit("Property").Create(planProp).Error; err != nil {
+			return fiber.NewError(fiber.StatusInternalServerError, "Failed to create tracking plan event property")
+		}
+		return nil
+	}
+
+	err := tx.Model(planProp).
+		Select("Required", "Enum", "Pattern", "Minimum", "Maximum", "MinLength", "MaxLength", "Format").
+		Updates(planProp).Error
+	if err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, "Failed to update tracking plan event property")
+	}
+	return nil
+}
+
+func loadTrackingPlanEvents(tx *gorm.DB, trackingPlanID uint) ([]models.TrackingPlanEvent, error) {
+	var events []models.TrackingPlanEvent
+	err := tx.Preload("Event").Preload("Properties.Property").
+		Where("tracking_plan_id = ?", trackingPlanID).
+		Order("id").
+		Find(&events).Error
+	if err != nil {
+		return nil, fiber.NewError(fiber.StatusInternalServerError, "Failed to fetch tracking plan events")
+	}
+	return events, nil
+}
+
+func findPlanEvent(plan *models.TrackingPlan, eventID uint) *models.TrackingPlanEvent {
+	for i := range plan.Events {
+		if plan.Events[i].EventID == eventID {
+			return &plan.Events[i]
+		}
+	}
+	return nil
+}
+
+func findPlanEventProperty(planEvent *models.TrackingPlanEvent, propertyID uint) *models.TrackingPlanEventProperty {
+	for i := range planEvent.Properties {
+		if planEvent.Properties[i].PropertyID == propertyID {
+			return &planEvent.Properties[i]
+		}
+	}
+	return nil
+}
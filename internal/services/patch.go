@@ -0,0 +1,116 @@
+package services
+
+import (
+	"encoding/json"
+	"fmt"
+
+	jsonpatch "github.com/evanphx/json-patch/v5"
+	"github.com/gofiber/fiber/v2"
+	"github.com/shivamrajput1826/api-catalog/internal/dtos"
+	"github.com/shivamrajput1826/api-catalog/internal/models"
+)
+
+const (
+	MergePatchContentType = "application/merge-patch+json"
+	JSONPatchContentType  = "application/json-patch+json"
+)
+
+// applyPatch applies an RFC 7396 merge patch or an RFC 6902 JSON Patch to the
+// JSON form of current and decodes the result into target. The patched
+// document is then saved through the regular update path, so it is validated
+// exactly like a PUT.
+func applyPatch(current interface{}, patch []byte, contentType string, target interface{}) error {
+	document, err := json.Marshal(current)
+	if err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, "Failed to prepare resource for patching")
+	}
+
+	var patched []byte
+	switch contentType {
+	case MergePatchContentType:
+		patched, err = jsonpatch.MergePatch(document, patch)
+		if err != nil {
+			return fiber.NewError(fiber.StatusBadRequest, "Invalid merge patch: "+err.Error())
+		}
+	case JSONPatchContentType:
+		operations, err := jsonpatch.DecodePatch(patch)
+		if err != nil {
+			return fiber.NewError(fiber.StatusBadRequest, "Invalid JSON Patch: "+err.Error())
+		}
+		patched, err = operations.Apply(document)
+		if err != nil {
+			return fiber.NewError(fiber.StatusConflict, "JSON Patch could not be applied: "+err.Error())
+		}
+	default:
+		return fiber.NewError(fiber.StatusUnsupportedMediaType,
+			fmt.Sprintf("Content-Type must be %s or %s", MergePatchContentType, JSONPatchContentType))
+	}
+
+	if err := json.Unmarshal(patched, target); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Patched document is invalid: "+err.Error())
+	}
+	return nil
+}
+
+// patchPrecondition checks the caller's If-Match against the version the
+// patch was applied to, and returns the precondition for saving the result:
+// the save must fail if the resource changed after it was read.
+func patchPrecondition(resource string, ifMatch []int, version int) ([]int, error) {
+	if err := checkIfMatch(resource, ifMatch, version); err != nil {
+		return nil, err
+	}
+	return []int{version}, nil
+}
+
+func eventUpdateRequest(event *models.Event) *dtos.UpdateEventRequest {
+	return &dtos.UpdateEventRequest{
+		Name:        event.Name,
+		Type:        event.Type,
+		Description: event.Description,
+	}
+}
+
+func propertyUpdateRequest(property *models.Property) *dtos.UpdatePropertyRequest {
+	return &dtos.UpdatePropertyRequest{
+		Name:                property.Name,
+		Type:                property.Type,
+		Items:               property.Items,
+		Description:         property.Description,
+		PropertyConstraints: property.PropertyConstraints,
+		Properties:          property.Properties,
+	}
+}
+
+// trackingPlanUpdateRequest turns a stored plan back into the request that
+// would recreate it. Constraints are the plan-level ones of each usage; the
+// catalog constraints stay on the property itself.
+func trackingPlanUpdateRequest(plan *models.TrackingPlan) *dtos.UpdateTrackingPlanRequest {
+	events := make([]dtos.TrackingPlanEventRequest, 0, len(plan.Events))
+	for _, planEvent := range plan.Events {
+		properties := make([]dtos.TrackingPlanPropertyRequest, 0, len(planEvent.Properties))
+		for _, planProp := range planEvent.Properties {
+			properties = append(properties, dtos.TrackingPlanPropertyRequest{
+				Name:                planProp.Property.Name,
+				Type:                planProp.Property.Type,
+				Items:               planProp.Property.Items,
+				Required:            planProp.Required,
+				Description:         planProp.Property.Description,
+				PropertyConstraints: planProp.PropertyConstraints,
+				Properties:          planProp.Property.Properties,
+			})
+		}
+		events = append(events, dtos.TrackingPlanEventRequest{
+			Name:                 planEvent.Event.Name,
+			Description:          planEvent.Event.Description,
+			Properties:           properties,
+			AdditionalProperties: planEvent.AdditionalProperties,
+			Type:                 planEvent.Event.Type,
+		})
+	}
+
+	return &dtos.UpdateTrackingPlanRequest{
+		Name:        plan.Name,
+		Description: plan.Description,
+		Events:      events,
+	}
+}
@@ -0,0 +1,22 @@
+package services
+
+import (
+	"fmt"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// checkIfMatch enforces an If-Match precondition against the current version
+// of a resource. A nil ifMatch means the request carried no precondition.
+func checkIfMatch(resource string, ifMatch []int, version int) error {
+	if ifMatch == nil {
+		return nil
+	}
+	for _, expected := range ifMatch {
+		if expected == version {
+			return nil
+		}
+	}
+	return fiber.NewError(fiber.StatusPreconditionFailed,
+		fmt.Sprintf("%s has been modified (current version %d); fetch it again and retry", resource, version))
+}
@@ -0,0 +1,146 @@
+package services
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/shivamrajput1826/api-catalog/internal/dtos"
+	"github.com/shivamrajput1826/api-catalog/internal/models"
+	"gorm.io/gorm"
+)
+
+// ReferenceConflictError is returned when an event or property cannot be
+// deleted because tracking plans still reference it.
+type ReferenceConflictError struct {
+	Message       string
+	TrackingPlans []dtos.TrackingPlanRef
+}
+
+func (e *ReferenceConflictError) Error() string {
+	return e.Message
+}
+
+func newReferenceConflictError(resource string, plans []models.TrackingPlan) *ReferenceConflictError {
+	refs := make([]dtos.TrackingPlanRef, 0, len(plans))
+	names := make([]string, 0, len(plans))
+	for _, plan := range plans {
+		refs = append(refs, dtos.TrackingPlanRef{
+			TrackingPlanID: plan.ID,
+			Name:           plan.Name,
+			Version:        plan.Version,
+		})
+		names = append(names, fmt.Sprintf("'%s'", plan.Name))
+	}
+	return &ReferenceConflictError{
+		Message: fmt.Sprintf("%s is used by tracking plans %s; delete with cascade=true to detach it",
+			resource, strings.Join(names, ", ")),
+		TrackingPlans: refs,
+	}
+}
+
+// deleteReferenced soft deletes an event or property that the caller has
+// locked with lockRow. Plan changes that would add a reference take a share
+// lock on the row, so the tracking plans found using it here cannot change
+// before the delete commits. With cascade, detach removes the row from those
+// plans, and every affected plan gets a new version and an audit entry of its
+// own, so the detachment shows up in plan history and in the audit log.
+// Soft-deleted plans are neither blocking nor detached, so a restored plan
+// comes back with the members it was deleted with.
+func deleteReferenced(
+	tx *gorm.DB,
+	auditor *Auditor,
+	auditResource, resource string,
+	id uint,
+	row interface{},
+	deletedBy string,
+	cascade bool,
+	usedBy func(tx *gorm.DB, id uint) ([]models.TrackingPlan, error),
+	detach func(planIDs []uint) error,
+) error {
+	before, err := auditSnapshot(row)
+	if err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, "Failed to record audit entry")
+	}
+
+	plans, err := usedBy(tx, id)
+	if err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError,
+			fmt.Sprintf("Failed to fetch tracking plans using the %s", strings.ToLower(resource)))
+	}
+	if len(plans) > 0 && !cascade {
+		return newReferenceConflictError(resource, plans)
+	}
+
+	plansBefore := make([]json.RawMessage, len(plans))
+	planIDs := make([]uint, len(plans))
+	for i, plan := range plans {
+		if plansBefore[i], err = lockTrackingPlanSnapshot(tx, plan.ID); err != nil {
+			return err
+		}
+		planIDs[i] = plan.ID
+	}
+	if len(plans) > 0 {
+		if err := detach(planIDs); err != nil {
+			return err
+		}
+	}
+
+	if err := tx.Model(row).UpdateColumns(models.DeletionColumns(deletedBy)).Error; err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, fmt.Sprintf("Failed to delete %s", strings.ToLower(resource)))
+	}
+	if err := auditor.Record(tx, auditResource, id, models.AuditActionDelete, before, nil); err != nil {
+		return err
+	}
+
+	for i, plan := range plans {
+		after, err := recordTrackingPlanVersion(tx, plan.ID)
+		if err != nil {
+			return err
+		}
+		if err := auditor.Record(tx, models.AuditResourceTrackingPlan, plan.ID, models.AuditActionUpdate, plansBefore[i], after); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// lockTrackingPlanSnapshot locks a tracking plan and returns the JSON form of
+// it and its events for the audit log.
+func lockTrackingPlanSnapshot(tx *gorm.DB, id uint) (json.RawMessage, error) {
+	plan, err := lockRow[models.TrackingPlan](tx, id)
+	if err != nil {
+		return nil, fiber.NewError(fiber.StatusInternalServerError, "Failed to fetch tracking plan")
+	}
+	if plan.Events, err = loadTrackingPlanEvents(tx, id); err != nil {
+		return nil, err
+	}
+	snapshot, err := auditSnapshot(plan)
+	if err != nil {
+		return nil, fiber.NewError(fiber.StatusInternalServerError, "Failed to record audit entry")
+	}
+	return snapshot, nil
+}
+
+// plansUsingEvent returns the tracking plans that include the event.
+func plansUsingEvent(tx *gorm.DB, eventID uint) ([]models.TrackingPlan, error) {
+	var plans []models.TrackingPlan
+	planIDs := tx.Model(&models.TrackingPlanEvent{}).
+		Select("tracking_plan_id").
+		Where("event_id = ?", eventID)
+	err := tx.Where("id IN (?)", planIDs).Order("id").Find(&plans).Error
+	return plans, err
+}
+
+// plansUsingProperty returns the tracking plans with at least one event using
+// the property.
+func plansUsingProperty(tx *gorm.DB, propertyID uint) ([]models.TrackingPlan, error) {
+	var plans []models.TrackingPlan
+	planIDs := tx.Model(&models.TrackingPlanEvent{}).
+		Select("tracking_plan_events.tracking_plan_id").
+		Joins("JOIN tracking_plan_event_properties ON tracking_plan_event_properties.tracking_plan_event_id = tracking_plan_events.id").
+		Where("tracking_plan_event_properties.property_id = ?", propertyID)
+	err := tx.Where("id IN (?)", planIDs).Order("id").Find(&plans).Error
+	return plans, err
+}
@@ -1,23 +1,45 @@
 package services
 
 import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+
 	"github.com/shivamrajput1826/api-catalog/internal/dtos"
+	"github.com/shivamrajput1826/api-catalog/internal/jsonschema"
 	"github.com/shivamrajput1826/api-catalog/internal/models"
+	"github.com/shivamrajput1826/api-catalog/internal/spreadsheet"
 	"github.com/shivamrajput1826/api-catalog/internal/validation"
 
 	"github.com/gofiber/fiber/v2"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type EventService struct {
-	eventRepo models.EventRepository
-	validator *validation.Validator
+	eventRepo        models.EventRepository
+	trackingPlanRepo models.TrackingPlanRepository
+	txManager        models.TransactionManager
+	validator        *validation.Validator
+	auditor          *Auditor
 }
 
-func NewEventService(eventRepo models.EventRepository, validator *validation.Validator) *EventService {
+func NewEventService(
+	eventRepo models.EventRepository,
+	trackingPlanRepo models.TrackingPlanRepository,
+	txManager models.TransactionManager,
+	validator *validation.Validator,
+	auditor *Auditor,
+) *EventService {
 	return &EventService{
-		eventRepo: eventRepo,
-		validator: validator,
+		eventRepo:        eventRepo,
+		trackingPlanRepo: trackingPlanRepo,
+		txManager:        txManager,
+		validator:        validator,
+		auditor:          auditor,
 	}
 }
 
@@ -32,19 +54,30 @@ func (s *EventService) CreateEvent(req *dtos.CreateEventRequest) (*models.Event,
 		Description: req.Description,
 	}
 
-	if err := s.eventRepo.Create(event); err != nil {
-		return nil, fiber.NewError(fiber.StatusInternalServerError, "Failed to create event")
+	err := runInTransaction(s.txManager, func(tx *gorm.DB) error {
+		if err := tx.Create(event).Error; err != nil {
+			return fiber.NewError(fiber.StatusInternalServerError, "Failed to create event")
+		}
+		return s.auditor.Record(tx, models.AuditResourceEvent, event.ID, models.AuditActionCreate, nil, event)
+	})
+	if err != nil {
+		return nil, err
 	}
 
 	return event, nil
 }
 
-func (s *EventService) GetAllEvents() ([]models.Event, error) {
-	events, err := s.eventRepo.GetAll()
+func (s *EventService) ListEvents(query *dtos.ListQuery) (*dtos.PageResponse, error) {
+	opts, err := listOptionsFromQuery(s.validator, query, validation.ValidEventTypes)
+	if err != nil {
+		return nil, err
+	}
+
+	events, total, err := s.eventRepo.List(opts)
 	if err != nil {
 		return nil, fiber.NewError(fiber.StatusInternalServerError, "Failed to fetch events")
 	}
-	return events, nil
+	return buildPage(events, total, opts, eventListKey), nil
 }
 
 func (s *EventService) GetEventByID(id uint) (*models.Event, error) {
@@ -58,46 +91,163 @@ func (s *EventService) GetEventByID(id uint) (*models.Event, error) {
 	return event, nil
 }
 
-func (s *EventService) UpdateEvent(id uint, req *dtos.UpdateEventRequest) (*models.Event, error) {
-	if err := s.validator.ValidateUpdateEvent(req); err != nil {
+// GetEventUsages lists every tracking plan event that uses the event.
+func (s *EventService) GetEventUsages(id uint) ([]models.Usage, error) {
+	if _, err := s.GetEventByID(id); err != nil {
 		return nil, err
 	}
 
-	event, err := s.eventRepo.GetByID(id)
+	usages, err := s.trackingPlanRepo.GetEventUsages(id)
 	if err != nil {
-		if err == gorm.ErrRecordNotFound {
-			return nil, fiber.NewError(fiber.StatusNotFound, "Event not found")
-		}
-		return nil, fiber.NewError(fiber.StatusInternalServerError, "Failed to fetch event")
+		return nil, fiber.NewError(fiber.StatusInternalServerError, "Failed to fetch event usages")
 	}
+	if usages == nil {
+		usages = []models.Usage{}
+	}
+	return usages, nil
+}
+
+func (s *EventService) UpdateEvent(id uint, req *dtos.UpdateEventRequest, ifMatch []int) (*models.Event, error) {
+	if err := s.validator.ValidateUpdateEvent(req); err != nil {
+		return nil, err
+	}
+
+	var event *models.Event
+	err := runInTransaction(s.txManager, func(tx *gorm.DB) error {
+		var err error
+		if event, err = lockRow[models.Event](tx, id); err != nil {
+			if err == gorm.ErrRecordNotFound {
+				return fiber.NewError(fiber.StatusNotFound, "Event not found")
+			}
+			return fiber.NewError(fiber.StatusInternalServerError, "Failed to fetch event")
+		}
+		if err := checkIfMatch("Event", ifMatch, event.Version); err != nil {
+			return err
+		}
 
-	event.Name = req.Name
-	event.Type = req.Type
-	event.Description = req.Description
+		before := *event
+		event.Name = req.Name
+		event.Type = req.Type
+		event.Description = req.Description
 
-	if err := s.eventRepo.Update(event); err != nil {
-		return nil, fiber.NewError(fiber.StatusInternalServerError, "Failed to update event")
+		if err := saveLocked(tx, event, &event.Version); err != nil {
+			return fiber.NewError(fiber.StatusInternalServerError, "Failed to update event")
+		}
+		return s.auditor.Record(tx, models.AuditResourceEvent, id, models.AuditActionUpdate, &before, event)
+	})
+	if err != nil {
+		return nil, err
 	}
 
 	return event, nil
 }
 
-func (s *EventService) DeleteEvent(id uint) error {
-	if err := s.eventRepo.Delete(id); err != nil {
-		return fiber.NewError(fiber.StatusInternalServerError, "Failed to delete event")
+// PatchEvent applies a merge patch or JSON Patch to an event and saves the
+// result through UpdateEvent.
+func (s *EventService) PatchEvent(id uint, patch []byte, contentType string, ifMatch []int) (*models.Event, error) {
+	event, err := s.GetEventByID(id)
+	if err != nil {
+		return nil, err
 	}
-	return nil
+	precondition, err := patchPrecondition("Event", ifMatch, event.Version)
+	if err != nil {
+		return nil, err
+	}
+
+	var req dtos.UpdateEventRequest
+	if err := applyPatch(eventUpdateRequest(event), patch, contentType, &req); err != nil {
+		return nil, err
+	}
+	return s.UpdateEvent(id, &req, precondition)
+}
+
+// DeleteEvent soft deletes an event. It refuses while tracking plans still
+// include the event, unless cascade is set, in which case the event is removed
+// from those plans in the same transaction.
+func (s *EventService) DeleteEvent(id uint, cascade bool, deletedBy string, ifMatch []int) error {
+	return runInTransaction(s.txManager, func(tx *gorm.DB) error {
+		event, err := lockRow[models.Event](tx, id)
+		if err != nil {
+			if err == gorm.ErrRecordNotFound {
+				return fiber.NewError(fiber.StatusNotFound, "Event not found")
+			}
+			return fiber.NewError(fiber.StatusInternalServerError, "Failed to fetch event")
+		}
+		if err := checkIfMatch("Event", ifMatch, event.Version); err != nil {
+			return err
+		}
+
+		return deleteReferenced(tx, s.auditor, models.AuditResourceEvent, "Event", id, event, deletedBy, cascade, plansUsingEvent,
+			func(planIDs []uint) error {
+				err := tx.Where("event_id = ? AND tracking_plan_id IN ?", id, planIDs).Delete(&models.TrackingPlanEvent{}).Error
+				if err != nil {
+					return fiber.NewError(fiber.StatusInternalServerError, "Failed to detach event from tracking plans")
+				}
+				return nil
+			})
+	})
+}
+
+// RestoreEvent brings back a soft-deleted event, unless another event with the
+// same name and type has been created since.
+func (s *EventService) RestoreEvent(id uint) (*models.Event, error) {
+	var event *models.Event
+	err := runInTransaction(s.txManager, func(tx *gorm.DB) error {
+		var err error
+		if event, err = lockRow[models.Event](tx.Unscoped(), id); err != nil {
+			if err == gorm.ErrRecordNotFound {
+				return fiber.NewError(fiber.StatusNotFound, "Event not found")
+			}
+			return fiber.NewError(fiber.StatusInternalServerError, "Failed to fetch event")
+		}
+		if !event.DeletedAt.Valid {
+			return nil
+		}
+
+		var existing models.Event
+		if err := tx.Where("name = ? AND type = ?", event.Name, event.Type).First(&existing).Error; err == nil {
+			return fiber.NewError(fiber.StatusConflict, "Another event with the same name and type exists")
+		} else if err != gorm.ErrRecordNotFound {
+			return fiber.NewError(fiber.StatusInternalServerError, "Failed to fetch event")
+		}
+
+		if err := tx.Unscoped().Model(&models.Event{}).Where("id = ?", id).UpdateColumns(models.RestoreColumns()).Error; err != nil {
+			return fiber.NewError(fiber.StatusInternalServerError, "Failed to restore event")
+		}
+		var restored models.Event
+		if err := tx.First(&restored, id).Error; err != nil {
+			return fiber.NewError(fiber.StatusInternalServerError, "Failed to fetch event")
+		}
+		event = &restored
+		return s.auditor.Record(tx, models.AuditResourceEvent, id, models.AuditActionRestore, nil, event)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return event, nil
 }
 
 type PropertyService struct {
-	propertyRepo models.PropertyRepository
-	validator    *validation.Validator
+	propertyRepo     models.PropertyRepository
+	trackingPlanRepo models.TrackingPlanRepository
+	txManager        models.TransactionManager
+	validator        *validation.Validator
+	auditor          *Auditor
 }
 
-func NewPropertyService(propertyRepo models.PropertyRepository, validator *validation.Validator) *PropertyService {
+func NewPropertyService(
+	propertyRepo models.PropertyRepository,
+	trackingPlanRepo models.TrackingPlanRepository,
+	txManager models.TransactionManager,
+	validator *validation.Validator,
+	auditor *Auditor,
+) *PropertyService {
 	return &PropertyService{
-		propertyRepo: propertyRepo,
-		validator:    validator,
+		propertyRepo:     propertyRepo,
+		trackingPlanRepo: trackingPlanRepo,
+		txManager:        txManager,
+		validator:        validator,
+		auditor:          auditor,
 	}
 }
 
@@ -107,24 +257,38 @@ func (s *PropertyService) CreateProperty(req *dtos.CreatePropertyRequest) (*mode
 	}
 
 	property := &models.Property{
-		Name:        req.Name,
-		Type:        req.Type,
-		Description: req.Description,
+		Name:                req.Name,
+		Type:                req.Type,
+		Items:               req.Items,
+		Description:         req.Description,
+		PropertyConstraints: req.PropertyConstraints,
+		Properties:          req.Properties,
 	}
 
-	if err := s.propertyRepo.Create(property); err != nil {
-		return nil, fiber.NewError(fiber.StatusInternalServerError, "Failed to create property")
+	err := runInTransaction(s.txManager, func(tx *gorm.DB) error {
+		if err := tx.Create(property).Error; err != nil {
+			return fiber.NewError(fiber.StatusInternalServerError, "Failed to create property")
+		}
+		return s.auditor.Record(tx, models.AuditResourceProperty, property.ID, models.AuditActionCreate, nil, property)
+	})
+	if err != nil {
+		return nil, err
 	}
 
 	return property, nil
 }
 
-func (s *PropertyService) GetAllProperties() ([]models.Property, error) {
-	properties, err := s.propertyRepo.GetAll()
+func (s *PropertyService) ListProperties(query *dtos.ListQuery) (*dtos.PageResponse, error) {
+	opts, err := listOptionsFromQuery(s.validator, query, validation.ValidPropertyTypes)
+	if err != nil {
+		return nil, err
+	}
+
+	properties, total, err := s.propertyRepo.List(opts)
 	if err != nil {
 		return nil, fiber.NewError(fiber.StatusInternalServerError, "Failed to fetch properties")
 	}
-	return properties, nil
+	return buildPage(properties, total, opts, propertyListKey), nil
 }
 
 func (s *PropertyService) GetPropertyByID(id uint) (*models.Property, error) {
@@ -138,58 +302,175 @@ func (s *PropertyService) GetPropertyByID(id uint) (*models.Property, error) {
 	return property, nil
 }
 
-func (s *PropertyService) UpdateProperty(id uint, req *dtos.UpdatePropertyRequest) (*models.Property, error) {
-	if err := s.validator.ValidateUpdateProperty(req); err != nil {
+// GetPropertyUsages lists every tracking plan event that uses the property,
+// with the required flag each plan sets for it.
+func (s *PropertyService) GetPropertyUsages(id uint) ([]models.Usage, error) {
+	if _, err := s.GetPropertyByID(id); err != nil {
 		return nil, err
 	}
 
-	property, err := s.propertyRepo.GetByID(id)
+	usages, err := s.trackingPlanRepo.GetPropertyUsages(id)
 	if err != nil {
-		if err == gorm.ErrRecordNotFound {
-			return nil, fiber.NewError(fiber.StatusNotFound, "Property not found")
-		}
-		return nil, fiber.NewError(fiber.StatusInternalServerError, "Failed to fetch property")
+		return nil, fiber.NewError(fiber.StatusInternalServerError, "Failed to fetch property usages")
+	}
+	if usages == nil {
+		usages = []models.Usage{}
+	}
+	return usages, nil
+}
+
+func (s *PropertyService) UpdateProperty(id uint, req *dtos.UpdatePropertyRequest, ifMatch []int) (*models.Property, error) {
+	if err := s.validator.ValidateUpdateProperty(req); err != nil {
+		return nil, err
 	}
 
-	property.Name = req.Name
-	property.Type = req.Type
-	property.Description = req.Description
+	var property *models.Property
+	err := runInTransaction(s.txManager, func(tx *gorm.DB) error {
+		var err error
+		if property, err = lockRow[models.Property](tx, id); err != nil {
+			if err == gorm.ErrRecordNotFound {
+				return fiber.NewError(fiber.StatusNotFound, "Property not found")
+			}
+			return fiber.NewError(fiber.StatusInternalServerError, "Failed to fetch property")
+		}
+		if err := checkIfMatch("Property", ifMatch, property.Version); err != nil {
+			return err
+		}
+
+		before := *property
+		property.Name = req.Name
+		property.Type = req.Type
+		property.Items = req.Items
+		property.Description = req.Description
+		property.PropertyConstraints = req.PropertyConstraints
+		property.Properties = req.Properties
 
-	if err := s.propertyRepo.Update(property); err != nil {
-		return nil, fiber.NewError(fiber.StatusInternalServerError, "Failed to update property")
+		if err := saveLocked(tx, property, &property.Version); err != nil {
+			return fiber.NewError(fiber.StatusInternalServerError, "Failed to update property")
+		}
+		return s.auditor.Record(tx, models.AuditResourceProperty, id, models.AuditActionUpdate, &before, property)
+	})
+	if err != nil {
+		return nil, err
 	}
 
 	return property, nil
 }
 
-func (s *PropertyService) DeleteProperty(id uint) error {
-	if err := s.propertyRepo.Delete(id); err != nil {
-		return fiber.NewError(fiber.StatusInternalServerError, "Failed to delete property")
+// PatchProperty applies a merge patch or JSON Patch to a property and saves
+// the result through UpdateProperty.
+func (s *PropertyService) PatchProperty(id uint, patch []byte, contentType string, ifMatch []int) (*models.Property, error) {
+	property, err := s.GetPropertyByID(id)
+	if err != nil {
+		return nil, err
+	}
+	precondition, err := patchPrecondition("Property", ifMatch, property.Version)
+	if err != nil {
+		return nil, err
+	}
+
+	var req dtos.UpdatePropertyRequest
+	if err := applyPatch(propertyUpdateRequest(property), patch, contentType, &req); err != nil {
+		return nil, err
+	}
+	return s.UpdateProperty(id, &req, precondition)
+}
+
+// DeleteProperty soft deletes a property. It refuses while tracking plan
+// events still use the property, unless cascade is set, in which case the
+// property is removed from those events in the same transaction.
+func (s *PropertyService) DeleteProperty(id uint, cascade bool, deletedBy string, ifMatch []int) error {
+	return runInTransaction(s.txManager, func(tx *gorm.DB) error {
+		property, err := lockRow[models.Property](tx, id)
+		if err != nil {
+			if err == gorm.ErrRecordNotFound {
+				return fiber.NewError(fiber.StatusNotFound, "Property not found")
+			}
+			return fiber.NewError(fiber.StatusInternalServerError, "Failed to fetch property")
+		}
+		if err := checkIfMatch("Property", ifMatch, property.Version); err != nil {
+			return err
+		}
+
+		return deleteReferenced(tx, s.auditor, models.AuditResourceProperty, "Property", id, property, deletedBy, cascade, plansUsingProperty,
+			func(planIDs []uint) error {
+				planEvents := tx.Model(&models.TrackingPlanEvent{}).Select("id").Where("tracking_plan_id IN ?", planIDs)
+				err := tx.Where("property_id = ? AND tracking_plan_event_id IN (?)", id, planEvents).Delete(&models.TrackingPlanEventProperty{}).Error
+				if err != nil {
+					return fiber.NewError(fiber.StatusInternalServerError, "Failed to detach property from tracking plans")
+				}
+				return nil
+			})
+	})
+}
+
+// RestoreProperty brings back a soft-deleted property, unless another
+// property with the same name and type has been created since.
+func (s *PropertyService) RestoreProperty(id uint) (*models.Property, error) {
+	var property *models.Property
+	err := runInTransaction(s.txManager, func(tx *gorm.DB) error {
+		var err error
+		if property, err = lockRow[models.Property](tx.Unscoped(), id); err != nil {
+			if err == gorm.ErrRecordNotFound {
+				return fiber.NewError(fiber.StatusNotFound, "Property not found")
+			}
+			return fiber.NewError(fiber.StatusInternalServerError, "Failed to fetch property")
+		}
+		if !property.DeletedAt.Valid {
+			return nil
+		}
+
+		var existing models.Property
+		err = tx.Where("name = ? AND type = ? AND items = ?", property.Name, property.Type, property.Items).First(&existing).Error
+		if err == nil {
+			return fiber.NewError(fiber.StatusConflict, "Another property with the same name and type exists")
+		} else if err != gorm.ErrRecordNotFound {
+			return fiber.NewError(fiber.StatusInternalServerError, "Failed to fetch property")
+		}
+
+		if err := tx.Unscoped().Model(&models.Property{}).Where("id = ?", id).UpdateColumns(models.RestoreColumns()).Error; err != nil {
+			return fiber.NewError(fiber.StatusInternalServerError, "Failed to restore property")
+		}
+		var restored models.Property
+		if err := tx.First(&restored, id).Error; err != nil {
+			return fiber.NewError(fiber.StatusInternalServerError, "Failed to fetch property")
+		}
+		property = &restored
+		return s.auditor.Record(tx, models.AuditResourceProperty, id, models.AuditActionRestore, nil, property)
+	})
+	if err != nil {
+		return nil, err
 	}
-	return nil
+	return property, nil
 }
 
 type TrackingPlanService struct {
 	trackingPlanRepo models.TrackingPlanRepository
+	versionRepo      models.TrackingPlanVersionRepository
 	eventRepo        models.EventRepository
 	propertyRepo     models.PropertyRepository
 	txManager        models.TransactionManager
 	validator        *validation.Validator
+	auditor          *Auditor
 }
 
 func NewTrackingPlanService(
 	trackingPlanRepo models.TrackingPlanRepository,
+	versionRepo models.TrackingPlanVersionRepository,
 	eventRepo models.EventRepository,
 	propertyRepo models.PropertyRepository,
 	txManager models.TransactionManager,
 	validator *validation.Validator,
+	auditor *Auditor,
 ) *TrackingPlanService {
 	return &TrackingPlanService{
 		trackingPlanRepo: trackingPlanRepo,
+		versionRepo:      versionRepo,
 		eventRepo:        eventRepo,
 		propertyRepo:     propertyRepo,
 		txManager:        txManager,
 		validator:        validator,
+		auditor:          auditor,
 	}
 }
 
@@ -198,63 +479,28 @@ func (s *TrackingPlanService) CreateTrackingPlan(req *dtos.CreateTrackingPlanReq
 		return nil, err
 	}
 
-	tx := s.txManager.BeginTransaction()
-	defer func() {
-		if r := recover(); r != nil {
-			tx.Rollback()
-		}
-	}()
-
 	trackingPlan := &models.TrackingPlan{
 		Name:        req.Name,
 		Description: req.Description,
 	}
 
-	if err := tx.Create(trackingPlan).Error; err != nil {
-		tx.Rollback()
-		return nil, fiber.NewError(fiber.StatusInternalServerError, "Failed to create tracking plan")
-	}
-
-	for _, eventReq := range req.Events {
-		event, err := s.findOrCreateEvent(tx, eventReq.Name, eventReq.Type, eventReq.Description)
-		if err != nil {
-			tx.Rollback()
-			return nil, err
-		}
-
-		trackingPlanEvent := &models.TrackingPlanEvent{
-			TrackingPlanID:       trackingPlan.ID,
-			EventID:              event.ID,
-			AdditionalProperties: eventReq.AdditionalProperties,
+	err := runInTransaction(s.txManager, func(tx *gorm.DB) error {
+		if err := tx.Create(trackingPlan).Error; err != nil {
+			return fiber.NewError(fiber.StatusInternalServerError, "Failed to create tracking plan")
 		}
 
-		if err := tx.Create(trackingPlanEvent).Error; err != nil {
-			tx.Rollback()
-			return nil, fiber.NewError(fiber.StatusInternalServerError, "Failed to create tracking plan event")
+		if err := s.syncTrackingPlanEvents(tx, trackingPlan.ID, req.Events); err != nil {
+			return err
 		}
 
-		for _, propReq := range eventReq.Properties {
-			property, err := s.findOrCreateProperty(tx, propReq.Name, propReq.Type, propReq.Description)
-			if err != nil {
-				tx.Rollback()
-				return nil, err
-			}
-
-			trackingPlanEventProperty := &models.TrackingPlanEventProperty{
-				TrackingPlanEventID: trackingPlanEvent.ID,
-				PropertyID:          property.ID,
-				Required:            propReq.Required,
-			}
-
-			if err := tx.Create(trackingPlanEventProperty).Error; err != nil {
-				tx.Rollback()
-				return nil, fiber.NewError(fiber.StatusInternalServerError, "Failed to create tracking plan event property")
-			}
+		after, err := recordTrackingPlanVersion(tx, trackingPlan.ID)
+		if err != nil {
+			return err
 		}
-	}
-
-	if err := tx.Commit().Error; err != nil {
-		return nil, fiber.NewError(fiber.StatusInternalServerError, "Failed to commit transaction")
+		return s.auditor.Record(tx, models.AuditResourceTrackingPlan, trackingPlan.ID, models.AuditActionCreate, nil, after)
+	})
+	if err != nil {
+		return nil, err
 	}
 
 	result, err := s.trackingPlanRepo.GetByID(trackingPlan.ID)
@@ -265,12 +511,17 @@ func (s *TrackingPlanService) CreateTrackingPlan(req *dtos.CreateTrackingPlanReq
 	return result, nil
 }
 
-func (s *TrackingPlanService) GetAllTrackingPlans() ([]models.TrackingPlan, error) {
-	plans, err := s.trackingPlanRepo.GetAll()
+func (s *TrackingPlanService) ListTrackingPlans(query *dtos.ListQuery) (*dtos.PageResponse, error) {
+	opts, err := listOptionsFromQuery(s.validator, query, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	plans, total, err := s.trackingPlanRepo.List(opts)
 	if err != nil {
 		return nil, fiber.NewError(fiber.StatusInternalServerError, "Failed to fetch tracking plans")
 	}
-	return plans, nil
+	return buildPage(plans, total, opts, trackingPlanListKey), nil
 }
 
 func (s *TrackingPlanService) GetTrackingPlanByID(id uint) (*models.TrackingPlan, error) {
@@ -284,99 +535,480 @@ func (s *TrackingPlanService) GetTrackingPlanByID(id uint) (*models.TrackingPlan
 	return plan, nil
 }
 
-func (s *TrackingPlanService) UpdateTrackingPlan(id uint, req *dtos.UpdateTrackingPlanRequest) (*models.TrackingPlan, error) {
+func (s *TrackingPlanService) UpdateTrackingPlan(id uint, req *dtos.UpdateTrackingPlanRequest, ifMatch []int) (*models.TrackingPlan, error) {
 	if err := s.validator.ValidateUpdateTrackingPlan(req); err != nil {
 		return nil, err
 	}
 
-	tx := s.txManager.BeginTransaction()
-	defer func() {
-		if r := recover(); r != nil {
-			tx.Rollback()
+	return s.modifyTrackingPlan(id, ifMatch, func(tx *gorm.DB, plan *models.TrackingPlan) error {
+		err := tx.Model(&models.TrackingPlan{}).
+			Where("id = ?", plan.ID).
+			Updates(map[string]interface{}{"name": req.Name, "description": req.Description}).Error
+		if err != nil {
+			return fiber.NewError(fiber.StatusInternalServerError, "Failed to update tracking plan")
 		}
-	}()
+		return s.syncTrackingPlanEvents(tx, plan.ID, req.Events)
+	})
+}
 
-	trackingPlan, err := s.trackingPlanRepo.GetByID(id)
+// PatchTrackingPlan applies a merge patch or JSON Patch to the request form of
+// a tracking plan (name, description and events) and saves the result
+// through UpdateTrackingPlan.
+func (s *TrackingPlanService) PatchTrackingPlan(id uint, patch []byte, contentType string, ifMatch []int) (*models.TrackingPlan, error) {
+	plan, err := s.GetTrackingPlanByID(id)
 	if err != nil {
-		if err == gorm.ErrRecordNotFound {
-			return nil, fiber.NewError(fiber.StatusNotFound, "Tracking plan not found")
-		}
-		return nil, fiber.NewError(fiber.StatusInternalServerError, "Failed to fetch tracking plan")
+		return nil, err
 	}
-
-	trackingPlan.Name = req.Name
-	trackingPlan.Description = req.Description
-
-	if err := tx.Save(trackingPlan).Error; err != nil {
-		tx.Rollback()
-		return nil, fiber.NewError(fiber.StatusInternalServerError, "Failed to update tracking plan")
+	precondition, err := patchPrecondition("Tracking plan", ifMatch, plan.Version)
+	if err != nil {
+		return nil, err
 	}
 
-	if err := tx.Where("tracking_plan_id = ?", trackingPlan.ID).Delete(&models.TrackingPlanEvent{}).Error; err != nil {
-		tx.Rollback()
-		return nil, fiber.NewError(fiber.StatusInternalServerError, "Failed to delete existing events")
+	var req dtos.UpdateTrackingPlanRequest
+	if err := applyPatch(trackingPlanUpdateRequest(plan), patch, contentType, &req); err != nil {
+		return nil, err
 	}
+	return s.UpdateTrackingPlan(id, &req, precondition)
+}
 
-	for _, eventReq := range req.Events {
-		event, err := s.findOrCreateEvent(tx, eventReq.Name, "track", eventReq.Description)
+// DeleteTrackingPlan soft deletes a tracking plan. The plan is locked while
+// the If-Match precondition is checked, so a concurrent update cannot land
+// between the check and the delete.
+func (s *TrackingPlanService) DeleteTrackingPlan(id uint, deletedBy string, ifMatch []int) error {
+	return runInTransaction(s.txManager, func(tx *gorm.DB) error {
+		plan, err := lockRow[models.TrackingPlan](tx, id)
 		if err != nil {
-			tx.Rollback()
-			return nil, err
+			if err == gorm.ErrRecordNotFound {
+				return fiber.NewError(fiber.StatusNotFound, "Tracking plan not found")
+			}
+			return fiber.NewError(fiber.StatusInternalServerError, "Failed to fetch tracking plan")
 		}
-
-		trackingPlanEvent := &models.TrackingPlanEvent{
-			TrackingPlanID:       trackingPlan.ID,
-			EventID:              event.ID,
-			AdditionalProperties: eventReq.AdditionalProperties,
+		if err := checkIfMatch("Tracking plan", ifMatch, plan.Version); err != nil {
+			return err
+		}
+		if plan.Events, err = loadTrackingPlanEvents(tx, plan.ID); err != nil {
+			return err
 		}
 
-		if err := tx.Create(trackingPlanEvent).Error; err != nil {
-			tx.Rollback()
-			return nil, fiber.NewError(fiber.StatusInternalServerError, "Failed to create tracking plan event")
+		if err := tx.Model(&models.TrackingPlan{}).Where("id = ?", id).UpdateColumns(models.DeletionColumns(deletedBy)).Error; err != nil {
+			return fiber.NewError(fiber.StatusInternalServerError, "Failed to delete tracking plan")
 		}
+		return s.auditor.Record(tx, models.AuditResourceTrackingPlan, id, models.AuditActionDelete, plan, nil)
+	})
+}
 
-		for _, propReq := range eventReq.Properties {
-			property, err := s.findOrCreateProperty(tx, propReq.Name, propReq.Type, propReq.Description)
-			if err != nil {
-				tx.Rollback()
-				return nil, err
+// RestoreTrackingPlan brings back a soft-deleted tracking plan. The plan's
+// name must still be free, and every event and property it uses must exist.
+func (s *TrackingPlanService) RestoreTrackingPlan(id uint) (*models.TrackingPlan, error) {
+	var plan models.TrackingPlan
+	err := runInTransaction(s.txManager, func(tx *gorm.DB) error {
+		if _, err := lockRow[models.TrackingPlan](tx.Unscoped(), id); err != nil {
+			if err == gorm.ErrRecordNotFound {
+				return fiber.NewError(fiber.StatusNotFound, "Tracking plan not found")
 			}
+			return fiber.NewError(fiber.StatusInternalServerError, "Failed to fetch tracking plan")
+		}
+		err := tx.Unscoped().Preload("Events.Event").Preload("Events.Properties.Property").First(&plan, id).Error
+		if err != nil {
+			return fiber.NewError(fiber.StatusInternalServerError, "Failed to fetch tracking plan")
+		}
+		if !plan.DeletedAt.Valid {
+			return nil
+		}
 
-			trackingPlanEventProperty := &models.TrackingPlanEventProperty{
-				TrackingPlanEventID: trackingPlanEvent.ID,
-				PropertyID:          property.ID,
-				Required:            propReq.Required,
-			}
+		var existing models.TrackingPlan
+		if err := tx.Where("name = ?", plan.Name).First(&existing).Error; err == nil {
+			return fiber.NewError(fiber.StatusConflict, "Another tracking plan with the same name exists")
+		} else if err != gorm.ErrRecordNotFound {
+			return fiber.NewError(fiber.StatusInternalServerError, "Failed to fetch tracking plan")
+		}
 
-			if err := tx.Create(trackingPlanEventProperty).Error; err != nil {
-				tx.Rollback()
-				return nil, fiber.NewError(fiber.StatusInternalServerError, "Failed to create tracking plan event property")
+		deleted := make([]string, 0)
+		seen := make(map[string]bool)
+		for _, planEvent := range plan.Events {
+			if planEvent.Event.DeletedAt.Valid {
+				deleted = append(deleted, fmt.Sprintf("event '%s'", planEvent.Event.Name))
+			}
+			for _, planProp := range planEvent.Properties {
+				label := fmt.Sprintf("property '%s'", planProp.Property.Name)
+				if planProp.Property.DeletedAt.Valid && !seen[label] {
+					seen[label] = true
+					deleted = append(deleted, label)
+				}
 			}
 		}
+		if len(deleted) > 0 {
+			return fiber.NewError(fiber.StatusConflict,
+				fmt.Sprintf("Tracking plan uses deleted %s; restore them first", strings.Join(deleted, ", ")))
+		}
+
+		if err := tx.Unscoped().Model(&models.TrackingPlan{}).Where("id = ?", id).UpdateColumns(models.RestoreColumns()).Error; err != nil {
+			return fiber.NewError(fiber.StatusInternalServerError, "Failed to restore tracking plan")
+		}
+		plan = models.TrackingPlan{}
+		if err := tx.Preload("Events.Event").Preload("Events.Properties.Property").First(&plan, id).Error; err != nil {
+			return fiber.NewError(fiber.StatusInternalServerError, "Failed to fetch tracking plan")
+		}
+		return s.auditor.Record(tx, models.AuditResourceTrackingPlan, id, models.AuditActionRestore, nil, &plan)
+	})
+	if err != nil {
+		return nil, err
 	}
+	return &plan, nil
+}
 
-	if err := tx.Commit().Error; err != nil {
-		return nil, fiber.NewError(fiber.StatusInternalServerError, "Failed to commit transaction")
+func (s *TrackingPlanService) GetTrackingPlanVersions(id uint) ([]dtos.TrackingPlanVersionSummary, error) {
+	if _, err := s.GetTrackingPlanByID(id); err != nil {
+		return nil, err
 	}
 
-	result, err := s.trackingPlanRepo.GetByID(trackingPlan.ID)
+	versions, err := s.versionRepo.GetByTrackingPlanID(id)
 	if err != nil {
-		return nil, fiber.NewError(fiber.StatusInternalServerError, "Failed to fetch updated tracking plan")
+		return nil, fiber.NewError(fiber.StatusInternalServerError, "Failed to fetch tracking plan versions")
 	}
 
-	return result, nil
+	summaries := make([]dtos.TrackingPlanVersionSummary, 0, len(versions))
+	for _, version := range versions {
+		summaries = append(summaries, dtos.TrackingPlanVersionSummary{
+			TrackingPlanID: version.TrackingPlanID,
+			Version:        version.Version,
+			CreateTime:     version.CreateTime,
+		})
+	}
+	return summaries, nil
 }
 
-func (s *TrackingPlanService) DeleteTrackingPlan(id uint) error {
-	if err := s.trackingPlanRepo.Delete(id); err != nil {
-		return fiber.NewError(fiber.StatusInternalServerError, "Failed to delete tracking plan")
+func (s *TrackingPlanService) GetTrackingPlanVersion(id uint, version int) (*models.TrackingPlanVersion, error) {
+	// Versions are not owned by a workspace themselves; the plan lookup keeps
+	// other workspaces' history out of reach.
+	if _, err := s.GetTrackingPlanByID(id); err != nil {
+		return nil, err
 	}
-	return nil
+
+	planVersion, err := s.versionRepo.GetByVersion(id, version)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, fiber.NewError(fiber.StatusNotFound, "Tracking plan version not found")
+		}
+		return nil, fiber.NewError(fiber.StatusInternalServerError, "Failed to fetch tracking plan version")
+	}
+	return planVersion, nil
+}
+
+// CompareTrackingPlans diffs two tracking plans, or two versions of the same
+// plan. A version of 0 selects the plan's current state.
+func (s *TrackingPlanService) CompareTrackingPlans(baseID uint, baseVersion int, headID uint, headVersion int) (*dtos.TrackingPlanDiffResponse, error) {
+	base, err := s.resolveTrackingPlan(baseID, baseVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	head, err := s.resolveTrackingPlan(headID, headVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	return &dtos.TrackingPlanDiffResponse{
+		Base:    dtos.TrackingPlanRef{TrackingPlanID: base.ID, Name: base.Name, Version: base.Version},
+		Head:    dtos.TrackingPlanRef{TrackingPlanID: head.ID, Name: head.Name, Version: head.Version},
+		Changes: DiffTrackingPlans(base, head),
+	}, nil
+}
+
+func (s *TrackingPlanService) resolveTrackingPlan(id uint, version int) (*models.TrackingPlan, error) {
+	if version == 0 {
+		return s.GetTrackingPlanByID(id)
+	}
+
+	planVersion, err := s.GetTrackingPlanVersion(id, version)
+	if err != nil {
+		return nil, err
+	}
+	return &planVersion.Snapshot, nil
+}
+
+// recordTrackingPlanVersion bumps the plan's version counter and stores an
+// immutable snapshot of its current events and properties, which it returns.
+// It must run inside the transaction that changed the plan so the snapshot
+// matches the commit. The plan row is locked before its version is read, so
+// concurrent changes take turns instead of both claiming the same version
+// number.
+func recordTrackingPlanVersion(tx *gorm.DB, trackingPlanID uint) (*models.TrackingPlan, error) {
+	var plan models.TrackingPlan
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&plan, trackingPlanID).Error; err != nil {
+		return nil, fiber.NewError(fiber.StatusInternalServerError, "Failed to load tracking plan for versioning")
+	}
+	events, err := loadTrackingPlanEvents(tx, plan.ID)
+	if err != nil {
+		return nil, err
+	}
+	plan.Events = events
+
+	plan.Version++
+	if err := tx.Model(&plan).UpdateColumn("version", plan.Version).Error; err != nil {
+		return nil, fiber.NewError(fiber.StatusInternalServerError, "Failed to update tracking plan version")
+	}
+
+	version := &models.TrackingPlanVersion{
+		TrackingPlanID: plan.ID,
+		Version:        plan.Version,
+		Snapshot:       plan,
+	}
+	if err := tx.Create(version).Error; err != nil {
+		return nil, fiber.NewError(fiber.StatusInternalServerError, "Failed to create tracking plan version")
+	}
+	return &plan, nil
+}
+
+func (s *TrackingPlanService) ValidatePayload(id uint, req *dtos.ValidatePayloadRequest) (*dtos.ValidatePayloadResponse, error) {
+	if err := s.validator.ValidatePayloadRequest(req); err != nil {
+		return nil, err
+	}
+
+	plan, err := s.GetTrackingPlanByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	eventName := req.Event
+	if eventName == "" {
+		eventName = req.Name
+	}
+
+	var planEvent *models.TrackingPlanEvent
+	for i := range plan.Events {
+		candidate := &plan.Events[i]
+		if candidate.Event.Type != req.Type {
+			continue
+		}
+		if eventName == "" || candidate.Event.Name == eventName {
+			planEvent = candidate
+			break
+		}
+	}
+
+	if planEvent == nil {
+		return &dtos.ValidatePayloadResponse{
+			Valid: false,
+			Event: eventName,
+			Violations: []dtos.PayloadViolation{{
+				Code:    validation.ViolationUnknownEvent,
+				Path:    "event",
+				Message: fmt.Sprintf("event '%s' of type '%s' is not part of the tracking plan", eventName, req.Type),
+			}},
+		}, nil
+	}
+
+	fields := req.Properties
+	if models.PayloadFieldFor(req.Type) == "traits" {
+		fields = req.Traits
+	}
+
+	violations := s.validator.ValidateEventPayload(planEvent, fields)
+	return &dtos.ValidatePayloadResponse{
+		Valid:      len(violations) == 0,
+		Event:      planEvent.Event.Name,
+		Violations: violations,
+	}, nil
+}
+
+func (s *TrackingPlanService) ExportJSONSchema(id uint, draft string) (*dtos.TrackingPlanSchemaResponse, error) {
+	if draft == "" {
+		draft = jsonschema.Draft07
+	}
+	if _, ok := jsonschema.DraftURI(draft); !ok {
+		return nil, fiber.NewError(fiber.StatusBadRequest,
+			fmt.Sprintf("unsupported draft '%s'. Must be one of: %s, %s", draft, jsonschema.Draft07, jsonschema.Draft202012))
+	}
+
+	plan, err := s.GetTrackingPlanByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &dtos.TrackingPlanSchemaResponse{
+		TrackingPlanID: plan.ID,
+		Name:           plan.Name,
+		Draft:          draft,
+		Events:         make([]dtos.EventSchema, 0, len(plan.Events)),
+	}
+	for i := range plan.Events {
+		planEvent := &plan.Events[i]
+		response.Events = append(response.Events, dtos.EventSchema{
+			Name:   planEvent.Event.Name,
+			Type:   planEvent.Event.Type,
+			Schema: jsonschema.FromTrackingPlanEvent(planEvent, draft),
+		})
+	}
+
+	return response, nil
+}
+
+// ImportJSONSchema creates the named tracking plan from one JSON Schema
+// document per event, or replaces its events when the plan already exists.
+// The returned flag reports whether a new plan was created.
+func (s *TrackingPlanService) ImportJSONSchema(req *dtos.ImportTrackingPlanSchemaRequest) (*models.TrackingPlan, bool, error) {
+	if err := s.validator.ValidateImportTrackingPlanSchema(req); err != nil {
+		return nil, false, err
+	}
+
+	events := make([]dtos.TrackingPlanEventRequest, 0, len(req.Schemas))
+	for i, schema := range req.Schemas {
+		eventReq, err := eventRequestFromSchema(schema)
+		if err != nil {
+			return nil, false, fiber.NewError(fiber.StatusBadRequest, fmt.Sprintf("schemas[%d]: %s", i, err.Error()))
+		}
+		events = append(events, *eventReq)
+	}
+
+	return s.importTrackingPlan(&dtos.CreateTrackingPlanRequest{
+		Name:        req.Name,
+		Description: req.Description,
+		Events:      events,
+	})
+}
+
+// ExportSpreadsheet renders a tracking plan as a CSV or XLSX spreadsheet with
+// one row per event/property pair. XLSX is the default format.
+func (s *TrackingPlanService) ExportSpreadsheet(id uint, format string) (*spreadsheet.File, error) {
+	if format == "" {
+		format = spreadsheet.FormatXLSX
+	}
+	if format != spreadsheet.FormatCSV && format != spreadsheet.FormatXLSX {
+		return nil, fiber.NewError(fiber.StatusBadRequest,
+			fmt.Sprintf("unsupported format '%s'. Must be one of: %s, %s", format, spreadsheet.FormatCSV, spreadsheet.FormatXLSX))
+	}
+
+	plan, err := s.GetTrackingPlanByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	file, err := spreadsheet.Export(plan, format)
+	if err != nil {
+		return nil, fiber.NewError(fiber.StatusInternalServerError, "Failed to export tracking plan")
+	}
+	return file, nil
+}
+
+// ImportSpreadsheet creates the named tracking plan from a CSV or XLSX
+// spreadsheet, or replaces its events when the plan already exists. The
+// returned flag reports whether a new plan was created.
+func (s *TrackingPlanService) ImportSpreadsheet(name, description string, body []byte, contentType string) (*models.TrackingPlan, bool, error) {
+	if contentType != spreadsheet.CSVContentType && contentType != spreadsheet.XLSXContentType {
+		return nil, false, fiber.NewError(fiber.StatusUnsupportedMediaType,
+			fmt.Sprintf("Content-Type must be %s or %s", spreadsheet.CSVContentType, spreadsheet.XLSXContentType))
+	}
+
+	records, err := spreadsheet.Read(body, contentType)
+	if err != nil {
+		return nil, false, fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+	events, err := spreadsheet.Events(records)
+	if err != nil {
+		return nil, false, fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+
+	return s.importTrackingPlan(&dtos.CreateTrackingPlanRequest{
+		Name:        name,
+		Description: description,
+		Events:      events,
+	})
+}
+
+// importTrackingPlan creates the plan in req, or replaces the events of the
+// plan that already has its name.
+func (s *TrackingPlanService) importTrackingPlan(req *dtos.CreateTrackingPlanRequest) (*models.TrackingPlan, bool, error) {
+	existing, err := s.trackingPlanRepo.GetByName(req.Name)
+	if err != nil && err != gorm.ErrRecordNotFound {
+		return nil, false, fiber.NewError(fiber.StatusInternalServerError, "Failed to fetch tracking plan")
+	}
+
+	if existing != nil {
+		plan, err := s.UpdateTrackingPlan(existing.ID, (*dtos.UpdateTrackingPlanRequest)(req), nil)
+		return plan, false, err
+	}
+
+	plan, err := s.CreateTrackingPlan(req)
+	return plan, true, err
+}
+
+func eventRequestFromSchema(schema *jsonschema.Schema) (*dtos.TrackingPlanEventRequest, error) {
+	// A property written as null decodes to a nil schema.
+	for propName, propSchema := range schema.Properties {
+		if propSchema == nil {
+			return nil, fmt.Errorf("property '%s' has an empty schema", propName)
+		}
+	}
+
+	eventType := "track"
+	if typeSchema, ok := schema.Properties["type"]; ok {
+		if value, ok := typeSchema.Const.(string); ok && value != "" {
+			eventType = value
+		}
+	}
+
+	name := schema.Title
+	if nameField := models.EventNameFieldFor(eventType); name == "" && nameField != "" {
+		if nameSchema, ok := schema.Properties[nameField]; ok {
+			name, _ = nameSchema.Const.(string)
+		}
+	}
+	if name == "" {
+		return nil, fmt.Errorf("event name is missing; set title or a const %s", models.EventNameFieldFor(eventType))
+	}
+
+	eventReq := &dtos.TrackingPlanEventRequest{
+		Name:                 name,
+		Type:                 eventType,
+		Description:          schema.Description,
+		AdditionalProperties: true,
+		Properties:           []dtos.TrackingPlanPropertyRequest{},
+	}
+
+	fields, ok := schema.Properties[models.PayloadFieldFor(eventType)]
+	if !ok {
+		return eventReq, nil
+	}
+	if fields.AdditionalProperties != nil {
+		eventReq.AdditionalProperties = *fields.AdditionalProperties
+	}
+
+	required := make(map[string]bool, len(fields.Required))
+	for _, propName := range fields.Required {
+		required[propName] = true
+	}
+
+	names := make([]string, 0, len(fields.Properties))
+	for propName := range fields.Properties {
+		names = append(names, propName)
+	}
+	sort.Strings(names)
+
+	for _, propName := range names {
+		field, err := jsonschema.ToPropertyField(propName, fields.Properties[propName], required[propName])
+		if err != nil {
+			return nil, err
+		}
+		eventReq.Properties = append(eventReq.Properties, dtos.TrackingPlanPropertyRequest{
+			Name:                propName,
+			Type:                field.Type,
+			Items:               field.Items,
+			Required:            field.Required,
+			Description:         field.Description,
+			PropertyConstraints: field.PropertyConstraints,
+			Properties:          field.Properties,
+		})
+	}
+
+	return eventReq, nil
 }
 
 func (s *TrackingPlanService) findOrCreateEvent(tx *gorm.DB, name, eventType, description string) (*models.Event, error) {
 	var event models.Event
-	if err := tx.Where("name = ? AND type = ?", name, eventType).First(&event).Error; err != nil {
+	// The share lock keeps the event from being deleted until the plan that
+	// now references it has committed.
+	if err := tx.Clauses(clause.Locking{Strength: "SHARE"}).Where("name = ? AND type = ?", name, eventType).First(&event).Error; err != nil {
 		if err == gorm.ErrRecordNotFound {
 			event = models.Event{
 				Name:        name,
@@ -397,18 +1029,24 @@ func (s *TrackingPlanService) findOrCreateEvent(tx *gorm.DB, name, eventType, de
 	return &event, nil
 }
 
-func (s *TrackingPlanService) findOrCreateProperty(tx *gorm.DB, name, propertyType, description string) (*models.Property, error) {
-	if !validation.ValidPropertyTypes[propertyType] {
-		return nil, fiber.NewError(fiber.StatusBadRequest, "Invalid property type. Must be one of: string, number, boolean")
+func (s *TrackingPlanService) findOrCreateProperty(tx *gorm.DB, propReq *dtos.TrackingPlanPropertyRequest) (*models.Property, error) {
+	if err := s.validator.ValidatePropertyType("property type", propReq.Type, propReq.Items); err != nil {
+		return nil, err
 	}
 
+	description := propReq.Description
 	var property models.Property
-	if err := tx.Where("name = ? AND type = ?", name, propertyType).First(&property).Error; err != nil {
+	err := tx.Clauses(clause.Locking{Strength: "SHARE"}).
+		Where("name = ? AND type = ? AND items = ?", propReq.Name, propReq.Type, propReq.Items).
+		First(&property).Error
+	if err != nil {
 		if err == gorm.ErrRecordNotFound {
 			property = models.Property{
-				Name:        name,
-				Type:        propertyType,
+				Name:        propReq.Name,
+				Type:        propReq.Type,
+				Items:       propReq.Items,
 				Description: description,
+				Properties:  propReq.Properties,
 			}
 			if err := tx.Create(&property).Error; err != nil {
 				return nil, fiber.NewError(fiber.StatusInternalServerError, "Failed to create property")
@@ -420,6 +1058,80 @@ func (s *TrackingPlanService) findOrCreateProperty(tx *gorm.DB, name, propertyTy
 		if property.Description != description && description != "" {
 			return nil, fiber.NewError(fiber.StatusConflict, "Property exists with different description")
 		}
+		if len(propReq.Properties) > 0 && !samePropertyFields(property.Properties, propReq.Properties) {
+			return nil, fiber.NewError(fiber.StatusConflict, "Property exists with different child properties")
+		}
 	}
 	return &property, nil
 }
+
+// samePropertyFields compares child definitions by their JSON form.
+func samePropertyFields(a, b []models.PropertyField) bool {
+	aJSON, aErr := json.Marshal(a)
+	bJSON, bErr := json.Marshal(b)
+	return aErr == nil && bErr == nil && bytes.Equal(aJSON, bJSON)
+}
+
+type SearchService struct {
+	searchRepo models.SearchRepository
+	validator  *validation.Validator
+}
+
+func NewSearchService(searchRepo models.SearchRepository, validator *validation.Validator) *SearchService {
+	return &SearchService{
+		searchRepo: searchRepo,
+		validator:  validator,
+	}
+}
+
+func (s *SearchService) Search(query *dtos.SearchQuery) (*dtos.SearchResponse, error) {
+	if err := s.validator.ValidateSearchQuery(query); err != nil {
+		return nil, err
+	}
+
+	kinds := []string{models.SearchKindEvent, models.SearchKindProperty, models.SearchKindTrackingPlan}
+	if query.Types != "" {
+		kinds = kinds[:0]
+		for _, kind := range strings.Split(query.Types, ",") {
+			kinds = append(kinds, strings.TrimSpace(kind))
+		}
+	}
+
+	limit := query.Limit
+	if limit == 0 {
+		limit = validation.DefaultSearchLimit
+	}
+
+	response := &dtos.SearchResponse{
+		Query:   query.Q,
+		Results: []models.SearchResult{},
+	}
+
+	tsQuery := prefixTSQuery(query.Q)
+	if tsQuery == "" {
+		return response, nil
+	}
+
+	results, err := s.searchRepo.Search(tsQuery, kinds, limit)
+	if err != nil {
+		return nil, fiber.NewError(fiber.StatusInternalServerError, "Failed to search catalog")
+	}
+	if results != nil {
+		response.Results = results
+	}
+	return response, nil
+}
+
+// prefixTSQuery turns free text into a Postgres tsquery that matches every
+// word as a prefix, so "check" finds "Checkout Started". Anything other than
+// letters and digits is treated as a separator and never reaches the query.
+func prefixTSQuery(text string) string {
+	words := strings.FieldsFunc(text, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	terms := make([]string, 0, len(words))
+	for _, word := range words {
+		terms = append(terms, strings.ToLower(word)+":*")
+	}
+	return strings.Join(terms, " & ")
+}
@@ -0,0 +1,55 @@
+package services
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/shivamrajput1826/api-catalog/internal/jsonschema"
+)
+
+func TestEventRequestFromSchemaRejectsNullProperties(t *testing.T) {
+	documents := []string{
+		`{"title": "Signed Up", "properties": {"type": null}}`,
+		`{"properties": {"type": {"const": "track"}, "event": null}}`,
+		`{"title": "Signed Up", "properties": {"properties": null}}`,
+	}
+	for _, document := range documents {
+		var schema jsonschema.Schema
+		if err := json.Unmarshal([]byte(document), &schema); err != nil {
+			t.Fatalf("%s: %v", document, err)
+		}
+		if _, err := eventRequestFromSchema(&schema); err == nil {
+			t.Errorf("%s: expected an error", document)
+		}
+	}
+}
+
+func TestEventRequestFromSchema(t *testing.T) {
+	document := `{
+		"properties": {
+			"type": {"const": "track"},
+			"event": {"const": "Signed Up"},
+			"properties": {
+				"type": "object",
+				"additionalProperties": false,
+				"required": ["plan"],
+				"properties": {"plan": {"type": "string"}}
+			}
+		}
+	}`
+	var schema jsonschema.Schema
+	if err := json.Unmarshal([]byte(document), &schema); err != nil {
+		t.Fatal(err)
+	}
+
+	eventReq, err := eventRequestFromSchema(&schema)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if eventReq.Name != "Signed Up" || eventReq.Type != "track" || eventReq.AdditionalProperties {
+		t.Fatalf("unexpected event %+v", eventReq)
+	}
+	if len(eventReq.Properties) != 1 || eventReq.Properties[0].Name != "plan" || !eventReq.Properties[0].Required {
+		t.Fatalf("unexpected properties %+v", eventReq.Properties)
+	}
+}
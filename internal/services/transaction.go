@@ -0,0 +1,51 @@
+package services
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/shivamrajput1826/api-catalog/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// runInTransaction runs fn in a transaction, which is committed when fn
+// returns nil and rolled back otherwise.
+func runInTransaction(txManager models.TransactionManager, fn func(tx *gorm.DB) error) error {
+	tx := txManager.BeginTransaction()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+			panic(r)
+		}
+	}()
+
+	if err := fn(tx); err != nil {
+		tx.Rollback()
+		return err
+	}
+	if err := tx.Commit().Error; err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, "Failed to commit transaction")
+	}
+	return nil
+}
+
+// lockRow loads a live row and locks it until the end of tx, so the version
+// checked against If-Match and the before state written to the audit log are
+// exactly what the change replaces. It returns gorm.ErrRecordNotFound when
+// there is no such row.
+func lockRow[T any](tx *gorm.DB, id uint) (*T, error) {
+	var row T
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&row, id).Error; err != nil {
+		return nil, err
+	}
+	return &row, nil
+}
+
+// saveLocked writes every column of a row locked by lockRow and bumps its
+// version.
+func saveLocked(tx *gorm.DB, model interface{}, version *int) error {
+	*version++
+	return tx.Model(model).
+		Select("*").
+		Omit("id", "workspace_id", "create_time", "deleted_at", "deleted_by").
+		Updates(model).Error
+}
@@ -0,0 +1,33 @@
+package services
+
+import (
+	"sync"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/shivamrajput1826/api-catalog/internal/models"
+)
+
+type WorkspaceService struct {
+	workspaceRepo models.WorkspaceRepository
+	ids           sync.Map
+}
+
+func NewWorkspaceService(workspaceRepo models.WorkspaceRepository) *WorkspaceService {
+	return &WorkspaceService{workspaceRepo: workspaceRepo}
+}
+
+// ResolveWorkspaceID returns the id of the workspace a client works in,
+// creating the workspace on the client's first request. Ids are cached since
+// workspaces are never renamed or removed.
+func (s *WorkspaceService) ResolveWorkspaceID(clientID string) (uint, error) {
+	if id, ok := s.ids.Load(clientID); ok {
+		return id.(uint), nil
+	}
+
+	workspace, err := s.workspaceRepo.FirstOrCreate(clientID)
+	if err != nil {
+		return 0, fiber.NewError(fiber.StatusInternalServerError, "Failed to resolve workspace")
+	}
+	s.ids.Store(clientID, workspace.ID)
+	return workspace.ID, nil
+}
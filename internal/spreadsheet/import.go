@@ -0,0 +1,201 @@
+package spreadsheet
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/shivamrajput1826/api-catalog/internal/dtos"
+	"github.com/shivamrajput1826/api-catalog/internal/models"
+	"github.com/shivamrajput1826/api-catalog/internal/validation"
+	"github.com/xuri/excelize/v2"
+)
+
+// Read returns the rows of a CSV upload, or of the first sheet of an XLSX
+// workbook.
+func Read(body []byte, contentType string) ([][]string, error) {
+	switch contentType {
+	case CSVContentType:
+		reader := csv.NewReader(bytes.NewReader(bytes.TrimPrefix(body, []byte("\xef\xbb\xbf"))))
+		reader.FieldsPerRecord = -1
+		records, err := reader.ReadAll()
+		if err != nil {
+			return nil, fmt.Errorf("invalid CSV: %s", err.Error())
+		}
+		return records, nil
+	case XLSXContentType:
+		file, err := excelize.OpenReader(bytes.NewReader(body))
+		if err != nil {
+			return nil, fmt.Errorf("invalid XLSX workbook: %s", err.Error())
+		}
+		defer file.Close()
+
+		sheets := file.GetSheetList()
+		if len(sheets) == 0 {
+			return nil, errors.New("XLSX workbook has no sheets")
+		}
+		return file.GetRows(sheets[0])
+	}
+	return nil, fmt.Errorf("unsupported content type '%s'. Must be %s or %s", contentType, CSVContentType, XLSXContentType)
+}
+
+// Events groups spreadsheet rows into tracking plan events. Rows of the same
+// event must agree on its description and additionalProperties; a blank
+// cell defers to the other rows. Event types default to track. Without a
+// Constraints column the properties keep the plan-level constraints they
+// already have.
+func Events(records [][]string) ([]dtos.TrackingPlanEventRequest, error) {
+	if len(records) == 0 {
+		return nil, errors.New("spreadsheet is empty")
+	}
+
+	columns := make(map[string]int, len(records[0]))
+	for i, title := range records[0] {
+		for _, known := range Header {
+			if strings.EqualFold(strings.TrimSpace(title), known) {
+				columns[known] = i
+			}
+		}
+	}
+	if _, ok := columns[ColumnEventName]; !ok {
+		return nil, fmt.Errorf("header row must include an '%s' column", ColumnEventName)
+	}
+	_, hasConstraints := columns[ColumnConstraints]
+
+	events := []dtos.TrackingPlanEventRequest{}
+	index := make(map[string]int)
+	additionalSet := make(map[string]bool)
+	for i, record := range records[1:] {
+		row := i + 2
+		cell := func(column string) string {
+			if at, ok := columns[column]; ok && at < len(record) {
+				return strings.TrimSpace(record[at])
+			}
+			return ""
+		}
+		if strings.TrimSpace(strings.Join(record, "")) == "" {
+			continue
+		}
+
+		name := cell(ColumnEventName)
+		if name == "" {
+			return nil, fmt.Errorf("row %d: %s is required", row, ColumnEventName)
+		}
+		eventType := strings.ToLower(cell(ColumnEventType))
+		if eventType == "" {
+			eventType = "track"
+		}
+		if !validation.ValidEventTypes[eventType] {
+			return nil, fmt.Errorf("row %d: invalid event type '%s'. Must be one of: track, identify, alias, screen, page", row, eventType)
+		}
+
+		key := eventType + "\x00" + name
+		at, ok := index[key]
+		if !ok {
+			at = len(events)
+			index[key] = at
+			events = append(events, dtos.TrackingPlanEventRequest{
+				Name:       name,
+				Type:       eventType,
+				Properties: []dtos.TrackingPlanPropertyRequest{},
+			})
+		}
+		event := &events[at]
+
+		if description := cell(ColumnEventDescription); description != "" {
+			if event.Description != "" && event.Description != description {
+				return nil, fmt.Errorf("row %d: event '%s' has a different description on an earlier row", row, name)
+			}
+			event.Description = description
+		}
+		if text := cell(ColumnAdditionalProperties); text != "" {
+			additional, err := parseBool(text)
+			if err != nil {
+				return nil, fmt.Errorf("row %d: %s: %s", row, ColumnAdditionalProperties, err.Error())
+			}
+			if additionalSet[key] && event.AdditionalProperties != additional {
+				return nil, fmt.Errorf("row %d: event '%s' has a different %s value on an earlier row", row, name, ColumnAdditionalProperties)
+			}
+			additionalSet[key] = true
+			event.AdditionalProperties = additional
+		}
+
+		propName := cell(ColumnPropertyName)
+		if propName == "" {
+			continue
+		}
+		propertyType, items, err := parseTypeLabel(cell(ColumnPropertyType))
+		if err != nil {
+			return nil, fmt.Errorf("row %d: %s", row, err.Error())
+		}
+		required, err := parseBool(cell(ColumnRequired))
+		if err != nil {
+			return nil, fmt.Errorf("row %d: %s: %s", row, ColumnRequired, err.Error())
+		}
+		constraints, err := parseConstraints(cell(ColumnConstraints))
+		if err != nil {
+			return nil, fmt.Errorf("row %d: %s: %s", row, ColumnConstraints, err.Error())
+		}
+		event.Properties = append(event.Properties, dtos.TrackingPlanPropertyRequest{
+			Name:                propName,
+			Type:                propertyType,
+			Items:               items,
+			Required:            required,
+			Description:         cell(ColumnPropertyDescription),
+			PropertyConstraints: constraints,
+			KeepConstraints:     !hasConstraints,
+		})
+	}
+
+	if len(events) == 0 {
+		return nil, errors.New("spreadsheet has no event rows")
+	}
+	return events, nil
+}
+
+// parseTypeLabel reads the label form of a property type written by
+// models.TypeLabel, e.g. "string|null" or "array<number>".
+func parseTypeLabel(label string) (models.PropertyType, models.PropertyType, error) {
+	label = strings.ToLower(strings.ReplaceAll(label, " ", ""))
+	if label == "" {
+		return "", "", fmt.Errorf("%s is required", ColumnPropertyType)
+	}
+
+	outer, inner, isArray := strings.Cut(label, "<")
+	if !isArray {
+		return models.NewPropertyType(strings.Split(label, "|")...), "", nil
+	}
+	if !strings.HasSuffix(inner, ">") {
+		return "", "", fmt.Errorf("invalid property type '%s'", label)
+	}
+	items := strings.TrimSuffix(inner, ">")
+	return models.NewPropertyType(strings.Split(outer, "|")...), models.NewPropertyType(strings.Split(items, "|")...), nil
+}
+
+// parseConstraints reads the JSON object written by Records. A blank cell
+// means no constraints.
+func parseConstraints(text string) (models.PropertyConstraints, error) {
+	var constraints models.PropertyConstraints
+	if text == "" {
+		return constraints, nil
+	}
+	decoder := json.NewDecoder(strings.NewReader(text))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&constraints); err != nil {
+		return constraints, fmt.Errorf("invalid constraints '%s': %s", text, err.Error())
+	}
+	return constraints, nil
+}
+
+func parseBool(text string) (bool, error) {
+	switch strings.ToLower(strings.TrimSpace(text)) {
+	case "", "false", "no", "n", "0":
+		return false, nil
+	case "true", "yes", "y", "1", "x":
+		return true, nil
+	}
+	return false, fmt.Errorf("'%s' is not a yes/no value", text)
+}
@@ -0,0 +1,183 @@
+// Package spreadsheet converts tracking plans to and from the spreadsheet
+// layout product managers author them in: one row per event/property pair,
+// with events that have no properties on a row of their own.
+package spreadsheet
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/shivamrajput1826/api-catalog/internal/models"
+	"github.com/xuri/excelize/v2"
+)
+
+const (
+	FormatCSV  = "csv"
+	FormatXLSX = "xlsx"
+
+	CSVContentType  = "text/csv"
+	XLSXContentType = "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+
+	sheetName = "Tracking Plan"
+)
+
+const (
+	ColumnEventName            = "Event Name"
+	ColumnEventType            = "Event Type"
+	ColumnEventDescription     = "Event Description"
+	ColumnPropertyName         = "Property Name"
+	ColumnPropertyType         = "Property Type"
+	ColumnRequired             = "Required"
+	ColumnPropertyDescription  = "Property Description"
+	ColumnAdditionalProperties = "Additional Properties"
+	ColumnConstraints          = "Constraints"
+)
+
+// Header is the column order used on export. Imports match columns by name,
+// so they may come in any order and only the event name is mandatory.
+var Header = []string{
+	ColumnEventName,
+	ColumnEventType,
+	ColumnEventDescription,
+	ColumnPropertyName,
+	ColumnPropertyType,
+	ColumnRequired,
+	ColumnPropertyDescription,
+	ColumnAdditionalProperties,
+	ColumnConstraints,
+}
+
+// File is an exported spreadsheet ready to be sent to the client.
+type File struct {
+	Name        string
+	ContentType string
+	Body        []byte
+}
+
+var unsafeFileChars = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+
+// Export renders a tracking plan as a CSV or XLSX file.
+func Export(plan *models.TrackingPlan, format string) (*File, error) {
+	records, err := Records(plan)
+	if err != nil {
+		return nil, err
+	}
+	baseName := strings.Trim(unsafeFileChars.ReplaceAllString(plan.Name, "-"), "-")
+	if baseName == "" {
+		baseName = fmt.Sprintf("tracking-plan-%d", plan.ID)
+	}
+
+	switch format {
+	case FormatCSV:
+		body, err := writeCSV(records)
+		if err != nil {
+			return nil, err
+		}
+		return &File{Name: baseName + ".csv", ContentType: CSVContentType, Body: body}, nil
+	case FormatXLSX:
+		body, err := writeXLSX(records)
+		if err != nil {
+			return nil, err
+		}
+		return &File{Name: baseName + ".xlsx", ContentType: XLSXContentType, Body: body}, nil
+	}
+	return nil, fmt.Errorf("unsupported format '%s'. Must be one of: %s, %s", format, FormatCSV, FormatXLSX)
+}
+
+// Records lays a tracking plan out as rows, starting with the header.
+// Property types use their label form, e.g. "string|null" or "array<number>".
+// The plan-level constraints of a property are written as a JSON object.
+func Records(plan *models.TrackingPlan) ([][]string, error) {
+	records := [][]string{Header}
+	for _, planEvent := range plan.Events {
+		event := planEvent.Event
+		additional := fmt.Sprint(planEvent.AdditionalProperties)
+		if len(planEvent.Properties) == 0 {
+			records = append(records, []string{event.Name, event.Type, event.Description, "", "", "", "", additional, ""})
+			continue
+		}
+		for _, planProp := range planEvent.Properties {
+			property := planProp.Property
+			constraints, err := constraintsCell(planProp.PropertyConstraints)
+			if err != nil {
+				return nil, err
+			}
+			records = append(records, []string{
+				event.Name,
+				event.Type,
+				event.Description,
+				property.Name,
+				models.TypeLabel(property.Type, property.Items),
+				fmt.Sprint(planProp.Required),
+				property.Description,
+				additional,
+				constraints,
+			})
+		}
+	}
+	return records, nil
+}
+
+func constraintsCell(constraints models.PropertyConstraints) (string, error) {
+	if constraints.IsZero() {
+		return "", nil
+	}
+	encoded, err := json.Marshal(constraints)
+	if err != nil {
+		return "", err
+	}
+	return string(encoded), nil
+}
+
+func writeCSV(records [][]string) ([]byte, error) {
+	var buf bytes.Buffer
+	writer := csv.NewWriter(&buf)
+	if err := writer.WriteAll(records); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func writeXLSX(records [][]string) ([]byte, error) {
+	file := excelize.NewFile()
+	defer file.Close()
+
+	if err := file.SetSheetName(file.GetSheetName(0), sheetName); err != nil {
+		return nil, err
+	}
+	for i, record := range records {
+		cell, err := excelize.CoordinatesToCellName(1, i+1)
+		if err != nil {
+			return nil, err
+		}
+		if err := file.SetSheetRow(sheetName, cell, &record); err != nil {
+			return nil, err
+		}
+	}
+
+	headerStyle, err := file.NewStyle(&excelize.Style{Font: &excelize.Font{Bold: true}})
+	if err != nil {
+		return nil, err
+	}
+	if err := file.SetRowStyle(sheetName, 1, 1, headerStyle); err != nil {
+		return nil, err
+	}
+	if err := file.SetPanes(sheetName, &excelize.Panes{
+		Freeze:      true,
+		YSplit:      1,
+		TopLeftCell: "A2",
+		ActivePane:  "bottomLeft",
+	}); err != nil {
+		return nil, err
+	}
+
+	buf, err := file.WriteToBuffer()
+	if err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
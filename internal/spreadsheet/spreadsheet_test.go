@@ -0,0 +1,67 @@
+package spreadsheet
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/shivamrajput1826/api-catalog/internal/models"
+)
+
+func TestRecordsRoundTripConstraints(t *testing.T) {
+	minLength := 2
+	constraints := models.PropertyConstraints{Enum: []interface{}{"free", "pro"}, MinLength: &minLength}
+	plan := &models.TrackingPlan{
+		Name: "Web",
+		Events: []models.TrackingPlanEvent{{
+			Event: models.Event{Name: "Signed Up", Type: "track"},
+			Properties: []models.TrackingPlanEventProperty{
+				{Property: models.Property{Name: "plan", Type: "string"}, Required: true, PropertyConstraints: constraints},
+				{Property: models.Property{Name: "referrer", Type: "string"}},
+			},
+		}},
+	}
+
+	records, err := Records(plan)
+	if err != nil {
+		t.Fatal(err)
+	}
+	events, err := Events(records)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(events) != 1 || len(events[0].Properties) != 2 {
+		t.Fatalf("unexpected events %+v", events)
+	}
+	planProp, referrer := events[0].Properties[0], events[0].Properties[1]
+	if !reflect.DeepEqual(planProp.PropertyConstraints, constraints) || planProp.KeepConstraints {
+		t.Errorf("got constraints %+v, want %+v", planProp.PropertyConstraints, constraints)
+	}
+	if !referrer.PropertyConstraints.IsZero() || referrer.KeepConstraints {
+		t.Errorf("got constraints %+v, want none", referrer.PropertyConstraints)
+	}
+}
+
+func TestEventsWithoutConstraintsColumnKeepsConstraints(t *testing.T) {
+	events, err := Events([][]string{
+		{ColumnEventName, ColumnPropertyName, ColumnPropertyType},
+		{"Signed Up", "plan", "string"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !events[0].Properties[0].KeepConstraints {
+		t.Error("expected the existing constraints to be kept")
+	}
+}
+
+func TestEventsRejectsInvalidConstraints(t *testing.T) {
+	for _, cell := range []string{`{"enum": [}`, `{"minimun": 1}`, `["free"]`} {
+		_, err := Events([][]string{
+			{ColumnEventName, ColumnPropertyName, ColumnPropertyType, ColumnConstraints},
+			{"Signed Up", "plan", "string", cell},
+		})
+		if err == nil {
+			t.Errorf("%s: expected an error", cell)
+		}
+	}
+}
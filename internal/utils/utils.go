@@ -1,7 +1,14 @@
 package utils
 
 import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/url"
+	"path/filepath"
 	"strconv"
+	"strings"
 
 	"github.com/gofiber/fiber/v2"
 )
@@ -18,3 +25,163 @@ func ParseUintID(idStr string) (uint, error) {
 
 	return uint(id), nil
 }
+
+// ActorID returns the id of the authenticated user making the request, or an
+// empty string when the request is not authenticated.
+func ActorID(c *fiber.Ctx) string {
+	userID := c.Locals("user_id")
+	if userID == nil {
+		return ""
+	}
+	return fmt.Sprint(userID)
+}
+
+// ActorEmail returns the email of the authenticated user making the request,
+// or an empty string when it is unknown.
+func ActorEmail(c *fiber.Ctx) string {
+	email, _ := c.Locals("email").(string)
+	return email
+}
+
+// ClientID returns the client-id header the request was made with.
+func ClientID(c *fiber.Ctx) string {
+	return c.Get("client-id")
+}
+
+func ParseVersion(versionStr string) (int, error) {
+	if versionStr == "" {
+		return 0, fiber.NewError(fiber.StatusBadRequest, "Version parameter is required")
+	}
+
+	version, err := strconv.Atoi(versionStr)
+	if err != nil || version < 1 {
+		return 0, fiber.NewError(fiber.StatusBadRequest, "Invalid version format")
+	}
+
+	return version, nil
+}
+
+// ParseOptionalVersion behaves like ParseVersion but returns 0 when no
+// version was supplied.
+func ParseOptionalVersion(versionStr string) (int, error) {
+	if versionStr == "" {
+		return 0, nil
+	}
+	return ParseVersion(versionStr)
+}
+
+// ETag formats a resource version as a strong entity tag.
+func ETag(version int) string {
+	return strconv.Quote(strconv.Itoa(version))
+}
+
+// IfMatchVersions reads the resource versions listed in the If-Match header.
+// It returns nil when the header is absent or "*", meaning the write is
+// unconditional. Weak or malformed tags can never match, so they are skipped.
+func IfMatchVersions(c *fiber.Ctx) []int {
+	header := strings.TrimSpace(c.Get(fiber.HeaderIfMatch))
+	if header == "" || header == "*" {
+		return nil
+	}
+
+	versions := []int{}
+	for _, tag := range strings.Split(header, ",") {
+		tag = strings.TrimSpace(tag)
+		if !strings.HasPrefix(tag, `"`) {
+			continue
+		}
+		unquoted, err := strconv.Unquote(tag)
+		if err != nil {
+			continue
+		}
+		if version, err := strconv.Atoi(unquoted); err == nil {
+			versions = append(versions, version)
+		}
+	}
+	return versions
+}
+
+// MediaType returns the request's Content-Type without parameters such as
+// charset.
+func MediaType(c *fiber.Ctx) string {
+	mediaType, _, _ := strings.Cut(c.Get(fiber.HeaderContentType), ";")
+	return strings.ToLower(strings.TrimSpace(mediaType))
+}
+
+// uploadTypes maps file extensions to the media type assumed for uploaded
+// files that arrive without a useful Content-Type.
+var uploadTypes = map[string]string{
+	".csv":    "text/csv",
+	".ndjson": "application/x-ndjson",
+	".jsonl":  "application/x-ndjson",
+	".xlsx":   "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet",
+}
+
+// UploadBody returns the uploaded document and its media type. The document
+// is either the raw request body or, for multipart forms, the "file" field.
+func UploadBody(c *fiber.Ctx) ([]byte, string, error) {
+	if MediaType(c) != fiber.MIMEMultipartForm {
+		return c.Body(), MediaType(c), nil
+	}
+
+	header, err := c.FormFile("file")
+	if err != nil {
+		return nil, "", fiber.NewError(fiber.StatusBadRequest, "file is required")
+	}
+	file, err := header.Open()
+	if err != nil {
+		return nil, "", fiber.NewError(fiber.StatusBadRequest, "Failed to read uploaded file")
+	}
+	defer file.Close()
+
+	body, err := io.ReadAll(file)
+	if err != nil {
+		return nil, "", fiber.NewError(fiber.StatusBadRequest, "Failed to read uploaded file")
+	}
+
+	mediaType, _, _ := strings.Cut(header.Header.Get(fiber.HeaderContentType), ";")
+	mediaType = strings.ToLower(strings.TrimSpace(mediaType))
+	if mediaType == "" || mediaType == fiber.MIMEOctetStream {
+		mediaType = uploadTypes[strings.ToLower(filepath.Ext(header.Filename))]
+	}
+	return body, mediaType, nil
+}
+
+// Cursor marks the last row of a page for keyset pagination. It records the
+// ordering it was issued for so it cannot be replayed against another sort.
+type Cursor struct {
+	SortBy     string      `json:"s"`
+	Descending bool        `json:"d,omitempty"`
+	Value      interface{} `json:"v,omitempty"`
+	ID         uint        `json:"id"`
+}
+
+func EncodeCursor(cursor Cursor) string {
+	data, _ := json.Marshal(cursor)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func DecodeCursor(cursorStr string) (*Cursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(cursorStr)
+	if err != nil {
+		return nil, fiber.NewError(fiber.StatusBadRequest, "Invalid cursor")
+	}
+
+	var cursor Cursor
+	if err := json.Unmarshal(data, &cursor); err != nil || cursor.ID == 0 {
+		return nil, fiber.NewError(fiber.StatusBadRequest, "Invalid cursor")
+	}
+
+	return &cursor, nil
+}
+
+// NextPageLink rebuilds the current request URL with the cursor parameter
+// pointing at the next page.
+func NextPageLink(c *fiber.Ctx, cursor string) string {
+	values, err := url.ParseQuery(string(c.Request().URI().QueryString()))
+	if err != nil {
+		values = url.Values{}
+	}
+	values.Set("cursor", cursor)
+	return c.BaseURL() + c.Path() + "?" + values.Encode()
+}
@@ -0,0 +1,192 @@
+package validation
+
+import (
+	"fmt"
+	"net/mail"
+	"net/url"
+	"reflect"
+	"regexp"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/shivamrajput1826/api-catalog/internal/dtos"
+	"github.com/shivamrajput1826/api-catalog/internal/models"
+)
+
+var ValidFormats = map[string]bool{
+	"email":     true,
+	"uri":       true,
+	"date-time": true,
+	"uuid":      true,
+	"iso-4217":  true,
+}
+
+const validFormatNames = "email, uri, date-time, uuid, iso-4217"
+
+var uuidPattern = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+
+// currencyCodes lists the active ISO 4217 alphabetic currency codes.
+var currencyCodes = toSet(strings.Fields(`
+	AED AFN ALL AMD ANG AOA ARS AUD AWG AZN BAM BBD BDT BGN BHD BIF BMD BND BOB
+	BOV BRL BSD BTN BWP BYN BZD CAD CDF CHE CHF CHW CLF CLP CNY COP COU CRC CUP
+	CVE CZK DJF DKK DOP DZD EGP ERN ETB EUR FJD FKP GBP GEL GHS GIP GMD GNF GTQ
+	GYD HKD HNL HTG HUF IDR ILS INR IQD IRR ISK JMD JOD JPY KES KGS KHR KMF KPW
+	KRW KWD KYD KZT LAK LBP LKR LRD LSL LYD MAD MDL MGA MKD MMK MNT MOP MRU MUR
+	MVR MWK MXN MXV MYR MZN NAD NGN NIO NOK NPR NZD OMR PAB PEN PGK PHP PKR PLN
+	PYG QAR RON RSD RUB RWF SAR SBD SCR SDG SEK SGD SHP SLE SOS SRD SSP STN SVC
+	SYP SZL THB TJS TMT TND TOP TRY TTD TWD TZS UAH UGX USD USN UYI UYU UYW UZS
+	VED VES VND VUV WST XAF XAG XAU XBA XBB XBC XBD XCD XCG XDR XOF XPD XPF XPT
+	XSU XTS XUA XXX YER ZAR ZMW ZWG
+`))
+
+func toSet(values []string) map[string]bool {
+	set := make(map[string]bool, len(values))
+	for _, value := range values {
+		set[value] = true
+	}
+	return set
+}
+
+// constraintsError checks that constraints are well formed and fit the
+// property type, returning an empty string when they do.
+func constraintsError(label string, propertyType, items models.PropertyType, c models.PropertyConstraints) string {
+	if c.IsZero() {
+		return ""
+	}
+
+	valueType := propertyType
+	if items != "" {
+		valueType = items
+	}
+	isNumeric := valueType.Has("number") || valueType.Has("integer")
+	isString := valueType.Has("string") || valueType.Has("date-time")
+
+	if (c.Minimum != nil || c.Maximum != nil) && !isNumeric {
+		return fmt.Sprintf("%s: minimum and maximum only apply to number or integer properties", label)
+	}
+	if c.Minimum != nil && c.Maximum != nil && *c.Minimum > *c.Maximum {
+		return fmt.Sprintf("%s: minimum must not be greater than maximum", label)
+	}
+
+	if (c.MinLength != nil || c.MaxLength != nil || c.Pattern != "" || c.Format != "") && !isString {
+		return fmt.Sprintf("%s: minLength, maxLength, pattern and format only apply to string properties", label)
+	}
+	if (c.MinLength != nil && *c.MinLength < 0) || (c.MaxLength != nil && *c.MaxLength < 0) {
+		return fmt.Sprintf("%s: minLength and maxLength must not be negative", label)
+	}
+	if c.MinLength != nil && c.MaxLength != nil && *c.MinLength > *c.MaxLength {
+		return fmt.Sprintf("%s: minLength must not be greater than maxLength", label)
+	}
+	if c.Pattern != "" {
+		if _, err := regexp.Compile(c.Pattern); err != nil {
+			return fmt.Sprintf("%s: invalid pattern '%s': %s", label, c.Pattern, err.Error())
+		}
+	}
+	if c.Format != "" && !ValidFormats[c.Format] {
+		return fmt.Sprintf("%s: invalid format '%s'. Must be one of: %s", label, c.Format, validFormatNames)
+	}
+
+	for i, value := range c.Enum {
+		matched := false
+		for _, name := range valueType.Types() {
+			if matchesPropertyType(value, name) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return fmt.Sprintf("%s: enum[%d] does not match type %s", label, i, valueType)
+		}
+	}
+
+	return ""
+}
+
+// checkConstraints reports every constraint the value breaks. Null values
+// carry no constraints; whether null is allowed at all is a type question.
+func checkConstraints(path string, value interface{}, c models.PropertyConstraints) []dtos.PayloadViolation {
+	violations := []dtos.PayloadViolation{}
+	if value == nil || c.IsZero() {
+		return violations
+	}
+	violate := func(format string, args ...interface{}) {
+		violations = append(violations, dtos.PayloadViolation{
+			Code:    ViolationConstraint,
+			Path:    path,
+			Message: path + " " + fmt.Sprintf(format, args...),
+		})
+	}
+
+	if len(c.Enum) > 0 && !enumContains(c.Enum, value) {
+		violate("must be one of %s", formatEnum(c.Enum))
+	}
+
+	switch typed := value.(type) {
+	case float64:
+		if c.Minimum != nil && typed < *c.Minimum {
+			violate("must be at least %v", *c.Minimum)
+		}
+		if c.Maximum != nil && typed > *c.Maximum {
+			violate("must be at most %v", *c.Maximum)
+		}
+	case string:
+		length := utf8.RuneCountInString(typed)
+		if c.MinLength != nil && length < *c.MinLength {
+			violate("must be at least %d characters long", *c.MinLength)
+		}
+		if c.MaxLength != nil && length > *c.MaxLength {
+			violate("must be at most %d characters long", *c.MaxLength)
+		}
+		if c.Pattern != "" {
+			if pattern, err := regexp.Compile(c.Pattern); err == nil && !pattern.MatchString(typed) {
+				violate("must match pattern %s", c.Pattern)
+			}
+		}
+		if c.Format != "" && !matchesFormat(typed, c.Format) {
+			violate("must be a valid %s", c.Format)
+		}
+	}
+
+	return violations
+}
+
+func matchesFormat(value, format string) bool {
+	switch format {
+	case "email":
+		address, err := mail.ParseAddress(value)
+		return err == nil && address.Address == value
+	case "uri":
+		parsed, err := url.Parse(value)
+		return err == nil && parsed.Scheme != ""
+	case "date-time":
+		_, err := time.Parse(time.RFC3339, value)
+		return err == nil
+	case "uuid":
+		return uuidPattern.MatchString(value)
+	case "iso-4217":
+		return currencyCodes[value]
+	}
+	return true
+}
+
+func enumContains(enum []interface{}, value interface{}) bool {
+	for _, allowed := range enum {
+		if reflect.DeepEqual(allowed, value) {
+			return true
+		}
+	}
+	return false
+}
+
+func formatEnum(enum []interface{}) string {
+	values := make([]string, 0, len(enum))
+	for _, value := range enum {
+		if text, ok := value.(string); ok {
+			values = append(values, fmt.Sprintf("%q", text))
+			continue
+		}
+		values = append(values, fmt.Sprintf("%v", value))
+	}
+	return "[" + strings.Join(values, ", ") + "]"
+}
@@ -0,0 +1,189 @@
+package validation
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/shivamrajput1826/api-catalog/internal/dtos"
+	"github.com/shivamrajput1826/api-catalog/internal/models"
+)
+
+const (
+	ViolationUnknownEvent       = "unknown_event"
+	ViolationMissingProperty    = "missing_required_property"
+	ViolationInvalidType        = "invalid_type"
+	ViolationUnexpectedProperty = "unexpected_property"
+	ViolationConstraint         = "constraint_violation"
+)
+
+func (v *Validator) ValidatePayloadRequest(req *dtos.ValidatePayloadRequest) error {
+	if req.Type == "" {
+		customLogger.Error("ValidatePayloadRequestError", "type is required")
+		return fiber.NewError(fiber.StatusBadRequest, "type is required")
+	}
+	if !ValidEventTypes[req.Type] {
+		customLogger.Error("ValidatePayloadRequestError", "Wrong Validation type", req.Type)
+		return fiber.NewError(fiber.StatusBadRequest,
+			fmt.Sprintf("invalid event type '%s'. Must be one of: track, identify, alias, screen, page", req.Type))
+	}
+	if req.Type == "track" && req.Event == "" {
+		customLogger.Error("ValidatePayloadRequestError", "event is required for track calls")
+		return fiber.NewError(fiber.StatusBadRequest, "event is required for track calls")
+	}
+	return nil
+}
+
+// ValidateEventPayload checks the properties (or traits) of a payload against
+// the definition of a single tracking plan event and returns every violation
+// found. An empty result means the payload conforms to the plan.
+func (v *Validator) ValidateEventPayload(planEvent *models.TrackingPlanEvent, fields map[string]interface{}) []dtos.PayloadViolation {
+	field := models.PayloadFieldFor(planEvent.Event.Type)
+	violations := []dtos.PayloadViolation{}
+	declared := make(map[string]bool, len(planEvent.Properties))
+
+	for _, planProp := range planEvent.Properties {
+		name := planProp.Property.Name
+		declared[name] = true
+		path := field + "." + name
+
+		value, ok := fields[name]
+		if !ok {
+			if planProp.Required {
+				violations = append(violations, dtos.PayloadViolation{
+					Code:    ViolationMissingProperty,
+					Path:    path,
+					Message: fmt.Sprintf("required property '%s' is missing", name),
+				})
+			}
+			continue
+		}
+
+		property := planProp.Property
+		violations = append(violations, checkPropertyValue(path, value, property.Type, property.Items, planProp.EffectiveConstraints(), property.Properties)...)
+	}
+
+	if !planEvent.AdditionalProperties {
+		extra := make([]string, 0)
+		for name := range fields {
+			if !declared[name] {
+				extra = append(extra, name)
+			}
+		}
+		sort.Strings(extra)
+		for _, name := range extra {
+			violations = append(violations, dtos.PayloadViolation{
+				Code:    ViolationUnexpectedProperty,
+				Path:    field + "." + name,
+				Message: fmt.Sprintf("property '%s' is not defined in the tracking plan", name),
+			})
+		}
+	}
+
+	return violations
+}
+
+// checkPropertyValue reports a type violation when value matches none of the
+// types in the union. Otherwise it checks the constraints, each element of
+// arrays against items, and the children of objects.
+func checkPropertyValue(path string, value interface{}, propertyType, items models.PropertyType, constraints models.PropertyConstraints, children []models.PropertyField) []dtos.PayloadViolation {
+	for _, name := range propertyType.Types() {
+		if !matchesPropertyType(value, name) {
+			continue
+		}
+		switch typed := value.(type) {
+		case []interface{}:
+			if name == "array" && items != "" {
+				violations := []dtos.PayloadViolation{}
+				for i, element := range typed {
+					violations = append(violations, checkPropertyValue(fmt.Sprintf("%s[%d]", path, i), element, items, "", constraints, children)...)
+				}
+				return violations
+			}
+		case map[string]interface{}:
+			return append(checkConstraints(path, value, constraints), checkPropertyFields(path, typed, children)...)
+		}
+		return checkConstraints(path, value, constraints)
+	}
+
+	return []dtos.PayloadViolation{{
+		Code:    ViolationInvalidType,
+		Path:    path,
+		Message: fmt.Sprintf("%s must be of type %s, got %s", path, propertyType, jsonTypeOf(value)),
+	}}
+}
+
+// checkPropertyFields checks the children of an object value. Keys that are
+// not declared as children are allowed.
+func checkPropertyFields(path string, fields map[string]interface{}, children []models.PropertyField) []dtos.PayloadViolation {
+	violations := []dtos.PayloadViolation{}
+	for _, child := range children {
+		childPath := path + "." + child.Name
+		value, ok := fields[child.Name]
+		if !ok {
+			if child.Required {
+				violations = append(violations, dtos.PayloadViolation{
+					Code:    ViolationMissingProperty,
+					Path:    childPath,
+					Message: fmt.Sprintf("required property '%s' is missing", childPath),
+				})
+			}
+			continue
+		}
+		violations = append(violations, checkPropertyValue(childPath, value, child.Type, child.Items, child.PropertyConstraints, child.Properties)...)
+	}
+	return violations
+}
+
+func matchesPropertyType(value interface{}, typeName string) bool {
+	switch typeName {
+	case "string":
+		_, ok := value.(string)
+		return ok
+	case "number":
+		_, ok := value.(float64)
+		return ok
+	case "integer":
+		number, ok := value.(float64)
+		return ok && number == math.Trunc(number)
+	case "boolean":
+		_, ok := value.(bool)
+		return ok
+	case "array":
+		_, ok := value.([]interface{})
+		return ok
+	case "object":
+		_, ok := value.(map[string]interface{})
+		return ok
+	case "null":
+		return value == nil
+	case "date-time":
+		text, ok := value.(string)
+		if !ok {
+			return false
+		}
+		_, err := time.Parse(time.RFC3339, text)
+		return err == nil
+	}
+	return false
+}
+
+func jsonTypeOf(value interface{}) string {
+	switch value.(type) {
+	case nil:
+		return "null"
+	case string:
+		return "string"
+	case float64:
+		return "number"
+	case bool:
+		return "boolean"
+	case []interface{}:
+		return "array"
+	case map[string]interface{}:
+		return "object"
+	}
+	return fmt.Sprintf("%T", value)
+}
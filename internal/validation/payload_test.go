@@ -0,0 +1,167 @@
+package validation
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/shivamrajput1826/api-catalog/internal/dtos"
+	"github.com/shivamrajput1826/api-catalog/internal/models"
+)
+
+func planProperty(name string, propertyType models.PropertyType, required bool) models.TrackingPlanEventProperty {
+	return models.TrackingPlanEventProperty{
+		Property: models.Property{Name: name, Type: propertyType},
+		Required: required,
+	}
+}
+
+func TestValidateEventPayload(t *testing.T) {
+	minLength := 3
+	minimum := 1.0
+
+	plan := planProperty("plan", "string", true)
+	plan.PropertyConstraints = models.PropertyConstraints{Enum: []interface{}{"free", "pro"}}
+	seats := planProperty("seats", "integer", false)
+	seats.Property.Minimum = &minimum
+	coupon := planProperty("coupon", "string|null", false)
+	coupon.MinLength = &minLength
+	tags := planProperty("tags", "array", false)
+	tags.Property.Items = "string"
+	address := planProperty("address", "object", false)
+	address.Property.Properties = []models.PropertyField{
+		{Name: "city", Type: "string", Required: true},
+		{Name: "zip", Type: "string"},
+	}
+
+	signedUp := &models.TrackingPlanEvent{
+		Event:      models.Event{Name: "Signed Up", Type: "track"},
+		Properties: []models.TrackingPlanEventProperty{plan, seats, coupon, tags, address},
+	}
+	open := *signedUp
+	open.AdditionalProperties = true
+	identify := &models.TrackingPlanEvent{
+		Event:      models.Event{Type: "identify"},
+		Properties: []models.TrackingPlanEventProperty{planProperty("email", "string", true)},
+	}
+
+	tests := []struct {
+		name    string
+		event   *models.TrackingPlanEvent
+		payload string
+		want    []string
+	}{
+		{
+			name:    "valid",
+			event:   signedUp,
+			payload: `{"plan":"pro","seats":3,"coupon":null,"tags":["a","b"],"address":{"city":"Paris","country":"FR"}}`,
+		},
+		{
+			name:    "missing required property",
+			event:   signedUp,
+			payload: `{"seats":3}`,
+			want:    []string{"missing_required_property properties.plan"},
+		},
+		{
+			name:    "type mismatches",
+			event:   signedUp,
+			payload: `{"plan":7,"seats":2.5,"coupon":false,"tags":"a","address":[]}`,
+			want: []string{
+				"invalid_type properties.plan",
+				"invalid_type properties.seats",
+				"invalid_type properties.coupon",
+				"invalid_type properties.tags",
+				"invalid_type properties.address",
+			},
+		},
+		{
+			name:    "constraints",
+			event:   signedUp,
+			payload: `{"plan":"team","seats":0,"coupon":"ab"}`,
+			want: []string{
+				"constraint_violation properties.plan",
+				"constraint_violation properties.seats",
+				"constraint_violation properties.coupon",
+			},
+		},
+		{
+			name:    "array items and object children",
+			event:   signedUp,
+			payload: `{"plan":"free","tags":["a",2],"address":{"zip":75001}}`,
+			want: []string{
+				"invalid_type properties.tags[1]",
+				"missing_required_property properties.address.city",
+				"invalid_type properties.address.zip",
+			},
+		},
+		{
+			name:    "additional properties not allowed",
+			event:   signedUp,
+			payload: `{"plan":"free","zeta":1,"alpha":true}`,
+			want: []string{
+				"unexpected_property properties.alpha",
+				"unexpected_property properties.zeta",
+			},
+		},
+		{
+			name:    "additional properties allowed",
+			event:   &open,
+			payload: `{"plan":"free","zeta":1}`,
+		},
+		{
+			name:    "identify checks traits",
+			event:   identify,
+			payload: `{}`,
+			want:    []string{"missing_required_property traits.email"},
+		},
+	}
+
+	v := New()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var fields map[string]interface{}
+			if err := json.Unmarshal([]byte(tt.payload), &fields); err != nil {
+				t.Fatal(err)
+			}
+			violations := v.ValidateEventPayload(tt.event, fields)
+			if violations == nil {
+				t.Fatal("violations must be an empty list, not nil")
+			}
+			got := make([]string, 0, len(violations))
+			for _, violation := range violations {
+				if violation.Message == "" {
+					t.Errorf("%s at %s has no message", violation.Code, violation.Path)
+				}
+				got = append(got, violation.Code+" "+violation.Path)
+			}
+			if tt.want == nil {
+				tt.want = []string{}
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got violations %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidatePayloadRequest(t *testing.T) {
+	tests := []struct {
+		req   dtos.ValidatePayloadRequest
+		valid bool
+	}{
+		{dtos.ValidatePayloadRequest{Type: "track", Event: "Signed Up"}, true},
+		{dtos.ValidatePayloadRequest{Type: "identify"}, true},
+		{dtos.ValidatePayloadRequest{Type: "page", Name: "Home"}, true},
+		{dtos.ValidatePayloadRequest{Type: "track"}, false},
+		{dtos.ValidatePayloadRequest{Type: "bogus", Event: "Signed Up"}, false},
+		{dtos.ValidatePayloadRequest{Event: "Signed Up"}, false},
+	}
+
+	v := New()
+	for _, tt := range tests {
+		err := v.ValidatePayloadRequest(&tt.req)
+		if (err == nil) != tt.valid {
+			t.Errorf("%+v: got error %v, want valid %t", tt.req, err, tt.valid)
+		}
+	}
+}
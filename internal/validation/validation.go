@@ -2,9 +2,14 @@ package validation
 
 import (
 	"fmt"
+	"regexp"
+	"strings"
+	"time"
 
 	"github.com/gofiber/fiber/v2"
+	"github.com/shivamrajput1826/api-catalog/common"
 	"github.com/shivamrajput1826/api-catalog/internal/dtos"
+	"github.com/shivamrajput1826/api-catalog/internal/models"
 	"github.com/shivamrajput1826/api-catalog/logger"
 )
 
@@ -19,11 +24,60 @@ var ValidEventTypes = map[string]bool{
 }
 
 var ValidPropertyTypes = map[string]bool{
-	"string":  true,
-	"number":  true,
-	"boolean": true,
+	"string":    true,
+	"number":    true,
+	"integer":   true,
+	"boolean":   true,
+	"array":     true,
+	"object":    true,
+	"null":      true,
+	"date-time": true,
 }
 
+const validPropertyTypeNames = "string, number, integer, boolean, array, object, null, date-time"
+
+var ValidSortFields = map[string]bool{
+	"id":          true,
+	"name":        true,
+	"create_time": true,
+	"update_time": true,
+}
+
+var ValidSearchKinds = map[string]bool{
+	"event":         true,
+	"property":      true,
+	"tracking_plan": true,
+}
+
+var ValidAuditResources = map[string]bool{
+	models.AuditResourceEvent:        true,
+	models.AuditResourceProperty:     true,
+	models.AuditResourceTrackingPlan: true,
+}
+
+var ValidAuditActions = map[string]bool{
+	models.AuditActionCreate:  true,
+	models.AuditActionUpdate:  true,
+	models.AuditActionDelete:  true,
+	models.AuditActionRestore: true,
+}
+
+var ValidClientScopes = map[string]bool{
+	common.RoleViewer:   true,
+	common.RoleEditor:   true,
+	common.RoleAdmin:    true,
+	models.ScopeClients: true,
+}
+
+var clientIDPattern = regexp.MustCompile(`^[A-Za-z0-9_-]{3,64}$`)
+
+const (
+	DefaultListLimit   = 50
+	MaxListLimit       = 200
+	DefaultSearchLimit = 20
+	MaxSearchLimit     = 100
+)
+
 type Validator struct{}
 
 func New() *Validator {
@@ -73,10 +127,17 @@ func (v *Validator) ValidateCreateProperty(req *dtos.CreatePropertyRequest) erro
 		customLogger.Error("ValidateCreatePropertyError", "type is required")
 		return fiber.NewError(fiber.StatusBadRequest, "type is required")
 	}
-	if !ValidPropertyTypes[req.Type] {
-		customLogger.Error("ValidateCreatePropertyError", "Wrong Validation type", req.Type)
-		return fiber.NewError(fiber.StatusBadRequest,
-			fmt.Sprintf("invalid property type '%s'. Must be one of: string, number, boolean", req.Type))
+	if msg := propertyTypeError("property type", req.Type, req.Items); msg != "" {
+		customLogger.Error("ValidateCreatePropertyError", "Wrong Validation type", msg)
+		return fiber.NewError(fiber.StatusBadRequest, msg)
+	}
+	if msg := constraintsError("property", req.Type, req.Items, req.PropertyConstraints); msg != "" {
+		customLogger.Error("ValidateCreatePropertyError", msg)
+		return fiber.NewError(fiber.StatusBadRequest, msg)
+	}
+	if msg := propertyFieldsError("property", req.Type, req.Items, req.Properties); msg != "" {
+		customLogger.Error("ValidateCreatePropertyError", msg)
+		return fiber.NewError(fiber.StatusBadRequest, msg)
 	}
 	return nil
 }
@@ -90,10 +151,17 @@ func (v *Validator) ValidateUpdateProperty(req *dtos.UpdatePropertyRequest) erro
 		customLogger.Error("ValidateUpdatePropertyError", "type is required")
 		return fiber.NewError(fiber.StatusBadRequest, "type is required")
 	}
-	if !ValidPropertyTypes[req.Type] {
-		customLogger.Error("ValidateUpdatePropertyError", "Wrong Validation type", req.Type)
-		return fiber.NewError(fiber.StatusBadRequest,
-			fmt.Sprintf("invalid property type '%s'. Must be one of: string, number, boolean", req.Type))
+	if msg := propertyTypeError("property type", req.Type, req.Items); msg != "" {
+		customLogger.Error("ValidateUpdatePropertyError", "Wrong Validation type", msg)
+		return fiber.NewError(fiber.StatusBadRequest, msg)
+	}
+	if msg := constraintsError("property", req.Type, req.Items, req.PropertyConstraints); msg != "" {
+		customLogger.Error("ValidateUpdatePropertyError", msg)
+		return fiber.NewError(fiber.StatusBadRequest, msg)
+	}
+	if msg := propertyFieldsError("property", req.Type, req.Items, req.Properties); msg != "" {
+		customLogger.Error("ValidateUpdatePropertyError", msg)
+		return fiber.NewError(fiber.StatusBadRequest, msg)
 	}
 	return nil
 }
@@ -108,37 +176,266 @@ func (v *Validator) ValidateCreateTrackingPlan(req *dtos.CreateTrackingPlanReque
 		return fiber.NewError(fiber.StatusBadRequest, "events is required and cannot be empty")
 	}
 
-	for i, event := range req.Events {
-		if event.Name == "" {
-			customLogger.Error("ValidateCreateTrackingPlanError", fmt.Sprintf("event[%d].name is required", i))
+	for i := range req.Events {
+		if msg := trackingPlanEventError(fmt.Sprintf("event[%d]", i), &req.Events[i]); msg != "" {
+			customLogger.Error("ValidateCreateTrackingPlanError", msg)
+			return fiber.NewError(fiber.StatusBadRequest, msg)
+		}
+	}
+
+	return nil
+}
+
+func (v *Validator) ValidateUpdateTrackingPlan(req *dtos.UpdateTrackingPlanRequest) error {
+	return v.ValidateCreateTrackingPlan((*dtos.CreateTrackingPlanRequest)(req))
+}
+
+// ValidateTrackingPlanEvent checks a single event added to an existing plan.
+func (v *Validator) ValidateTrackingPlanEvent(req *dtos.TrackingPlanEventRequest) error {
+	if msg := trackingPlanEventError("event", req); msg != "" {
+		customLogger.Error("ValidateTrackingPlanEventError", msg)
+		return fiber.NewError(fiber.StatusBadRequest, msg)
+	}
+	return nil
+}
+
+// ValidatePropertyConstraints checks plan-level constraints set on a property
+// that is already in the catalog.
+func (v *Validator) ValidatePropertyConstraints(property *models.Property, constraints models.PropertyConstraints) error {
+	if msg := constraintsError("property '"+property.Name+"'", property.Type, property.Items, constraints); msg != "" {
+		customLogger.Error("ValidatePropertyConstraintsError", msg)
+		return fiber.NewError(fiber.StatusBadRequest, msg)
+	}
+	return nil
+}
+
+func trackingPlanEventError(label string, event *dtos.TrackingPlanEventRequest) string {
+	if event.Name == "" {
+		return label + ".name is required"
+	}
+	if event.Type == "" {
+		return label + ".type is required"
+	}
+	if !ValidEventTypes[event.Type] {
+		return fmt.Sprintf("invalid %s.type '%s'. Must be one of: track, identify, alias, screen, page", label, event.Type)
+	}
+
+	for i, prop := range event.Properties {
+		propLabel := fmt.Sprintf("%s.properties[%d]", label, i)
+		if prop.Name == "" {
+			return propLabel + ".name is required"
+		}
+		if prop.Type == "" {
+			return propLabel + ".type is required"
+		}
+		if msg := propertyTypeError(propLabel+".type", prop.Type, prop.Items); msg != "" {
+			return msg
+		}
+		if msg := constraintsError(propLabel, prop.Type, prop.Items, prop.PropertyConstraints); msg != "" {
+			return msg
+		}
+		if msg := propertyFieldsError(propLabel, prop.Type, prop.Items, prop.Properties); msg != "" {
+			return msg
+		}
+	}
+	return ""
+}
+
+func (v *Validator) ValidateImportTrackingPlanSchema(req *dtos.ImportTrackingPlanSchemaRequest) error {
+	if req.Name == "" {
+		customLogger.Error("ValidateImportTrackingPlanSchemaError", "name is required")
+		return fiber.NewError(fiber.StatusBadRequest, "name is required")
+	}
+	if len(req.Schemas) == 0 {
+		customLogger.Error("ValidateImportTrackingPlanSchemaError", "schemas is required and cannot be empty")
+		return fiber.NewError(fiber.StatusBadRequest, "schemas is required and cannot be empty")
+	}
+	for i, schema := range req.Schemas {
+		if schema == nil {
+			customLogger.Error("ValidateImportTrackingPlanSchemaError", fmt.Sprintf("schemas[%d] is null", i))
+			return fiber.NewError(fiber.StatusBadRequest, fmt.Sprintf("schemas[%d] must be a JSON Schema object", i))
+		}
+	}
+	return nil
+}
+
+// ValidateListQuery checks paging, sorting and filter parameters. validTypes
+// lists the values accepted by the type filter; nil means the resource has no
+// type and the filter is rejected.
+func (v *Validator) ValidateListQuery(query *dtos.ListQuery, validTypes map[string]bool) error {
+	if query.Limit < 0 || query.Limit > MaxListLimit {
+		customLogger.Error("ValidateListQueryError", "limit out of range", query.Limit)
+		return fiber.NewError(fiber.StatusBadRequest,
+			fmt.Sprintf("limit must be between 1 and %d", MaxListLimit))
+	}
+	if query.Sort != "" && !ValidSortFields[query.Sort] {
+		customLogger.Error("ValidateListQueryError", "invalid sort field", query.Sort)
+		return fiber.NewError(fiber.StatusBadRequest,
+			fmt.Sprintf("invalid sort '%s'. Must be one of: id, name, create_time, update_time", query.Sort))
+	}
+	if query.Order != "" && query.Order != "asc" && query.Order != "desc" {
+		customLogger.Error("ValidateListQueryError", "invalid order", query.Order)
+		return fiber.NewError(fiber.StatusBadRequest,
+			fmt.Sprintf("invalid order '%s'. Must be one of: asc, desc", query.Order))
+	}
+	if query.Type != "" {
+		if validTypes == nil {
+			customLogger.Error("ValidateListQueryError", "type filter is not supported")
+			return fiber.NewError(fiber.StatusBadRequest, "type filter is not supported for this resource")
+		}
+		if !validTypes[query.Type] {
+			customLogger.Error("ValidateListQueryError", "invalid type filter", query.Type)
+			return fiber.NewError(fiber.StatusBadRequest, fmt.Sprintf("invalid type filter '%s'", query.Type))
+		}
+	}
+	if query.CreatedAfter != 0 && query.CreatedBefore != 0 && query.CreatedAfter >= query.CreatedBefore {
+		customLogger.Error("ValidateListQueryError", "created_after must be before created_before")
+		return fiber.NewError(fiber.StatusBadRequest, "created_after must be before created_before")
+	}
+	if query.UpdatedAfter != 0 && query.UpdatedBefore != 0 && query.UpdatedAfter >= query.UpdatedBefore {
+		customLogger.Error("ValidateListQueryError", "updated_after must be before updated_before")
+		return fiber.NewError(fiber.StatusBadRequest, "updated_after must be before updated_before")
+	}
+	if query.Expand != "" && query.Expand != "events" {
+		customLogger.Error("ValidateListQueryError", "invalid expand", query.Expand)
+		return fiber.NewError(fiber.StatusBadRequest,
+			fmt.Sprintf("invalid expand '%s'. Must be: events", query.Expand))
+	}
+	return nil
+}
+
+// ValidateAuditQuery checks the filters of an audit log listing. Paging and
+// time range are checked by ValidateListQuery.
+func (v *Validator) ValidateAuditQuery(query *dtos.AuditQuery) error {
+	if query.Resource != "" && !ValidAuditResources[query.Resource] {
+		customLogger.Error("ValidateAuditQueryError", "invalid resource", query.Resource)
+		return fiber.NewError(fiber.StatusBadRequest,
+			fmt.Sprintf("invalid resource '%s'. Must be one of: event, property, tracking_plan", query.Resource))
+	}
+	if query.Action != "" && !ValidAuditActions[query.Action] {
+		customLogger.Error("ValidateAuditQueryError", "invalid action", query.Action)
+		return fiber.NewError(fiber.StatusBadRequest,
+			fmt.Sprintf("invalid action '%s'. Must be one of: create, update, delete, restore", query.Action))
+	}
+	return nil
+}
+
+func (v *Validator) ValidateCreateAPIClient(req *dtos.CreateAPIClientRequest) error {
+	if !clientIDPattern.MatchString(req.ClientID) {
+		customLogger.Error("ValidateCreateAPIClientError", "invalid client_id", req.ClientID)
+		return fiber.NewError(fiber.StatusBadRequest,
+			"client_id must be 3 to 64 letters, digits, underscores or hyphens")
+	}
+	if len(req.Scopes) == 0 {
+		customLogger.Error("ValidateCreateAPIClientError", "scopes are required")
+		return fiber.NewError(fiber.StatusBadRequest, "scopes are required")
+	}
+	for _, scope := range req.Scopes {
+		if !ValidClientScopes[scope] {
+			customLogger.Error("ValidateCreateAPIClientError", "invalid scope", scope)
 			return fiber.NewError(fiber.StatusBadRequest,
-				fmt.Sprintf("event[%d].name is required", i))
+				fmt.Sprintf("invalid scope '%s'. Must be one of: viewer, editor, admin, clients", scope))
 		}
+	}
+	if req.ExpiresAt != 0 && req.ExpiresAt <= time.Now().Unix() {
+		customLogger.Error("ValidateCreateAPIClientError", "expires_at is in the past", req.ExpiresAt)
+		return fiber.NewError(fiber.StatusBadRequest, "expires_at must be in the future")
+	}
+	return nil
+}
 
-		for j, prop := range event.Properties {
-			if prop.Name == "" {
-				customLogger.Error("ValidateCreateTrackingPlanError", fmt.Sprintf("event[%d].properties[%d].name is required", i, j))
-				return fiber.NewError(fiber.StatusBadRequest,
-					fmt.Sprintf("event[%d].properties[%d].name is required", i, j))
-			}
-			if prop.Type == "" {
-				customLogger.Error("ValidateCreateTrackingPlanError", fmt.Sprintf("event[%d].properties[%d].type is required", i, j))
-				return fiber.NewError(fiber.StatusBadRequest,
-					fmt.Sprintf("event[%d].properties[%d].type is required", i, j))
-			}
-			if !ValidPropertyTypes[prop.Type] {
-				customLogger.Error("ValidateCreateTrackingPlanError", fmt.Sprintf("event[%d].properties[%d].type '%s' is invalid", i, j, prop.Type))
+func (v *Validator) ValidateSearchQuery(query *dtos.SearchQuery) error {
+	if strings.TrimSpace(query.Q) == "" {
+		customLogger.Error("ValidateSearchQueryError", "q is required")
+		return fiber.NewError(fiber.StatusBadRequest, "q is required")
+	}
+	if query.Limit < 0 || query.Limit > MaxSearchLimit {
+		customLogger.Error("ValidateSearchQueryError", "limit out of range", query.Limit)
+		return fiber.NewError(fiber.StatusBadRequest,
+			fmt.Sprintf("limit must be between 1 and %d", MaxSearchLimit))
+	}
+	if query.Types != "" {
+		for _, kind := range strings.Split(query.Types, ",") {
+			if !ValidSearchKinds[strings.TrimSpace(kind)] {
+				customLogger.Error("ValidateSearchQueryError", "invalid search type", kind)
 				return fiber.NewError(fiber.StatusBadRequest,
-					fmt.Sprintf("event[%d].properties[%d].type '%s' is invalid. Must be one of: string, number, boolean", i, j, prop.Type))
+					fmt.Sprintf("invalid type '%s'. Must be one of: event, property, tracking_plan", kind))
 			}
 		}
 	}
+	return nil
+}
 
+// ValidatePropertyType checks a property type or union and, for arrays, the
+// element type. label names the field in the error message.
+func (v *Validator) ValidatePropertyType(label string, propertyType, items models.PropertyType) error {
+	if msg := propertyTypeError(label, propertyType, items); msg != "" {
+		customLogger.Error("ValidatePropertyTypeError", msg)
+		return fiber.NewError(fiber.StatusBadRequest, msg)
+	}
 	return nil
 }
 
-func (v *Validator) ValidateUpdateTrackingPlan(req *dtos.UpdateTrackingPlanRequest) error {
-	return v.ValidateCreateTrackingPlan((*dtos.CreateTrackingPlanRequest)(req))
+func propertyTypeError(label string, propertyType, items models.PropertyType) string {
+	for _, name := range propertyType.Types() {
+		if !ValidPropertyTypes[name] {
+			return fmt.Sprintf("invalid %s '%s'. Must be one of: %s", label, name, validPropertyTypeNames)
+		}
+	}
+	if propertyType == "null" {
+		return fmt.Sprintf("invalid %s: null must be combined with another type", label)
+	}
+	if items == "" {
+		return ""
+	}
+	if !propertyType.Has("array") {
+		return fmt.Sprintf("invalid %s: items is only allowed for array properties", label)
+	}
+	for _, name := range items.Types() {
+		if !ValidPropertyTypes[name] {
+			return fmt.Sprintf("invalid %s items '%s'. Must be one of: %s", label, name, validPropertyTypeNames)
+		}
+	}
+	if items == "null" {
+		return fmt.Sprintf("invalid %s items: null must be combined with another type", label)
+	}
+	return ""
+}
+
+// propertyFieldsError checks the child properties of an object property, or of
+// the objects in an array property, recursing into nested children.
+func propertyFieldsError(label string, propertyType, items models.PropertyType, fields []models.PropertyField) string {
+	if len(fields) == 0 {
+		return ""
+	}
+	if !propertyType.Has("object") && !items.Has("object") {
+		return fmt.Sprintf("%s: child properties are only allowed for object properties or arrays of objects", label)
+	}
+
+	seen := make(map[string]bool, len(fields))
+	for k, field := range fields {
+		fieldLabel := fmt.Sprintf("%s.properties[%d]", label, k)
+		if field.Name == "" {
+			return fieldLabel + ".name is required"
+		}
+		if seen[field.Name] {
+			return fmt.Sprintf("%s: duplicate property '%s'", label, field.Name)
+		}
+		seen[field.Name] = true
+		if field.Type == "" {
+			return fieldLabel + ".type is required"
+		}
+		if msg := propertyTypeError(fieldLabel+".type", field.Type, field.Items); msg != "" {
+			return msg
+		}
+		if msg := constraintsError(fieldLabel, field.Type, field.Items, field.PropertyConstraints); msg != "" {
+			return msg
+		}
+		if msg := propertyFieldsError(fieldLabel, field.Type, field.Items, field.Properties); msg != "" {
+			return msg
+		}
+	}
+	return ""
 }
 
 func (v *Validator) ValidateID(id string) error {
@@ -73,7 +73,7 @@ func (v *Validator) ValidateCreateProperty(req *dtos.CreatePropertyRequest) erro
 		customLogger.Error("ValidateCreatePropertyError", "type is required")
 		return fiber.NewError(fiber.StatusBadRequest, "type is required")
 	}
-	if !ValidPropertyTypes[req.Type] {
+	if !ValidPropertyTypes[string(req.Type)] {
 		customLogger.Error("ValidateCreatePropertyError", "Wrong Validation type", req.Type)
 		return fiber.NewError(fiber.StatusBadRequest,
 			fmt.Sprintf("invalid property type '%s'. Must be one of: string, number, boolean", req.Type))
@@ -90,7 +90,7 @@ func (v *Validator) ValidateUpdateProperty(req *dtos.UpdatePropertyRequest) erro
 		customLogger.Error("ValidateUpdatePropertyError", "type is required")
 		return fiber.NewError(fiber.StatusBadRequest, "type is required")
 	}
-	if !ValidPropertyTypes[req.Type] {
+	if !ValidPropertyTypes[string(req.Type)] {
 		customLogger.Error("ValidateUpdatePropertyError", "Wrong Validation type", req.Type)
 		return fiber.NewError(fiber.StatusBadRequest,
 			fmt.Sprintf("invalid property type '%s'. Must be one of: string, number, boolean", req.Type))
@@ -126,7 +126,7 @@ func (v *Validator) ValidateCreateTrackingPlan(req *dtos.CreateTrackingPlanReque
 				return fiber.NewError(fiber.StatusBadRequest,
 					fmt.Sprintf("event[%d].properties[%d].type is required", i, j))
 			}
-			if !ValidPropertyTypes[prop.Type] {
+			if !ValidPropertyTypes[string(prop.Type)] {
 				customLogger.Error("ValidateCreateTrackingPlanError", fmt.Sprintf("event[%d].properties[%d].type '%s' is invalid", i, j, prop.Type))
 				return fiber.NewError(fiber.StatusBadRequest,
 					fmt.Sprintf("event[%d].properties[%d].type '%s' is invalid. Must be one of: string, number, boolean", i, j, prop.Type))
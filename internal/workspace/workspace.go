@@ -0,0 +1,176 @@
+// Package workspace scopes catalog data to the workspace of the calling
+// client. The workspace travels in the context of a *gorm.DB session; the
+// Plugin then restricts every query, update and delete on models with a
+// WorkspaceID field to that workspace, and stamps it on every created row.
+// Models owned through a parent row, such as the events of a tracking plan,
+// implement Child to be restricted through their parent.
+package workspace
+
+import (
+	"context"
+	"errors"
+	"reflect"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	// Column is the column holding the owning workspace of a row.
+	Column = "workspace_id"
+
+	field       = "WorkspaceID"
+	rawKey      = "workspace:allow_raw"
+	callbackKey = "workspace:scope"
+	rawCheckKey = "workspace:raw"
+)
+
+// ErrRawStatement is returned for raw SQL run in a workspace session without
+// AllowRaw, since the plugin cannot add the workspace condition to it.
+var ErrRawStatement = errors.New("raw SQL in a workspace session must apply the workspace itself; mark it with workspace.AllowRaw")
+
+// Child is implemented by models without a WorkspaceID of their own, whose
+// rows belong to the workspace of a parent row. WorkspaceCondition returns the
+// condition that keeps the rows of the model's table owned by workspaceID.
+type Child interface {
+	WorkspaceCondition(workspaceID uint) clause.Expression
+}
+
+type contextKey struct{}
+
+// WithID returns a copy of ctx carrying the workspace id.
+func WithID(ctx context.Context, id uint) context.Context {
+	return context.WithValue(ctx, contextKey{}, id)
+}
+
+// FromContext returns the workspace id carried by ctx, if any.
+func FromContext(ctx context.Context) (uint, bool) {
+	if ctx == nil {
+		return 0, false
+	}
+	id, ok := ctx.Value(contextKey{}).(uint)
+	return id, ok && id != 0
+}
+
+// AllowRaw lets db run raw SQL in a workspace session. The caller is
+// responsible for restricting the statement to the workspace.
+func AllowRaw(db *gorm.DB) *gorm.DB {
+	return db.Set(rawKey, true)
+}
+
+// Plugin registers the GORM callbacks that enforce workspace isolation.
+// Sessions without a workspace in their context are left untouched, which is
+// what migrations and workspace lookups rely on. Unscoped does not lift the
+// workspace filter; it only affects soft deletes. The callbacks run before
+// GORM builds the SQL of a statement, which is the last point where clauses
+// can be added; raw SQL is already built by then and is rejected unless the
+// session allows it. Statements on other models, including Table queries
+// scanned into plain structs, are not filtered.
+type Plugin struct{}
+
+func (Plugin) Name() string {
+	return "workspace"
+}
+
+func (Plugin) Initialize(db *gorm.DB) error {
+	callbacks := db.Callback()
+	if err := callbacks.Create().Before("gorm:create").Register(callbackKey, assign); err != nil {
+		return err
+	}
+	if err := callbacks.Query().Before("gorm:query").Register(callbackKey, filter); err != nil {
+		return err
+	}
+	if err := callbacks.Update().Before("gorm:update").Register(callbackKey, filter); err != nil {
+		return err
+	}
+	if err := callbacks.Delete().Before("gorm:delete").Register(callbackKey, filter); err != nil {
+		return err
+	}
+	if err := callbacks.Row().Before("gorm:row").Register(callbackKey, filter); err != nil {
+		return err
+	}
+	return callbacks.Raw().Before("gorm:raw").Register(rawCheckKey, checkRaw)
+}
+
+// condition returns the workspace condition of the statement's model: the
+// workspace column for models owned by a workspace, the Child condition for
+// models owned through a parent, and nil for models outside workspaces.
+func condition(stmt *gorm.Statement, id uint) clause.Expression {
+	if stmt.Schema.LookUpField(field) != nil {
+		return clause.Eq{Column: clause.Column{Table: clause.CurrentTable, Name: Column}, Value: id}
+	}
+	if child, ok := reflect.New(stmt.Schema.ModelType).Interface().(Child); ok {
+		return child.WorkspaceCondition(id)
+	}
+	return nil
+}
+
+func filter(db *gorm.DB) {
+	stmt := db.Statement
+	id, ok := FromContext(stmt.Context)
+	if db.Error != nil || !ok {
+		return
+	}
+	if stmt.SQL.Len() > 0 {
+		checkRaw(db)
+		return
+	}
+	if stmt.Schema == nil {
+		return
+	}
+	expr := condition(stmt, id)
+	if expr == nil || hasCondition(stmt, expr) {
+		return
+	}
+	stmt.AddClause(clause.Where{Exprs: []clause.Expression{expr}})
+}
+
+// hasCondition reports whether the WHERE clause already holds expr. A
+// statement can be executed more than once, e.g. a count followed by a find
+// on the same query, and the condition is only added the first time.
+func hasCondition(stmt *gorm.Statement, expr clause.Expression) bool {
+	where, ok := stmt.Clauses["WHERE"].Expression.(clause.Where)
+	if !ok {
+		return false
+	}
+	for _, existing := range where.Exprs {
+		if reflect.DeepEqual(existing, expr) {
+			return true
+		}
+	}
+	return false
+}
+
+func checkRaw(db *gorm.DB) {
+	if _, ok := FromContext(db.Statement.Context); !ok || db.Error != nil {
+		return
+	}
+	if allowed, _ := db.Get(rawKey); allowed != true {
+		db.AddError(ErrRawStatement)
+	}
+}
+
+func assign(db *gorm.DB) {
+	stmt := db.Statement
+	id, ok := FromContext(stmt.Context)
+	if db.Error != nil || !ok || stmt.Schema == nil {
+		return
+	}
+	owner := stmt.Schema.LookUpField(field)
+	if owner == nil {
+		return
+	}
+	switch stmt.ReflectValue.Kind() {
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < stmt.ReflectValue.Len(); i++ {
+			if err := owner.Set(stmt.Context, reflect.Indirect(stmt.ReflectValue.Index(i)), id); err != nil {
+				db.AddError(err)
+				return
+			}
+		}
+	case reflect.Struct:
+		if err := owner.Set(stmt.Context, stmt.ReflectValue, id); err != nil {
+			db.AddError(err)
+		}
+	}
+}
@@ -0,0 +1,162 @@
+package workspace
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/glebarez/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/logger"
+)
+
+type note struct {
+	ID          uint
+	WorkspaceID uint
+	Title       string
+}
+
+type comment struct {
+	ID     uint
+	NoteID uint
+	Body   string
+}
+
+func (comment) WorkspaceCondition(workspaceID uint) clause.Expression {
+	return clause.Expr{
+		SQL:  "? IN (SELECT id FROM notes WHERE workspace_id = ?)",
+		Vars: []interface{}{clause.Column{Table: clause.CurrentTable, Name: "note_id"}, workspaceID},
+	}
+}
+
+// testDB returns a database with one note and one comment in workspace 1
+// and in workspace 2, and sessions scoped to each workspace.
+func testDB(t *testing.T) (db, first, second *gorm.DB) {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open("file:"+t.Name()+"?mode=memory&cache=shared"), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sqlDB, err := db.DB()
+	if err != nil {
+		t.Fatal(err)
+	}
+	sqlDB.SetMaxOpenConns(1)
+	t.Cleanup(func() { sqlDB.Close() })
+	if err := db.Use(Plugin{}); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.AutoMigrate(&note{}, &comment{}); err != nil {
+		t.Fatal(err)
+	}
+
+	first = db.WithContext(WithID(context.Background(), 1))
+	second = db.WithContext(WithID(context.Background(), 2))
+	for _, session := range []*gorm.DB{first, second} {
+		n := note{Title: "note"}
+		if err := session.Create(&n).Error; err != nil {
+			t.Fatal(err)
+		}
+		if err := session.Create(&comment{NoteID: n.ID, Body: "comment"}).Error; err != nil {
+			t.Fatal(err)
+		}
+	}
+	return db, first, second
+}
+
+func TestPluginStampsAndFiltersOwnedRows(t *testing.T) {
+	db, first, second := testDB(t)
+
+	var notes []note
+	if err := db.Order("id").Find(&notes).Error; err != nil {
+		t.Fatal(err)
+	}
+	if len(notes) != 2 || notes[0].WorkspaceID != 1 || notes[1].WorkspaceID != 2 {
+		t.Fatalf("unexpected notes %+v", notes)
+	}
+
+	if err := first.First(&note{}, notes[1].ID).Error; !errors.Is(err, gorm.ErrRecordNotFound) {
+		t.Errorf("read of another workspace's note: got %v", err)
+	}
+	if n := first.Model(&note{}).Where("id = ?", notes[1].ID).Update("title", "changed").RowsAffected; n != 0 {
+		t.Errorf("update of another workspace's note changed %d rows", n)
+	}
+	if n := first.Delete(&note{}, notes[1].ID).RowsAffected; n != 0 {
+		t.Errorf("delete of another workspace's note removed %d rows", n)
+	}
+	if err := second.First(&note{}, "title = ?", "note").Error; err != nil {
+		t.Errorf("the other workspace lost its note: %v", err)
+	}
+}
+
+func TestPluginFiltersChildRowsThroughTheirParent(t *testing.T) {
+	db, first, _ := testDB(t)
+
+	var comments []comment
+	if err := db.Order("id").Find(&comments).Error; err != nil {
+		t.Fatal(err)
+	}
+	var visible []comment
+	if err := first.Find(&visible).Error; err != nil {
+		t.Fatal(err)
+	}
+	if len(visible) != 1 || visible[0].ID != comments[0].ID {
+		t.Errorf("got comments %+v, want only %+v", visible, comments[0])
+	}
+	if n := first.Model(&comment{}).Where("id = ?", comments[1].ID).Update("body", "changed").RowsAffected; n != 0 {
+		t.Errorf("update of another workspace's comment changed %d rows", n)
+	}
+	if n := first.Delete(&comment{}, comments[1].ID).RowsAffected; n != 0 {
+		t.Errorf("delete of another workspace's comment removed %d rows", n)
+	}
+}
+
+func TestPluginAddsTheConditionOnce(t *testing.T) {
+	db, first, _ := testDB(t)
+	var statements []string
+	err := db.Callback().Query().After("gorm:query").Register("test:capture", func(db *gorm.DB) {
+		statements = append(statements, db.Statement.SQL.String())
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var count int64
+	var notes []note
+	if err := first.Model(&note{}).Count(&count).Find(&notes).Error; err != nil {
+		t.Fatal(err)
+	}
+	if count != 1 || len(notes) != 1 {
+		t.Fatalf("got count %d and %d notes, want 1 and 1", count, len(notes))
+	}
+	if len(statements) != 2 {
+		t.Fatalf("got statements %q", statements)
+	}
+	for _, sql := range statements {
+		if strings.Count(sql, "workspace_id") != 1 {
+			t.Errorf("condition missing or repeated in %s", sql)
+		}
+	}
+}
+
+func TestPluginRejectsRawSQLUnlessAllowed(t *testing.T) {
+	db, first, _ := testDB(t)
+
+	var titles []string
+	if err := first.Raw("SELECT title FROM notes").Scan(&titles).Error; !errors.Is(err, ErrRawStatement) {
+		t.Errorf("raw query: got %v", err)
+	}
+	if err := first.Exec("UPDATE notes SET title = 'changed'").Error; !errors.Is(err, ErrRawStatement) {
+		t.Errorf("raw exec: got %v", err)
+	}
+	if err := AllowRaw(first).Raw("SELECT title FROM notes WHERE workspace_id = ?", 1).Scan(&titles).Error; err != nil {
+		t.Errorf("allowed raw query: %v", err)
+	}
+	if err := db.Raw("SELECT title FROM notes").Scan(&titles).Error; err != nil || len(titles) != 2 {
+		t.Errorf("raw query outside a workspace: got %v, %v", titles, err)
+	}
+}
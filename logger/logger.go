@@ -12,8 +12,13 @@ type Logger struct {
 	logInstance zerolog.Logger
 }
 
-func CreateLogger(logContext string) *Logger {
+// TimeFieldFormat is global, so it is set once here rather than by every
+// logger created while requests are being served.
+func init() {
 	zerolog.TimeFieldFormat = zerolog.TimeFormatUnix
+}
+
+func CreateLogger(logContext string) *Logger {
 	logInstance := zerolog.New(os.Stdout).With().Timestamp().Str("context", logContext).Logger()
 
 	return &Logger{
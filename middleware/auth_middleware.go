@@ -1,14 +1,10 @@
 package middleware
 
 import (
-	"fmt"
-	"slices"
 	"strings"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/golang-jwt/jwt"
-	"github.com/shivamrajput1826/api-catalog/common"
-	"github.com/shivamrajput1826/api-catalog/config"
 	"github.com/shivamrajput1826/api-catalog/logger"
 )
 
@@ -16,32 +12,41 @@ func AuthMiddleware(c *fiber.Ctx) error {
 	customLogger := logger.CreateLogger("AuthMiddleware").WithFiberContext(c)
 	authHeader := c.Get("Authorization")
 	clientId := c.Get("client-id")
-	secret := config.GetConfigValue("JWT_SECRET")
-	if secret == "" || authHeader == "" || clientId == "" {
+	if verifier == nil || authHeader == "" || clientId == "" {
 		customLogger.Debug("Missing required authentication parameters")
 		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
 			"error": "Unauthorized",
 		})
 	}
 	tokenString := strings.TrimPrefix(authHeader, "Bearer ")
-	token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
-		}
-		return []byte(secret), nil
-	})
+	claims, err := verifier.Verify(tokenString)
 	if err != nil {
-		customLogger.Debug("Invalid token", "error", err)
+		customLogger.Debug("Invalid token", "error", err.Error())
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Unauthorized",
+		})
 	}
-	claims, ok := token.Claims.(jwt.MapClaims)
-	if !ok || !token.Valid || (!slices.Contains(common.ClientIdsConfig, clientId)) || claims["user_id"] == "" {
-		customLogger.Debug("Invalid token", "error", err)
+	userID := subject(claims)
+	if userID == nil {
+		customLogger.Debug("Token has no user_id or sub claim")
 		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
 			"error": "Unauthorized",
 		})
 	}
-	c.Locals("user_id", claims["user_id"])
+	c.Locals("user_id", userID)
 	c.Locals("email", claims["email"])
 	c.Locals("role", claims["role"])
+	setUser(c, userID, claims["email"])
 	return c.Next()
 }
+
+// subject returns the user the token was issued to: the user_id claim of
+// tokens we sign ourselves, or the standard sub claim of identity providers.
+func subject(claims jwt.MapClaims) interface{} {
+	for _, name := range []string{"user_id", "sub"} {
+		if value, ok := claims[name]; ok && value != nil && value != "" {
+			return value
+		}
+	}
+	return nil
+}
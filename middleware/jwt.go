@@ -0,0 +1,454 @@
+package middleware
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt"
+	"github.com/shivamrajput1826/api-catalog/config"
+)
+
+const (
+	defaultJWTAlgorithms = "HS256"
+	defaultJWKSCacheTTL  = 10 * time.Minute
+	defaultClockSkew     = 30 * time.Second
+
+	// jwksMinRefresh limits how often an unknown key id triggers a refetch of
+	// the JWKS URL, so tokens with made-up key ids cannot flood the provider.
+	jwksMinRefresh = time.Minute
+	jwksTimeout    = 10 * time.Second
+	// jwksRetryBackoff is the wait after a first failed fetch. It doubles
+	// with every further failure, up to jwksMinRefresh.
+	jwksRetryBackoff = time.Second
+)
+
+// JWTVerifier checks the signature and standard claims of bearer tokens.
+// HMAC algorithms use JWT_SECRET; RSA and ECDSA algorithms use the public keys
+// of a JWKS file or URL, picked by the token's kid.
+type JWTVerifier struct {
+	parser     *jwt.Parser
+	secret     []byte
+	keys       *jwks
+	issuer     string
+	audience   string
+	clockSkew  time.Duration
+	requireExp bool
+}
+
+var verifier *JWTVerifier
+
+// LoadJWTVerifier builds the token verifier used by AuthMiddleware from the
+// configuration. It must be called once at startup, after config.LoadConfig.
+//
+//	JWT_ALGORITHMS    accepted algorithms, comma separated (default HS256)
+//	JWT_SECRET        shared secret for HS256, HS384 and HS512
+//	JWT_JWKS_FILE     local JWKS file with the public keys
+//	JWT_JWKS_URL      JWKS URL, used when no file is set
+//	JWT_JWKS_CACHE_TTL  how long keys fetched from the URL are used (default 10m)
+//	JWT_ISSUER        required iss claim
+//	JWT_AUDIENCE      required aud claim
+//	JWT_CLOCK_SKEW    tolerance for exp, nbf and iat (default 30s)
+//	JWT_REQUIRE_EXP   reject tokens without exp (default true when RSA or
+//	                  ECDSA algorithms are accepted, false otherwise)
+func LoadJWTVerifier() error {
+	algorithms := config.GetConfigValue("JWT_ALGORITHMS")
+	if algorithms == "" {
+		algorithms = defaultJWTAlgorithms
+	}
+
+	v := &JWTVerifier{
+		parser:   &jwt.Parser{SkipClaimsValidation: true},
+		secret:   []byte(config.GetConfigValue("JWT_SECRET")),
+		issuer:   config.GetConfigValue("JWT_ISSUER"),
+		audience: config.GetConfigValue("JWT_AUDIENCE"),
+	}
+
+	needsSecret, needsKeys := false, false
+	for _, alg := range strings.Split(algorithms, ",") {
+		alg = strings.TrimSpace(alg)
+		switch jwt.GetSigningMethod(alg).(type) {
+		case *jwt.SigningMethodHMAC:
+			needsSecret = true
+		case *jwt.SigningMethodRSA, *jwt.SigningMethodRSAPSS, *jwt.SigningMethodECDSA:
+			needsKeys = true
+		default:
+			return fmt.Errorf("unsupported JWT algorithm %q", alg)
+		}
+		v.parser.ValidMethods = append(v.parser.ValidMethods, alg)
+	}
+	if needsSecret && len(v.secret) == 0 {
+		return errors.New("JWT_SECRET is required for HMAC algorithms")
+	}
+
+	var err error
+	if v.clockSkew, err = durationConfig("JWT_CLOCK_SKEW", defaultClockSkew); err != nil {
+		return err
+	}
+	// A token without exp stays valid forever once leaked. Identity
+	// providers always set it, so only tokens we sign ourselves may omit it.
+	if v.requireExp, err = boolConfig("JWT_REQUIRE_EXP", needsKeys); err != nil {
+		return err
+	}
+
+	if needsKeys {
+		ttl, err := durationConfig("JWT_JWKS_CACHE_TTL", defaultJWKSCacheTTL)
+		if err != nil {
+			return err
+		}
+		v.keys = &jwks{
+			file: config.GetConfigValue("JWT_JWKS_FILE"),
+			url:  config.GetConfigValue("JWT_JWKS_URL"),
+			ttl:  ttl,
+		}
+		if v.keys.file == "" && v.keys.url == "" {
+			return errors.New("JWT_JWKS_FILE or JWT_JWKS_URL is required for RSA and ECDSA algorithms")
+		}
+		// A local file cannot change without a restart, so read it once and
+		// fail at startup when it is broken.
+		if v.keys.file != "" {
+			if v.keys.keys, err = v.keys.load(); err != nil {
+				return err
+			}
+		}
+	}
+
+	verifier = v
+	return nil
+}
+
+func durationConfig(key string, fallback time.Duration) (time.Duration, error) {
+	value := config.GetConfigValue(key)
+	if value == "" {
+		return fallback, nil
+	}
+	duration, err := time.ParseDuration(value)
+	if err != nil || duration < 0 {
+		return 0, fmt.Errorf("%s must be a duration such as 30s, got %q", key, value)
+	}
+	return duration, nil
+}
+
+func boolConfig(key string, fallback bool) (bool, error) {
+	value := config.GetConfigValue(key)
+	if value == "" {
+		return fallback, nil
+	}
+	enabled, err := strconv.ParseBool(value)
+	if err != nil {
+		return false, fmt.Errorf("%s must be true or false, got %q", key, value)
+	}
+	return enabled, nil
+}
+
+// Verify parses a token, checks its signature and validates exp, nbf and iat
+// within the clock skew, and iss and aud when they are configured. exp must
+// be present when JWT_REQUIRE_EXP is on.
+func (v *JWTVerifier) Verify(tokenString string) (jwt.MapClaims, error) {
+	token, err := v.parser.Parse(tokenString, v.key)
+	if err != nil {
+		return nil, err
+	}
+	claims, ok := token.Claims.(jwt.MapClaims)
+	if !ok || !token.Valid {
+		return nil, errors.New("invalid token")
+	}
+
+	now := time.Now()
+	skew := int64(v.clockSkew / time.Second)
+	switch {
+	case v.requireExp && claims["exp"] == nil:
+		return nil, errors.New("token has no exp claim")
+	case !claims.VerifyExpiresAt(now.Unix()-skew, false):
+		return nil, errors.New("token is expired")
+	case !claims.VerifyNotBefore(now.Unix()+skew, false):
+		return nil, errors.New("token is not valid yet")
+	case !claims.VerifyIssuedAt(now.Unix()+skew, false):
+		return nil, errors.New("token was issued in the future")
+	case v.issuer != "" && !claims.VerifyIssuer(v.issuer, true):
+		return nil, fmt.Errorf("token issuer is not %s", v.issuer)
+	case v.audience != "" && !claims.VerifyAudience(v.audience, true):
+		return nil, fmt.Errorf("token audience does not include %s", v.audience)
+	}
+	return claims, nil
+}
+
+// key returns the verification key for a token. The parser has already
+// checked the algorithm against the accepted ones, and each signing method
+// only accepts its own key type, so an HMAC token can never be checked
+// against a public key.
+func (v *JWTVerifier) key(token *jwt.Token) (interface{}, error) {
+	if _, ok := token.Method.(*jwt.SigningMethodHMAC); ok {
+		return v.secret, nil
+	}
+	if v.keys == nil {
+		return nil, fmt.Errorf("no public keys configured for %s", token.Method.Alg())
+	}
+	kid, _ := token.Header["kid"].(string)
+	return v.keys.lookup(kid, token.Method.Alg())
+}
+
+// jwks holds the public keys of a JSON Web Key Set. Keys from a URL are
+// refetched once the TTL has passed, or when a token names an unknown key.
+// Fetches run without holding mu, and concurrent lookups share the fetch in
+// flight. After a failed fetch, no new one starts before retryAt.
+type jwks struct {
+	file string
+	url  string
+	ttl  time.Duration
+
+	mu        sync.Mutex
+	keys      []publicKey
+	fetchedAt time.Time
+	fetching  chan struct{}
+	failures  int
+	retryAt   time.Time
+	fetchErr  error
+}
+
+type publicKey struct {
+	kid string
+	alg string
+	key interface{}
+}
+
+type jsonWebKey struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+func (s *jwks) lookup(kid, alg string) (interface{}, error) {
+	s.mu.Lock()
+	key, err := s.find(kid, alg)
+	if s.url == "" || s.file != "" {
+		s.mu.Unlock()
+		return key, err
+	}
+
+	now := time.Now()
+	age := now.Sub(s.fetchedAt)
+	stale := s.keys == nil || age > s.ttl || (err != nil && age > jwksMinRefresh)
+	if !stale || now.Before(s.retryAt) {
+		if s.keys == nil && s.fetchErr != nil {
+			err = s.fetchErr
+		}
+		s.mu.Unlock()
+		return key, err
+	}
+	done := s.startFetch()
+	s.mu.Unlock()
+
+	// Keys past their TTL that still know the token's key stay in use while
+	// the fetch runs, and when the provider is unreachable.
+	if err == nil {
+		return key, nil
+	}
+	<-done
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.keys == nil {
+		return nil, s.fetchErr
+	}
+	return s.find(kid, alg)
+}
+
+// startFetch starts fetching the key set from the URL unless a fetch is
+// already in flight, and returns a channel closed when it finishes. The
+// caller must hold s.mu.
+func (s *jwks) startFetch() <-chan struct{} {
+	if s.fetching != nil {
+		return s.fetching
+	}
+	done := make(chan struct{})
+	s.fetching = done
+	go func() {
+		keys, err := s.load()
+
+		s.mu.Lock()
+		now := time.Now()
+		s.fetchedAt = now
+		if err != nil {
+			s.failures++
+			s.fetchErr = err
+			s.retryAt = now.Add(retryBackoff(s.failures))
+		} else {
+			s.keys = keys
+			s.failures = 0
+			s.fetchErr = nil
+			s.retryAt = time.Time{}
+		}
+		s.fetching = nil
+		s.mu.Unlock()
+		close(done)
+	}()
+	return done
+}
+
+func retryBackoff(failures int) time.Duration {
+	backoff := jwksRetryBackoff
+	for i := 1; i < failures && backoff < jwksMinRefresh; i++ {
+		backoff *= 2
+	}
+	return min(backoff, jwksMinRefresh)
+}
+
+// find picks the key with the token's kid. A token without a kid may use the
+// only key that fits its algorithm.
+func (s *jwks) find(kid, alg string) (interface{}, error) {
+	var match *publicKey
+	for i, key := range s.keys {
+		if key.alg != "" && key.alg != alg {
+			continue
+		}
+		if !keyFits(key.key, alg) {
+			continue
+		}
+		if kid != "" {
+			if key.kid == kid {
+				return key.key, nil
+			}
+			continue
+		}
+		if match != nil {
+			return nil, errors.New("token has no kid and several keys match")
+		}
+		match = &s.keys[i]
+	}
+	if match == nil {
+		return nil, fmt.Errorf("no key found for kid %q", kid)
+	}
+	return match.key, nil
+}
+
+func keyFits(key interface{}, alg string) bool {
+	switch key.(type) {
+	case *rsa.PublicKey:
+		return strings.HasPrefix(alg, "RS") || strings.HasPrefix(alg, "PS")
+	case *ecdsa.PublicKey:
+		return strings.HasPrefix(alg, "ES")
+	}
+	return false
+}
+
+// load reads the key set from the file or URL. It does not touch the cached
+// keys, so it runs without s.mu.
+func (s *jwks) load() ([]publicKey, error) {
+	var body []byte
+	var err error
+	if s.file != "" {
+		body, err = os.ReadFile(s.file)
+	} else {
+		body, err = fetchJWKS(s.url)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load JWKS: %w", err)
+	}
+
+	var set struct {
+		Keys []jsonWebKey `json:"keys"`
+	}
+	if err := json.Unmarshal(body, &set); err != nil {
+		return nil, fmt.Errorf("invalid JWKS: %w", err)
+	}
+
+	keys := make([]publicKey, 0, len(set.Keys))
+	for _, jwk := range set.Keys {
+		if jwk.Use != "" && jwk.Use != "sig" {
+			continue
+		}
+		key, err := jwk.publicKey()
+		if err != nil {
+			return nil, fmt.Errorf("invalid JWKS key %q: %w", jwk.Kid, err)
+		}
+		if key == nil {
+			continue
+		}
+		keys = append(keys, publicKey{kid: jwk.Kid, alg: jwk.Alg, key: key})
+	}
+	return keys, nil
+}
+
+func fetchJWKS(url string) ([]byte, error) {
+	client := http.Client{Timeout: jwksTimeout}
+	resp, err := client.Get(url)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("GET %s returned %s", url, resp.Status)
+	}
+	return io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+}
+
+// publicKey decodes an RSA or EC key. Other key types are skipped.
+func (k jsonWebKey) publicKey() (interface{}, error) {
+	switch k.Kty {
+	case "RSA":
+		n, err := base64URLInt(k.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := base64URLInt(k.E)
+		if err != nil {
+			return nil, err
+		}
+		if !e.IsInt64() || e.Int64() < 3 || e.Int64() > 1<<31-1 {
+			return nil, errors.New("invalid RSA exponent")
+		}
+		return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+	case "EC":
+		var curve elliptic.Curve
+		switch k.Crv {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, fmt.Errorf("unsupported curve %q", k.Crv)
+		}
+		x, err := base64URLInt(k.X)
+		if err != nil {
+			return nil, err
+		}
+		y, err := base64URLInt(k.Y)
+		if err != nil {
+			return nil, err
+		}
+		if !curve.IsOnCurve(x, y) {
+			return nil, errors.New("point is not on the curve")
+		}
+		return &ecdsa.PublicKey{Curve: curve, X: x, Y: y}, nil
+	}
+	return nil, nil
+}
+
+func base64URLInt(value string) (*big.Int, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(value, "="))
+	if err != nil || len(raw) == 0 {
+		return nil, errors.New("invalid base64url integer")
+	}
+	return new(big.Int).SetBytes(raw), nil
+}
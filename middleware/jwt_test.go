@@ -0,0 +1,158 @@
+package middleware
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt"
+	"github.com/spf13/viper"
+)
+
+func jwksServer(t *testing.T, handler http.HandlerFunc) (*httptest.Server, *atomic.Int32) {
+	t.Helper()
+	var hits atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+		handler(w, r)
+	}))
+	t.Cleanup(server.Close)
+	return server, &hits
+}
+
+func rsaKeySet(t *testing.T, kid string) []byte {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, err := json.Marshal(map[string]interface{}{"keys": []jsonWebKey{{
+		Kty: "RSA",
+		Kid: kid,
+		N:   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+		E:   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+	}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return body
+}
+
+func TestJWKSLookupSharesOneFetch(t *testing.T) {
+	body := rsaKeySet(t, "current")
+	release := make(chan struct{})
+	server, hits := jwksServer(t, func(w http.ResponseWriter, r *http.Request) {
+		<-release
+		w.Write(body)
+	})
+	keys := &jwks{url: server.URL, ttl: time.Hour}
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 10)
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := keys.lookup("current", "RS256")
+			errs <- err
+		}()
+	}
+	for hits.Load() == 0 {
+		time.Sleep(time.Millisecond)
+	}
+	// The fetch is blocked in the handler and must not hold the mutex.
+	if !keys.mu.TryLock() {
+		t.Fatal("the mutex is held during the fetch")
+	}
+	keys.mu.Unlock()
+	close(release)
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	if n := hits.Load(); n != 1 {
+		t.Fatalf("got %d fetches, want 1", n)
+	}
+}
+
+func TestJWKSLookupBacksOffAfterFailure(t *testing.T) {
+	server, hits := jwksServer(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	})
+	keys := &jwks{url: server.URL, ttl: time.Hour}
+
+	for i := 0; i < 5; i++ {
+		if _, err := keys.lookup("current", "RS256"); err == nil {
+			t.Fatal("expected an error")
+		}
+	}
+	if n := hits.Load(); n != 1 {
+		t.Fatalf("got %d fetches, want 1", n)
+	}
+
+	keys.mu.Lock()
+	keys.retryAt = time.Now().Add(-time.Second)
+	keys.mu.Unlock()
+	if _, err := keys.lookup("current", "RS256"); err == nil {
+		t.Fatal("expected an error")
+	}
+	if n := hits.Load(); n != 2 {
+		t.Fatalf("got %d fetches after the backoff, want 2", n)
+	}
+	if backoff := time.Until(keys.retryAt); backoff <= jwksRetryBackoff {
+		t.Errorf("backoff did not grow: %s", backoff)
+	}
+}
+
+func TestVerifyRequiresExp(t *testing.T) {
+	t.Cleanup(func() {
+		viper.Set("JWT_SECRET", "")
+		viper.Set("JWT_REQUIRE_EXP", "")
+	})
+	viper.Set("JWT_SECRET", "secret")
+	sign := func(claims jwt.MapClaims) string {
+		token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte("secret"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return token
+	}
+	withoutExp := sign(jwt.MapClaims{"user_id": "1"})
+	withExp := sign(jwt.MapClaims{"user_id": "1", "exp": time.Now().Add(time.Minute).Unix()})
+
+	for _, tc := range []struct {
+		requireExp string
+		token      string
+		valid      bool
+	}{
+		{"", withoutExp, true},
+		{"false", withoutExp, true},
+		{"true", withoutExp, false},
+		{"true", withExp, true},
+	} {
+		viper.Set("JWT_REQUIRE_EXP", tc.requireExp)
+		if err := LoadJWTVerifier(); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := verifier.Verify(tc.token); (err == nil) != tc.valid {
+			t.Errorf("JWT_REQUIRE_EXP=%q: got error %v, want valid %v", tc.requireExp, err, tc.valid)
+		}
+	}
+
+	viper.Set("JWT_REQUIRE_EXP", "sometimes")
+	if err := LoadJWTVerifier(); err == nil {
+		t.Error("expected an invalid JWT_REQUIRE_EXP to be rejected")
+	}
+}
@@ -0,0 +1,87 @@
+package middleware
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/shivamrajput1826/api-catalog/logger"
+)
+
+const (
+	RequestIDHeader = "X-Request-ID"
+
+	maxRequestIDLength = 128
+)
+
+// RequestContextMiddleware tags every request with an id, taken from the
+// X-Request-ID header when the caller sent a usable one and generated
+// otherwise, and echoes it in the response. It stores the keys the logger
+// reads, so every log line of the request carries the id and, once
+// AuthMiddleware ran, the user. After the request it writes one access-log
+// line. It should be the first middleware.
+func RequestContextMiddleware(c *fiber.Ctx) error {
+	start := time.Now()
+
+	requestID := c.Get(RequestIDHeader)
+	if !validRequestID(requestID) {
+		requestID = uuid.NewString()
+	}
+	c.Set(RequestIDHeader, requestID)
+	c.Locals("requestId", requestID)
+	c.SetUserContext(context.WithValue(c.UserContext(), "requestCtx", map[string]interface{}{
+		"requestId": requestID,
+		"userId":    "",
+		"userEmail": "",
+	}))
+
+	// Render errors here rather than in the app's error handler so the
+	// access log sees the status that is actually sent.
+	if err := c.Next(); err != nil {
+		if err := c.App().ErrorHandler(c, err); err != nil {
+			_ = c.SendStatus(fiber.StatusInternalServerError)
+		}
+	}
+
+	logger.CreateLogger("AccessLog").WithFiberContext(c).Info("request completed",
+		"method", c.Method(),
+		"path", c.Path(),
+		"route", c.Route().Path,
+		"status", c.Response().StatusCode(),
+		"latency_ms", float64(time.Since(start).Microseconds())/1000,
+		"bytes_in", len(c.Request().Body()),
+		"bytes_out", len(c.Response().Body()),
+		"ip", c.IP(),
+	)
+	return nil
+}
+
+// setUser stores the authenticated user under the keys the logger reads, in
+// the fiber locals and in the request context.
+func setUser(c *fiber.Ctx, userID, email interface{}) {
+	id := fmt.Sprint(userID)
+	userEmail, _ := email.(string)
+
+	c.Locals("userId", id)
+	c.Locals("userEmail", userEmail)
+	if requestCtx, ok := c.UserContext().Value("requestCtx").(map[string]interface{}); ok {
+		requestCtx["userId"] = id
+		requestCtx["userEmail"] = userEmail
+	}
+}
+
+// validRequestID accepts ids of printable ASCII without spaces, so they can
+// be echoed and logged safely.
+func validRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLength {
+		return false
+	}
+	for i := 0; i < len(id); i++ {
+		if id[i] <= ' ' || id[i] > '~' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,48 @@
+package middleware
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/shivamrajput1826/api-catalog/common"
+	"github.com/shivamrajput1826/api-catalog/logger"
+)
+
+// RequireRole only lets a request through when the role claim stored by
+// AuthMiddleware ranks at least as high as role. It must run after
+// AuthMiddleware.
+func RequireRole(role string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		current, _ := c.Locals("role").(string)
+		if common.RoleRanks[current] >= common.RoleRanks[role] && common.RoleRanks[current] > 0 {
+			return c.Next()
+		}
+
+		customLogger := logger.CreateLogger("RoleMiddleware").WithFiberContext(c)
+		customLogger.Debug("Missing required role", "required", role, "role", current)
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+			"error":              fmt.Sprintf("Forbidden: this action requires the %s role", role),
+			"missing_permission": role,
+		})
+	}
+}
+
+// RequireScope only lets a request through when the API client it was made
+// with was granted scope. The scopes are stored by the client check that runs
+// after AuthMiddleware.
+func RequireScope(scope string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		scopes, _ := c.Locals("scopes").([]string)
+		if slices.Contains(scopes, scope) {
+			return c.Next()
+		}
+
+		customLogger := logger.CreateLogger("RoleMiddleware").WithFiberContext(c)
+		customLogger.Debug("Missing required scope", "required", scope, "scopes", scopes)
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+			"error":              fmt.Sprintf("Forbidden: this action requires the %s scope", scope),
+			"missing_permission": scope,
+		})
+	}
+}
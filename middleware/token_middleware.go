@@ -0,0 +1,27 @@
+package middleware
+
+import (
+	"crypto/subtle"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/shivamrajput1826/api-catalog/logger"
+)
+
+// RequireBearerToken only lets a request through when its Authorization
+// header carries token as a bearer token. It guards endpoints meant for
+// infrastructure rather than users, such as /metrics.
+func RequireBearerToken(token string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		sent, ok := strings.CutPrefix(c.Get("Authorization"), "Bearer ")
+		if ok && subtle.ConstantTimeCompare([]byte(sent), []byte(token)) == 1 {
+			return c.Next()
+		}
+
+		customLogger := logger.CreateLogger("TokenMiddleware").WithFiberContext(c)
+		customLogger.Debug("Missing or invalid bearer token")
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Unauthorized",
+		})
+	}
+}